@@ -6,6 +6,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/rodaine/table"
 	"github.com/spf13/cobra"
@@ -33,6 +34,12 @@ var (
   %[1]s %[2]s %[3]s --account 2451251 scan all AMIs of self and were AWS account 2451251 are owner  
   %[1]s %[2]s %[3]s --dry-run         do not delete anything just show what you would do
   %[1]s %[2]s %[3]s --older-then      5w delete all images which are older then 5w and are unused
+  %[1]s %[2]s %[3]s --regions all     delete unused images in all enabled regions
+  %[1]s %[2]s %[3]s --delete-snapshots delete unused images and their EBS snapshots
+  %[1]s %[2]s %[3]s --name-pattern 'build-*' --tag Team=ci delete unused images of the CI builds
+  %[1]s %[2]s %[3]s --keep-newest 3 --family-tag Family always keep the 3 newest images of each family
+  %[1]s %[2]s %[3]s --not-launched-for 30d also keep images which were launched within the last 30d
+  %[1]s %[2]s %[3]s --strategy deprecate --grace-period 30d deprecate unused images and deregister them 30d later
   %[1]s %[2]s %[3]s --help            show help for this sub-command
 	`,
 		binaryname,
@@ -41,6 +48,8 @@ var (
 	amiListCmdExamples = fmt.Sprintf(`
   %[1]s %[2]s %[3]s --account 2451251 scan all AMIs of self and were AWS account 2451251 are owner  
   %[1]s %[2]s %[3]s --dry-run         do not delete anything just show what you would do 
+  %[1]s %[2]s %[3]s --regions eu-central-1,us-east-1 list AMIs of both regions
+  %[1]s %[2]s %[3]s --launch-templates=all also keep images of any launch template version
   %[1]s %[2]s %[3]s --help            show help for this sub-command
 	`,
 		binaryname,
@@ -74,24 +83,36 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
 
-		awsClient := internal.NewAWSClient()
+		useLaunchTpls, launchTplVersions := amiLaunchTpls()
 
-		amiclean := amiclean.NewInstance(awsClient,
-			olderthenDuration,
-			viper.GetString(accountFlag),
-			viper.GetBool(dryrunFlag),
-			viper.GetBool(onlyUnusedFlag),
-			viper.GetBool(launchTplFlag),
-			viper.GetStringSlice(ignoreFlag))
+		amis := []amiOutput{}
+		for _, awsClient := range awsClients() {
+			amiclean := amiclean.NewInstance(awsClient,
+				olderthenDuration,
+				viper.GetString(accountFlag),
+				viper.GetBool(dryrunFlag),
+				viper.GetBool(onlyUnusedFlag),
+				useLaunchTpls,
+				viper.GetStringSlice(ignoreFlag),
+				launchTplVersions,
+				amiclean.WithFilters(amiFilters()),
+				amiRetention(),
+				amiSoftDelete(),
+				amiNotLaunchedFor())
 
-		err := amiclean.GetAMIs()
-		eslog.LogIfErrorf(err, eslog.Fatalf, "amiclean.GetAMIs() failed: %s")
+			err := amiclean.GetAMIs()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "amiclean.GetAMIs() failed: %s")
+
+			for _, ami := range amiclean.GetAllAMIs() {
+				amis = append(amis, amiOutput{origin: newOrigin(awsClient), Image: ami, Decision: amiclean.GetDecision(*ami.ImageId)})
+			}
+		}
 
 		switch viper.GetString(outputFlag) {
 		case "json", "JSON":
-			amiPrintJSON(amiclean.GetAllAMIs())
+			amiPrintJSON(amis)
 		default:
-			amiPrintTable(amiclean.GetAllAMIs())
+			amiPrintTable(amis)
 		}
 	},
 }
@@ -111,18 +132,28 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
 
-		awsClient := internal.NewAWSClient()
+		useLaunchTpls, launchTplVersions := amiLaunchTpls()
+
+		for _, awsClient := range awsClients() {
+			eslog.Logger.Infof("Cleanup AMIs in %s", newOrigin(awsClient))
 
-		amiclean := amiclean.NewInstance(awsClient,
-			olderthenDuration,
-			viper.GetString(accountFlag),
-			viper.GetBool(dryrunFlag),
-			viper.GetBool(onlyUnusedFlag),
-			viper.GetBool(launchTplFlag),
-			viper.GetStringSlice(ignoreFlag))
+			amiclean := amiclean.NewInstance(awsClient,
+				olderthenDuration,
+				viper.GetString(accountFlag),
+				viper.GetBool(dryrunFlag),
+				viper.GetBool(onlyUnusedFlag),
+				useLaunchTpls,
+				viper.GetStringSlice(ignoreFlag),
+				launchTplVersions,
+				amiclean.WithFilters(amiFilters()),
+				amiRetention(),
+				amiSoftDelete(),
+				amiNotLaunchedFor(),
+				amiclean.WithDeleteSnapshots(viper.GetBool(deleteSnapshotFlag)))
 
-		err := amiclean.DeleteOlderUnusedAMIs()
-		eslog.LogIfErrorf(err, eslog.Fatalf, "amiclean.DeleteOlderUnusedAMIs() failed: %s")
+			err := amiclean.DeleteOlderUnusedAMIs()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "amiclean.DeleteOlderUnusedAMIs() failed: %s")
+		}
 	},
 }
 
@@ -134,14 +165,26 @@ func amiBindFlags() {
 	const objType = "AMIs"
 	amiDeleteCmdFlags := amiDeleteCmd.Flags()
 	amiDeleteCmdFlags.StringArrayP(ignoreFlag, ignoreFlagSH, []string{}, "Set ignore regex patterns. If a ami name matches the pattern it will be exclueded from cleanup.")
+	amiDeleteCmdFlags.Bool(deleteSnapshotFlag, false, "Additionally delete the EBS snapshots of deregistered AMIs. Snapshots which are still used by another AMI are kept.")
 	deleteOnlyFlags(amiDeleteCmdFlags)
 
 	amiListCmdFlags := amiListCmd.Flags()
 	listOnlyFlags(amiListCmdFlags, objType)
 
 	amiCmdPersistentFlags := amiCmd.PersistentFlags()
-	amiCmdPersistentFlags.BoolP(launchTplFlag, launchTplFlagSH, false, "Additionally scan launch templates for used AMIs.")
+	amiCmdPersistentFlags.StringP(launchTplFlag, launchTplFlagSH, "", "Additionally scan launch templates for used AMIs. Set which versions are scanned: latest, default, all or referenced (the default versions and the versions instances were launched from). The versions pinned by Auto Scaling groups are always scanned.")
+	amiCmdPersistentFlags.Lookup(launchTplFlag).NoOptDefVal = string(internal.LAUNCH_TPL_LATEST)
 	amiCmdPersistentFlags.StringP(accountFlag, accountFlagSH, "", "Set AWS account number to cleanup AMIs. Used to set owner information when selecting AMIs. If not set only 'self' is used.")
+	amiCmdPersistentFlags.StringArray(namePatternFlag, []string{}, "Only select AMIs which name matches the pattern. Wildcards * and ? can be used. The filter is applied by AWS.")
+	amiCmdPersistentFlags.StringSlice(architectureFlag, []string{}, "Only select AMIs with the given architectures (e.g. x86_64,arm64).")
+	amiCmdPersistentFlags.StringArray(tagFlag, []string{}, "Only select AMIs with the given tag. Use key=value to match the value or just key to match all AMIs having the tag.")
+	amiCmdPersistentFlags.StringSlice(stateFlag, []string{}, "Only select AMIs in the given states (e.g. available,failed).")
+	amiCmdPersistentFlags.Int(keepNewestFlag, 0, fmt.Sprintf("Always keep the newest N AMIs of each family regardless of their age. The family is defined by --%s or --%s.", familyPatternFlag, familyTagFlag))
+	amiCmdPersistentFlags.String(familyPatternFlag, "", "Set a regex which is matched against the AMI name. The first capture group (or the whole match) is used as family.")
+	amiCmdPersistentFlags.String(familyTagFlag, "", "Set a tag key (e.g. Family). The tag value is used as family.")
+	amiCmdPersistentFlags.String(notLaunchedForFlag, "", "Set the duration string (e.g 5d, 1w etc.) for which an AMI must not have been used to launch an instance to be deleted. If not set only the creation date is considered.")
+	amiCmdPersistentFlags.String(strategyFlag, string(amiclean.STRATEGY_DEREGISTER), fmt.Sprintf("Set how unused AMIs are removed: deregister them immediately or deprecate or disable them first and deregister them after --%s. Disabled AMIs are only listed with the disable strategy.", gracePeriodFlag))
+	amiCmdPersistentFlags.String(gracePeriodFlag, "14d", "Set the duration string (e.g 5d, 1w etc.) how long AMIs stay deprecated or disabled before they are deregistered.")
 
 	err := viper.BindPFlags(amiCmdPersistentFlags)
 	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
@@ -153,16 +196,61 @@ func amiBindFlags() {
 	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
 }
 
-func amiPrintTable(amis []ec2Types.Image) {
-	grpsTable := table.New("ID", "Name", "Creation DateTime")
+func amiFilters() []ec2Types.Filter {
+	return amiclean.ImageFilters(
+		viper.GetStringSlice(namePatternFlag),
+		viper.GetStringSlice(architectureFlag),
+		viper.GetStringSlice(stateFlag),
+		viper.GetStringSlice(tagFlag))
+}
+
+func amiRetention() amiclean.Option {
+	keepNewest := viper.GetInt(keepNewestFlag)
+	familyPattern := viper.GetString(familyPatternFlag)
+	familyTag := viper.GetString(familyTagFlag)
+
+	if keepNewest > 0 && familyPattern == "" && familyTag == "" {
+		eslog.Fatalf("--%s requires --%s or --%s", keepNewestFlag, familyPatternFlag, familyTagFlag)
+	}
+	return amiclean.WithRetention(keepNewest, familyPattern, familyTag)
+}
+
+func amiLaunchTpls() (bool, amiclean.Option) {
+	versions, enabled, err := internal.ParseLaunchTplVersions(viper.GetString(launchTplFlag))
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Invalid --%s: %s", launchTplFlag, err)
+	return enabled, amiclean.WithLaunchTplVersions(versions)
+}
+
+func amiSoftDelete() amiclean.Option {
+	strategy, err := amiclean.ParseStrategy(viper.GetString(strategyFlag))
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Invalid --%s: %s", strategyFlag, err)
+	return amiclean.WithSoftDelete(strategy, internal.ParseDuration(viper.GetString(gracePeriodFlag)))
+}
+
+func amiNotLaunchedFor() amiclean.Option {
+	var notLaunchedFor time.Duration
+	if viper.GetString(notLaunchedForFlag) != "" {
+		notLaunchedFor = internal.ParseDuration(viper.GetString(notLaunchedForFlag))
+	}
+	return amiclean.WithNotLaunchedFor(notLaunchedFor)
+}
+
+type amiOutput struct {
+	origin
+	ec2Types.Image
+	amiclean.Decision
+}
+
+func amiPrintTable(amis []amiOutput) {
+	grpsTable := table.New("Account", "Region", "ID", "Name", "Creation DateTime", "Last Launched", "State", "Action", "Reason")
 	for _, ami := range amis {
 		// TODO: Conditionally add ami.tags here.
-		grpsTable.AddRow(*ami.ImageId, *ami.Name, *ami.CreationDate)
+		grpsTable.AddRow(ami.Account, ami.Region, *ami.ImageId, *ami.Name, *ami.CreationDate, nilCheck(ami.LastLaunchedTime), amiclean.ImageState(ami.Image), ami.Action, ami.Reason)
 	}
 	grpsTable.Print()
 }
 
-func amiPrintJSON(amis []ec2Types.Image) {
+func amiPrintJSON(amis []amiOutput) {
 	out, err := json.Marshal(amis)
 	eslog.LogIfErrorf(err, eslog.Fatalf, "Json.Marshal(amis) failed: %s", err)
 	fmt.Print(string(out))
@@ -74,10 +74,12 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
 
-		awsClient := internal.NewAWSClient()
-		ebsclean := ebsclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag))
+		for _, awsClient := range awsClients() {
+			eslog.Logger.Infof("Cleanup EBS volumes in %s", newOrigin(awsClient))
 
-		ebsclean.DeleteUnusedEBSVolumes()
+			ebsclean := ebsclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag))
+			ebsclean.DeleteUnusedEBSVolumes()
+		}
 	},
 }
 
@@ -93,16 +95,22 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
 
-		awsClient := internal.NewAWSClient()
-		ebsclean := ebsclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag))
+		vols := []ebsOutput{}
+		for _, awsClient := range awsClients() {
+			ebsclean := ebsclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag))
+
+			ebsclean.GetEBSVolumes()
 
-		ebsclean.GetEBSVolumes()
+			for _, vol := range ebsclean.GetAllVolumes() {
+				vols = append(vols, ebsOutput{origin: newOrigin(awsClient), Volume: vol})
+			}
+		}
 
 		switch viper.GetString(outputFlag) {
 		case "json", "JSON":
-			ebsPrintJSON(ebsclean.GetAllVolumes())
+			ebsPrintJSON(vols)
 		default:
-			ebsPrintTable(ebsclean.GetAllVolumes())
+			ebsPrintTable(vols)
 		}
 	},
 }
@@ -127,16 +135,21 @@ func ebsBindFlags() {
 	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %w", err)
 }
 
-func ebsPrintTable(vols []ec2Types.Volume) {
-	grpsTable := table.New("Volume ID", "Creation Datetime", "State")
+type ebsOutput struct {
+	origin
+	ec2Types.Volume
+}
+
+func ebsPrintTable(vols []ebsOutput) {
+	grpsTable := table.New("Account", "Region", "Volume ID", "Creation Datetime", "State")
 	for _, vol := range vols {
 		// TODO: Conditionally add Tags here.
-		grpsTable.AddRow(*vol.VolumeId, vol.CreateTime.Format(time.RFC3339), vol.State)
+		grpsTable.AddRow(vol.Account, vol.Region, *vol.VolumeId, vol.CreateTime.Format(time.RFC3339), vol.State)
 	}
 	grpsTable.Print()
 }
 
-func ebsPrintJSON(vols []ec2Types.Volume) {
+func ebsPrintJSON(vols []ebsOutput) {
 	out, err := json.Marshal(vols)
 	eslog.LogIfErrorf(err, eslog.Fatalf, "Json.Marshal(vols) failed: %s", err)
 	fmt.Print(string(out))
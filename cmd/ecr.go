@@ -0,0 +1,172 @@
+/*
+Copyright © 2026 steffakasid
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/ecrclean"
+	eslog "github.com/steffakasid/eslog"
+)
+
+const (
+	ecrCmdName       = "ecr"
+	ecrListCmdName   = "list"
+	ecrDeleteCmdName = "delete"
+)
+
+var (
+	ecrListCmdAliases   = []string{"ls"}
+	ecrDeleteCmdAliases = []string{"del"}
+)
+
+var (
+	ecrDeleteCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --older-then 30d      delete all unused images which were pushed more then 30d ago
+  %[1]s %[2]s %[3]s --%[4]s 10      keep the 10 newest tagged images of each repository
+  %[1]s %[2]s %[3]s --dry-run             do not delete any image just show what should be done
+	`,
+		binaryname,
+		ecrCmdName,
+		ecrDeleteCmdName,
+		keepNewestFlag)
+	ecrListCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --only-unused         list only unused images
+  %[1]s %[2]s %[4]s --output json          list all images as JSON
+	`,
+		binaryname,
+		ecrCmdName,
+		ecrListCmdName,
+		ecrListCmdAliases[0])
+)
+
+// ecrCmd represents the ecr command
+var ecrCmd = &cobra.Command{
+	Use:   ecrCmdName,
+	Short: "Cleanup unused ECR images",
+	Long: fmt.Sprintf(`This tool can be used to list or delete old and unused container images in ECR repositories.
+
+An image is used if its tag or digest is referenced by the task definition of an ECS service or running
+task or if a running task was started with its digest. Images used by other services like EKS are not
+known, keep them with --%s. The newest tagged images of each repository given by --%s are always kept.
+Images referenced by a multi-arch image which is used or pushed after the given duration are kept as well.
+
+Examples:
+%s%s`,
+		keepNewestFlag,
+		keepNewestFlag,
+		ecrDeleteCmdExamples,
+		ecrListCmdExamples),
+}
+
+var ecrListCmd = &cobra.Command{
+	Use:     ecrListCmdName,
+	Aliases: ecrListCmdAliases,
+	Short:   "List ECR images",
+	Long: fmt.Sprintf(`This command can be used to list the images of all ECR repositories. Nothing will be deleted.
+
+Examples:
+%s`,
+		ecrListCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		images := []ecrOutput{}
+		for _, awsClient := range awsClients() {
+			ecrclean := ecrclean.NewInstance(awsClient, olderthenDuration, viper.GetInt(keepNewestFlag), viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag))
+
+			err := ecrclean.GetImages()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "ecrclean.GetImages() failed: %s", err)
+
+			for _, image := range ecrclean.GetAllImages() {
+				images = append(images, ecrOutput{origin: newOrigin(awsClient), Image: image})
+			}
+		}
+
+		switch viper.GetString(outputFlag) {
+		case "json", "JSON":
+			ecrPrintJSON(images)
+		default:
+			ecrPrintTable(images)
+		}
+	},
+}
+
+var ecrDeleteCmd = &cobra.Command{
+	Use:     ecrDeleteCmdName,
+	Aliases: ecrDeleteCmdAliases,
+	Short:   "Delete unused ECR images",
+	Long: fmt.Sprintf(`This command can be used to delete unused images which were pushed before the given duration. Images
+are deleted by digest which removes all their tags. ECR has no dry run, with --%s nothing is called.
+
+Examples:
+%s`,
+		dryrunFlag,
+		ecrDeleteCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		for _, awsClient := range awsClients() {
+			eslog.Logger.Infof("Cleanup ECR images in %s", newOrigin(awsClient))
+
+			ecrclean := ecrclean.NewInstance(awsClient, olderthenDuration, viper.GetInt(keepNewestFlag), viper.GetBool(dryrunFlag), true)
+
+			err := ecrclean.DeleteUnusedImages()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "ecrclean.DeleteUnusedImages() failed: %s", err)
+		}
+	},
+}
+
+func ecrBindFlags() {
+	ecrCmd.AddCommand(ecrDeleteCmd)
+	ecrCmd.AddCommand(ecrListCmd)
+	rootCmd.AddCommand(ecrCmd)
+
+	const objType = "images"
+
+	ecrDeleteCmdFlags := ecrDeleteCmd.Flags()
+	deleteOnlyFlags(ecrDeleteCmdFlags)
+
+	ecrListCmdFlags := ecrListCmd.Flags()
+	ecrListCmdFlags.BoolP(onlyUnusedFlag, onlyUnusedFlagSH, false, "defines if only unused images are listed or all [Default: false]")
+	listOnlyFlags(ecrListCmdFlags, objType)
+
+	ecrCmdPersistentFlags := ecrCmd.PersistentFlags()
+	ecrCmdPersistentFlags.Int(keepNewestFlag, 5, "Always keep the newest N tagged images of each repository regardless of their age.")
+
+	err := viper.BindPFlags(ecrListCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+
+	err = viper.BindPFlags(ecrDeleteCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+
+	err = viper.BindPFlags(ecrCmdPersistentFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+}
+
+type ecrOutput struct {
+	origin
+	ecrclean.Image
+}
+
+func ecrPrintTable(images []ecrOutput) {
+	imageTable := table.New("Account", "Region", "Repository", "Tags", "Digest", "Pushed Datetime", "Size (MB)", "Used by")
+	for _, image := range images {
+		imageTable.AddRow(image.Account, image.Region, aws.ToString(image.RepositoryName), strings.Join(image.ImageTags, ", "), aws.ToString(image.ImageDigest), formatTime(image.ImagePushedAt), aws.ToInt64(image.ImageSizeInBytes)/1024/1024, image.UsedBy)
+	}
+	imageTable.Print()
+}
+
+func ecrPrintJSON(images []ecrOutput) {
+	out, err := json.Marshal(images)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Json.Marshal(images) failed: %s", err)
+	fmt.Print(string(out))
+}
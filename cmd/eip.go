@@ -0,0 +1,168 @@
+/*
+Copyright © 2026 steffakasid
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/eipclean"
+	eslog "github.com/steffakasid/eslog"
+)
+
+const (
+	eipCmdName       = "eip"
+	eipListCmdName   = "list"
+	eipDeleteCmdName = "delete"
+)
+
+var (
+	eipListCmdAliases   = []string{"ls"}
+	eipDeleteCmdAliases = []string{"del", "release"}
+)
+
+var (
+	eipDeleteCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --older-then 1d       release all unassociated Elastic IPs which were allocated more then 1d ago
+  %[1]s %[2]s %[3]s --dry-run             do not release any Elastic IP just show what should be done
+	`,
+		binaryname,
+		eipCmdName,
+		eipDeleteCmdName)
+	eipListCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --only-unused         list only unassociated Elastic IPs
+  %[1]s %[2]s %[4]s --output json          list all Elastic IPs as JSON
+	`,
+		binaryname,
+		eipCmdName,
+		eipListCmdName,
+		eipListCmdAliases[0])
+)
+
+// eipCmd represents the eip command
+var eipCmd = &cobra.Command{
+	Use:   eipCmdName,
+	Short: "Cleanup unassociated Elastic IPs",
+	Long: fmt.Sprintf(`This tool can be used to list or release old and unassociated Elastic IP addresses.
+
+An Elastic IP is unused if it's not associated with an instance or network interface. Unassociated
+Elastic IPs are charged by the hour.
+
+AWS doesn't return when an Elastic IP was allocated, so the allocation time is taken from the AllocateAddress
+events in CloudTrail which only covers the past 90 days.
+
+Examples:
+%s%s`,
+		eipDeleteCmdExamples,
+		eipListCmdExamples),
+}
+
+var eipListCmd = &cobra.Command{
+	Use:     eipListCmdName,
+	Aliases: eipListCmdAliases,
+	Short:   "List Elastic IPs",
+	Long: fmt.Sprintf(`This command can be used to list Elastic IPs. Nothing will be released.
+
+Examples:
+%s`,
+		eipListCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		addresses := []eipOutput{}
+		for _, awsClient := range awsClients() {
+			eipclean := eipclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag))
+
+			err := eipclean.GetAddresses()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "eipclean.GetAddresses() failed: %s", err)
+
+			for _, address := range eipclean.GetAllAddresses() {
+				addresses = append(addresses, eipOutput{origin: newOrigin(awsClient), Address: address})
+			}
+		}
+
+		switch viper.GetString(outputFlag) {
+		case "json", "JSON":
+			eipPrintJSON(addresses)
+		default:
+			eipPrintTable(addresses)
+		}
+	},
+}
+
+var eipDeleteCmd = &cobra.Command{
+	Use:     eipDeleteCmdName,
+	Aliases: eipDeleteCmdAliases,
+	Short:   "Release unassociated Elastic IPs",
+	Long: fmt.Sprintf(`This command can be used to release unassociated Elastic IPs which were allocated before the given
+duration. An Elastic IP without AllocateAddress event in CloudTrail was allocated before the lookup. As
+CloudTrail only covers 90 days such Elastic IPs are kept if --%s is longer then 90d.
+
+Examples:
+%s`,
+		olderthenFlag,
+		eipDeleteCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		for _, awsClient := range awsClients() {
+			eslog.Logger.Infof("Cleanup Elastic IPs in %s", newOrigin(awsClient))
+
+			eipclean := eipclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), true)
+
+			err := eipclean.ReleaseUnassociatedAddresses()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "eipclean.ReleaseUnassociatedAddresses() failed: %s", err)
+		}
+	},
+}
+
+func eipBindFlags() {
+	eipCmd.AddCommand(eipDeleteCmd)
+	eipCmd.AddCommand(eipListCmd)
+	rootCmd.AddCommand(eipCmd)
+
+	const objType = "Elastic IPs"
+
+	eipDeleteCmdFlags := eipDeleteCmd.Flags()
+	deleteOnlyFlags(eipDeleteCmdFlags)
+
+	eipListCmdFlags := eipListCmd.Flags()
+	eipListCmdFlags.BoolP(onlyUnusedFlag, onlyUnusedFlagSH, false, "defines if only unassociated Elastic IPs are listed or all [Default: false]")
+	listOnlyFlags(eipListCmdFlags, objType)
+
+	err := viper.BindPFlags(eipListCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+
+	err = viper.BindPFlags(eipDeleteCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+}
+
+type eipOutput struct {
+	origin
+	eipclean.Address
+}
+
+func eipPrintTable(addresses []eipOutput) {
+	addressTable := table.New("Account", "Region", "Public IP", "Allocation ID", "Association ID", "Instance ID", "Allocation Datetime", "Allocated by")
+	for _, address := range addresses {
+		allocationTime := ""
+		if address.AllocationTime != nil {
+			allocationTime = address.AllocationTime.Format(time.RFC3339)
+		}
+		addressTable.AddRow(address.Account, address.Region, aws.ToString(address.PublicIp), aws.ToString(address.AllocationId), aws.ToString(address.AssociationId), aws.ToString(address.InstanceId), allocationTime, address.Allocator)
+	}
+	addressTable.Print()
+}
+
+func eipPrintJSON(addresses []eipOutput) {
+	out, err := json.Marshal(addresses)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Json.Marshal(addresses) failed: %s", err)
+	fmt.Print(string(out))
+}
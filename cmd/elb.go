@@ -0,0 +1,199 @@
+/*
+Copyright © 2026 steffakasid
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/elbclean"
+	eslog "github.com/steffakasid/eslog"
+)
+
+const (
+	elbCmdName       = "elb"
+	elbListCmdName   = "list"
+	elbDeleteCmdName = "delete"
+)
+
+var (
+	elbListCmdAliases   = []string{"ls"}
+	elbDeleteCmdAliases = []string{"del"}
+)
+
+var (
+	elbDeleteCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --older-then 2w       delete load balancers without targets for 2w and orphaned target groups
+  %[1]s %[2]s %[3]s --dry-run             do not delete anything just show what should be done
+	`,
+		binaryname,
+		elbCmdName,
+		elbDeleteCmdName)
+	elbListCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --only-unused         list only unused load balancers and target groups
+  %[1]s %[2]s %[4]s --output json          list all load balancers and target groups as JSON
+	`,
+		binaryname,
+		elbCmdName,
+		elbListCmdName,
+		elbListCmdAliases[0])
+)
+
+// elbCmd represents the elb command
+var elbCmd = &cobra.Command{
+	Use:   elbCmdName,
+	Short: "Cleanup unused load balancers and target groups",
+	Long: fmt.Sprintf(`This tool can be used to list or cleanup unused Elastic Load Balancers (ALB, NLB and GWLB) and target groups.
+
+A load balancer is unused if none of its target groups has a registered target, regardless of the target
+health, and none of its listeners answers without forwarding, e.g. by a redirect or a fixed response. A
+target group is unused if it's not attached to any load balancer.
+
+AWS doesn't return when targets were deregistered or target groups were created, so this is taken from
+CloudTrail which only covers the past 90 days.
+
+Examples:
+%s%s`,
+		elbDeleteCmdExamples,
+		elbListCmdExamples),
+}
+
+var elbListCmd = &cobra.Command{
+	Use:     elbListCmdName,
+	Aliases: elbListCmdAliases,
+	Short:   "List load balancers and target groups",
+	Long: fmt.Sprintf(`This command can be used to list load balancers and target groups. Nothing will be deleted.
+
+Examples:
+%s`,
+		elbListCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		out := elbOutput{LoadBalancers: []loadBalancerOutput{}, TargetGroups: []targetGroupOutput{}}
+		for _, awsClient := range awsClients() {
+			elbclean := elbclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag))
+
+			err := elbclean.GetLoadBalancers()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "elbclean.GetLoadBalancers() failed: %s", err)
+
+			for _, lb := range elbclean.GetAllLoadBalancers() {
+				out.LoadBalancers = append(out.LoadBalancers, loadBalancerOutput{origin: newOrigin(awsClient), LoadBalancer: lb})
+			}
+			for _, tg := range elbclean.GetAllTargetGroups() {
+				out.TargetGroups = append(out.TargetGroups, targetGroupOutput{origin: newOrigin(awsClient), TargetGroup: tg})
+			}
+		}
+
+		switch viper.GetString(outputFlag) {
+		case "json", "JSON":
+			elbPrintJSON(out)
+		default:
+			elbPrintTable(out)
+		}
+	},
+}
+
+var elbDeleteCmd = &cobra.Command{
+	Use:     elbDeleteCmdName,
+	Aliases: elbDeleteCmdAliases,
+	Short:   "Cleanup unused load balancers and target groups",
+	Long: fmt.Sprintf(`This command can be used to delete load balancers which have no registered targets for longer then the
+given duration and target groups which are attached to no load balancer and were created before.
+
+A load balancer is unused long enough if it was created and its targets were deregistered before --%s.
+Unhealthy targets count as registered. Without event in CloudTrail the change happened before the lookup.
+As CloudTrail only covers 90 days such load balancers and target groups are kept if --%s is longer then
+90d. Target groups of deleted load balancers are deleted by the next run.
+
+Examples:
+%s`,
+		olderthenFlag,
+		olderthenFlag,
+		elbDeleteCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		for _, awsClient := range awsClients() {
+			eslog.Logger.Infof("Cleanup load balancers in %s", newOrigin(awsClient))
+
+			elbclean := elbclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), true)
+
+			err := elbclean.DeleteUnusedLoadBalancers()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "elbclean.DeleteUnusedLoadBalancers() failed: %s", err)
+		}
+	},
+}
+
+func elbBindFlags() {
+	elbCmd.AddCommand(elbDeleteCmd)
+	elbCmd.AddCommand(elbListCmd)
+	rootCmd.AddCommand(elbCmd)
+
+	const objType = "load balancers"
+
+	elbDeleteCmdFlags := elbDeleteCmd.Flags()
+	deleteOnlyFlags(elbDeleteCmdFlags)
+
+	elbListCmdFlags := elbListCmd.Flags()
+	elbListCmdFlags.BoolP(onlyUnusedFlag, onlyUnusedFlagSH, false, "defines if only unused load balancers and target groups are listed or all [Default: false]")
+	listOnlyFlags(elbListCmdFlags, objType)
+
+	err := viper.BindPFlags(elbListCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+
+	err = viper.BindPFlags(elbDeleteCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+}
+
+type elbOutput struct {
+	LoadBalancers []loadBalancerOutput
+	TargetGroups  []targetGroupOutput
+}
+
+type loadBalancerOutput struct {
+	origin
+	elbclean.LoadBalancer
+}
+
+type targetGroupOutput struct {
+	origin
+	elbclean.TargetGroup
+}
+
+func elbPrintTable(out elbOutput) {
+	lbTable := table.New("Account", "Region", "Load Balancer", "Type", "Creation Datetime", "Target Groups", "Registered", "Healthy", "Non Forward Listeners", "Last Deregistration")
+	for _, lb := range out.LoadBalancers {
+		lbTable.AddRow(lb.Account, lb.Region, aws.ToString(lb.LoadBalancerName), lb.Type, formatTime(lb.CreatedTime), strings.Join(lb.TargetGroups, ", "), lb.RegisteredTargets, lb.HealthyTargets, lb.NonForwardListeners, formatTime(lb.LastDeregistration))
+	}
+	lbTable.Print()
+	fmt.Println()
+
+	tgTable := table.New("Account", "Region", "Target Group", "Target Type", "Load Balancers", "Registered", "Creation Datetime", "Created by")
+	for _, tg := range out.TargetGroups {
+		tgTable.AddRow(tg.Account, tg.Region, aws.ToString(tg.TargetGroupName), tg.TargetType, len(tg.LoadBalancerArns), tg.RegisteredTargets, formatTime(tg.CreationTime), tg.Creator)
+	}
+	tgTable.Print()
+}
+
+func elbPrintJSON(out elbOutput) {
+	content, err := json.Marshal(out)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Json.Marshal(out) failed: %s", err)
+	fmt.Print(string(content))
+}
+
+// formatTime returns t in RFC3339 or an empty string if t is nil.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2026 steffakasid
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/eniclean"
+	eslog "github.com/steffakasid/eslog"
+)
+
+const (
+	eniCmdName       = "eni"
+	eniListCmdName   = "list"
+	eniDeleteCmdName = "delete"
+)
+
+var (
+	eniListCmdAliases   = []string{"ls"}
+	eniDeleteCmdAliases = []string{"del"}
+)
+
+var (
+	eniDeleteCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --older-then 5d       delete all detached network interfaces which are older then 5d
+  %[1]s %[2]s %[3]s --dry-run             do not delete any network interface just show what should be done
+	`,
+		binaryname,
+		eniCmdName,
+		eniDeleteCmdName)
+	eniListCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --only-unused         list only detached network interfaces
+  %[1]s %[2]s %[4]s --output json          list all network interfaces as JSON
+	`,
+		binaryname,
+		eniCmdName,
+		eniListCmdName,
+		eniListCmdAliases[0])
+)
+
+// eniCmd represents the eni command
+var eniCmd = &cobra.Command{
+	Use:   eniCmdName,
+	Short: "Cleanup orphaned network interfaces",
+	Long: fmt.Sprintf(`This tool can be used to list or cleanup old and detached Elastic Network Interfaces (ENI).
+
+A network interface is orphaned if it's in state available, i.e. not attached to anything, and not managed
+by another service like ELB or EKS. Orphaned network interfaces are left behind by Lambda, the EKS CNI or
+failed instance launches and prevent the deletion of their SecurityGroups.
+
+AWS doesn't return when a network interface was created, so the creation time is taken from CloudTrail which
+only covers the past 90 days.
+
+Examples:
+%s%s`,
+		eniDeleteCmdExamples,
+		eniListCmdExamples),
+}
+
+var eniListCmd = &cobra.Command{
+	Use:     eniListCmdName,
+	Aliases: eniListCmdAliases,
+	Short:   "List network interfaces",
+	Long: fmt.Sprintf(`This command can be used to list network interfaces. Nothing will be deleted.
+
+Examples:
+%s`,
+		eniListCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		ifaces := []eniOutput{}
+		for _, awsClient := range awsClients() {
+			eniclean := eniclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag))
+
+			err := eniclean.GetNetworkInterfaces()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "eniclean.GetNetworkInterfaces() failed: %s", err)
+
+			for _, iface := range eniclean.GetAllNetworkInterfaces() {
+				ifaces = append(ifaces, eniOutput{origin: newOrigin(awsClient), NetworkInterface: iface})
+			}
+		}
+
+		switch viper.GetString(outputFlag) {
+		case "json", "JSON":
+			eniPrintJSON(ifaces)
+		default:
+			eniPrintTable(ifaces)
+		}
+	},
+}
+
+var eniDeleteCmd = &cobra.Command{
+	Use:     eniDeleteCmdName,
+	Aliases: eniDeleteCmdAliases,
+	Short:   "Cleanup orphaned network interfaces",
+	Long: fmt.Sprintf(`This command can be used to delete detached network interfaces which are older then the given
+duration. Network interfaces managed by other services are never deleted. A network interface without
+CreateNetworkInterface event in CloudTrail is older then the lookup. As CloudTrail only covers 90 days
+such network interfaces are kept if --%s is longer then 90d.
+
+Examples:
+%s`,
+		olderthenFlag,
+		eniDeleteCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		for _, awsClient := range awsClients() {
+			eslog.Logger.Infof("Cleanup network interfaces in %s", newOrigin(awsClient))
+
+			eniclean := eniclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), true)
+
+			err := eniclean.DeleteOrphanedNetworkInterfaces()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "eniclean.DeleteOrphanedNetworkInterfaces() failed: %s", err)
+		}
+	},
+}
+
+func eniBindFlags() {
+	eniCmd.AddCommand(eniDeleteCmd)
+	eniCmd.AddCommand(eniListCmd)
+	rootCmd.AddCommand(eniCmd)
+
+	const objType = "network interfaces"
+
+	eniDeleteCmdFlags := eniDeleteCmd.Flags()
+	deleteOnlyFlags(eniDeleteCmdFlags)
+
+	eniListCmdFlags := eniListCmd.Flags()
+	eniListCmdFlags.BoolP(onlyUnusedFlag, onlyUnusedFlagSH, false, "defines if only detached network interfaces are listed or all [Default: false]")
+	listOnlyFlags(eniListCmdFlags, objType)
+
+	err := viper.BindPFlags(eniListCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+
+	err = viper.BindPFlags(eniDeleteCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+}
+
+type eniOutput struct {
+	origin
+	eniclean.NetworkInterface
+}
+
+func eniPrintTable(ifaces []eniOutput) {
+	ifaceTable := table.New("Account", "Region", "Interface ID", "Type", "Status", "VPC", "Description", "Creation Datetime", "Created by")
+	for _, iface := range ifaces {
+		creationTime := ""
+		if iface.CreationTime != nil {
+			creationTime = iface.CreationTime.Format(time.RFC3339)
+		}
+		ifaceTable.AddRow(iface.Account, iface.Region, aws.ToString(iface.NetworkInterfaceId), iface.InterfaceType, iface.Status, nilCheck(iface.VpcId), nilCheck(iface.Description), creationTime, iface.Creator)
+	}
+	ifaceTable.Print()
+}
+
+func eniPrintJSON(ifaces []eniOutput) {
+	out, err := json.Marshal(ifaces)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Json.Marshal(ifaces) failed: %s", err)
+	fmt.Print(string(out))
+}
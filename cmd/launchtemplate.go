@@ -0,0 +1,86 @@
+/*
+Copyright © 2026 steffakasid
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/launchtplclean"
+	eslog "github.com/steffakasid/eslog"
+)
+
+const (
+	launchTplCmdName      = "launchtemplate"
+	launchTplPruneCmdName = "prune"
+)
+
+var launchTplCmdAliases = []string{"lt"}
+
+var launchTplPruneCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --older-then 30d      delete all launch template versions older then 30d except the 5 newest ones
+  %[1]s %[2]s %[3]s --%[4]s 10      keep the 10 newest versions of each launch template
+  %[1]s %[2]s %[3]s --dry-run             do not delete anything just show what should be done
+	`,
+	binaryname,
+	launchTplCmdName,
+	launchTplPruneCmdName,
+	keepNewestFlag)
+
+// launchTplCmd represents the launchtemplate command
+var launchTplCmd = &cobra.Command{
+	Use:     launchTplCmdName,
+	Aliases: launchTplCmdAliases,
+	Short:   "Cleanup old launch template versions",
+	Long: fmt.Sprintf(`This tool can be used to prune old versions of EC2 launch templates and to delete unused launch templates.
+
+Examples:
+%s`,
+		launchTplPruneCmdExamples),
+}
+
+var launchTplPruneCmd = &cobra.Command{
+	Use:   launchTplPruneCmdName,
+	Short: "Delete old launch template versions and unused launch templates",
+	Long: fmt.Sprintf(`This command can be used to delete the versions of each launch template which were created before the
+given duration. The following versions are always kept:
+  - the $Default and the $Latest version
+  - the newest versions given by --%s
+  - the versions referenced by Auto Scaling groups, directly or by a mixed instances policy
+  - the versions not terminated instances were launched from
+
+Launch templates which are neither referenced by an Auto Scaling group nor used by an instance are deleted
+as a whole if their newest version was created before the given duration.
+
+Examples:
+%s`,
+		keepNewestFlag,
+		launchTplPruneCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		for _, awsClient := range awsClients() {
+			eslog.Logger.Infof("Cleanup launch templates in %s", newOrigin(awsClient))
+
+			launchtplclean := launchtplclean.NewInstance(awsClient, olderthenDuration, viper.GetInt(keepNewestFlag), viper.GetBool(dryrunFlag))
+
+			err := launchtplclean.Prune()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "launchtplclean.Prune() failed: %s", err)
+		}
+	},
+}
+
+func launchTplBindFlags() {
+	launchTplCmd.AddCommand(launchTplPruneCmd)
+	rootCmd.AddCommand(launchTplCmd)
+
+	launchTplPruneCmdFlags := launchTplPruneCmd.Flags()
+	deleteOnlyFlags(launchTplPruneCmdFlags)
+	launchTplPruneCmdFlags.Int(keepNewestFlag, 5, "Always keep the newest N versions of each launch template regardless of their age.")
+
+	err := viper.BindPFlags(launchTplPruneCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright © 2026 steffakasid
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/loggroupclean"
+	eslog "github.com/steffakasid/eslog"
+)
+
+const (
+	logGroupCmdName       = "loggroup"
+	logGroupListCmdName   = "list"
+	logGroupDeleteCmdName = "delete"
+)
+
+var (
+	logGroupListCmdAliases   = []string{"ls"}
+	logGroupDeleteCmdAliases = []string{"del"}
+)
+
+var (
+	logGroupDeleteCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --older-then 90d      delete all log groups without new events for 90d and empty log groups
+  %[1]s %[2]s %[3]s --%[4]s 30    set a retention of 30 days on stale log groups instead of deleting them
+  %[1]s %[2]s %[3]s -i '^/aws/eks/'       ignore all log groups starting with /aws/eks/
+  %[1]s %[2]s %[3]s --dry-run             do not delete anything just show what should be done
+	`,
+		binaryname,
+		logGroupCmdName,
+		logGroupDeleteCmdName,
+		setRetentionFlag)
+	logGroupListCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --only-unused         list only stale and empty log groups
+  %[1]s %[2]s %[4]s --output json          list all log groups as JSON
+	`,
+		binaryname,
+		logGroupCmdName,
+		logGroupListCmdName,
+		logGroupListCmdAliases[0])
+)
+
+// logGroupCmd represents the loggroup command
+var logGroupCmd = &cobra.Command{
+	Use:   logGroupCmdName,
+	Short: "Cleanup stale and empty CloudWatch log groups",
+	Long: fmt.Sprintf(`This tool can be used to list or cleanup CloudWatch log groups which didn't get new events for longer
+then the given duration or which are empty, e.g. the log groups of deleted Lambda functions or ECS services.
+
+Examples:
+%s%s`,
+		logGroupDeleteCmdExamples,
+		logGroupListCmdExamples),
+}
+
+var logGroupListCmd = &cobra.Command{
+	Use:     logGroupListCmdName,
+	Aliases: logGroupListCmdAliases,
+	Short:   "List CloudWatch log groups",
+	Long: fmt.Sprintf(`This command can be used to list CloudWatch log groups. Nothing will be deleted.
+
+Examples:
+%s`,
+		logGroupListCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		logGroups := []logGroupOutput{}
+		for _, awsClient := range awsClients() {
+			loggroupclean := loggroupclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag), viper.GetStringSlice(ignoreFlag))
+
+			err := loggroupclean.GetLogGroups()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "loggroupclean.GetLogGroups() failed: %s", err)
+
+			for _, logGroup := range loggroupclean.GetAllLogGroups() {
+				logGroups = append(logGroups, logGroupOutput{origin: newOrigin(awsClient), LogGroup: logGroup})
+			}
+		}
+
+		switch viper.GetString(outputFlag) {
+		case "json", "JSON":
+			logGroupPrintJSON(logGroups)
+		default:
+			logGroupPrintTable(logGroups)
+		}
+	},
+}
+
+var logGroupDeleteCmd = &cobra.Command{
+	Use:     logGroupDeleteCmdName,
+	Aliases: logGroupDeleteCmdAliases,
+	Short:   "Delete stale and empty CloudWatch log groups",
+	Long: fmt.Sprintf(`This command can be used to delete log groups whose last event was ingested before the given duration
+and log groups without log streams which were created before. With --%s such log groups get a retention policy
+instead of being deleted if they don't have one. CloudWatch Logs has no dry run, with --%s nothing is called.
+
+Examples:
+%s`,
+		setRetentionFlag,
+		dryrunFlag,
+		logGroupDeleteCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		retentionInDays := viper.GetInt32(setRetentionFlag)
+		if retentionInDays != 0 && !slices.Contains(internal.RETENTION_DAYS, retentionInDays) {
+			eslog.Fatalf("--%s must be one of %v", setRetentionFlag, internal.RETENTION_DAYS)
+		}
+
+		for _, awsClient := range awsClients() {
+			eslog.Logger.Infof("Cleanup log groups in %s", newOrigin(awsClient))
+
+			loggroupclean := loggroupclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), true, viper.GetStringSlice(ignoreFlag),
+				loggroupclean.WithRetention(retentionInDays))
+
+			err := loggroupclean.DeleteStaleLogGroups()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "loggroupclean.DeleteStaleLogGroups() failed: %s", err)
+		}
+	},
+}
+
+func logGroupBindFlags() {
+	logGroupCmd.AddCommand(logGroupDeleteCmd)
+	logGroupCmd.AddCommand(logGroupListCmd)
+	rootCmd.AddCommand(logGroupCmd)
+
+	const objType = "log groups"
+
+	logGroupDeleteCmdFlags := logGroupDeleteCmd.Flags()
+	deleteOnlyFlags(logGroupDeleteCmdFlags)
+	logGroupDeleteCmdFlags.StringArrayP(ignoreFlag, ignoreFlagSH, []string{}, "Set ignore regex patterns. If a log group name matches the pattern it will be exclueded from cleanup.")
+	logGroupDeleteCmdFlags.Int32(setRetentionFlag, 0, fmt.Sprintf("Set a retention policy with the given days on stale log groups without one instead of deleting them. Must be one of %v.", internal.RETENTION_DAYS))
+
+	logGroupListCmdFlags := logGroupListCmd.Flags()
+	logGroupListCmdFlags.BoolP(onlyUnusedFlag, onlyUnusedFlagSH, false, "defines if only stale and empty log groups are listed or all [Default: false]")
+	listOnlyFlags(logGroupListCmdFlags, objType)
+
+	err := viper.BindPFlags(logGroupListCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+
+	err = viper.BindPFlags(logGroupDeleteCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+}
+
+type logGroupOutput struct {
+	origin
+	loggroupclean.LogGroup
+}
+
+func logGroupPrintTable(logGroups []logGroupOutput) {
+	logGroupTable := table.New("Account", "Region", "Log Group", "Creation Datetime", "Last Ingestion", "Retention (days)", "Stored (MB)", "Reason")
+	for _, logGroup := range logGroups {
+		creationTime := time.UnixMilli(aws.ToInt64(logGroup.CreationTime))
+		retention := ""
+		if logGroup.RetentionInDays != nil {
+			retention = fmt.Sprint(*logGroup.RetentionInDays)
+		}
+		logGroupTable.AddRow(logGroup.Account, logGroup.Region, aws.ToString(logGroup.LogGroupName), formatTime(&creationTime), formatTime(logGroup.LastIngestionTime), retention, aws.ToInt64(logGroup.StoredBytes)/1024/1024, logGroup.Reason)
+	}
+	logGroupTable.Print()
+}
+
+func logGroupPrintJSON(logGroups []logGroupOutput) {
+	out, err := json.Marshal(logGroups)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Json.Marshal(logGroups) failed: %s", err)
+	fmt.Print(string(out))
+}
@@ -18,17 +18,39 @@ import (
 
 // Constants used in command flags
 const (
-	accountFlag    = "account"
-	debugFlag      = "debug"
-	dryrunFlag     = "dry-run"
-	endTimeFlag    = "end-time"
-	ignoreFlag     = "ignore"
-	launchTplFlag  = "launch-templates"
-	olderthenFlag  = "older-then"
-	outputFlag     = "output"
-	onlyUnusedFlag = "only-unused"
-	startTimeFlag  = "start-time"
-	showtagsFlag   = "show-tags"
+	accountFlag        = "account"
+	architectureFlag   = "architecture"
+	assumeAccountsFlag = "assume-accounts"
+	assumeRoleFlag     = "assume-role"
+	cacheFlag          = "cloudtrail-cache"
+	concurrencyFlag    = "concurrency"
+	debugFlag          = "debug"
+	deleteSnapshotFlag = "delete-snapshots"
+	dryrunFlag         = "dry-run"
+	endTimeFlag        = "end-time"
+	externalIDFlag     = "external-id"
+	familyPatternFlag  = "family-pattern"
+	familyTagFlag      = "family-tag"
+	gracePeriodFlag    = "grace-period"
+	ignoreFlag         = "ignore"
+	inclProtectedFlag  = "include-protected"
+	keepNewestFlag     = "keep-newest"
+	launchTplFlag      = "launch-templates"
+	namePatternFlag    = "name-pattern"
+	notLaunchedForFlag = "not-launched-for"
+	olderthenFlag      = "older-then"
+	outputFlag         = "output"
+	onlyUnusedFlag     = "only-unused"
+	regionsFlag        = "regions"
+	revokeStaleFlag    = "revoke-stale"
+	setRetentionFlag   = "set-retention"
+	startTimeFlag      = "start-time"
+	stateFlag          = "state"
+	showtagsFlag       = "show-tags"
+	strategyFlag       = "strategy"
+	tagFlag            = "tag"
+	trailLogsFlag      = "cloudtrail-logs"
+	unrefClustersFlag  = "unreferenced-clusters"
 )
 
 // constants used for short hand flags (to avoid collitions)
@@ -40,6 +62,7 @@ const (
 	launchTplFlagSH  = "l"
 	onlyUnusedFlagSH = "u"
 	olderthenFlagSH  = "o"
+	regionsFlagSH    = "r"
 	startTimeFlagSH  = "s"
 	showtagsFlagSH   = "t"
 )
@@ -56,7 +79,14 @@ var rootCmd = &cobra.Command{
 Right now it supports the following:
   - Amazon Machine Images (AMIs)
   - Elastic Blockstore (EBS) Volumes
+  - Elastic Blockstore (EBS) Snapshots
   - SecurityGroups
+  - Elastic Network Interfaces (ENIs)
+  - Elastic IPs
+  - Elastic Load Balancers (ELBs) and Target Groups
+  - Launch Template Versions
+  - Elastic Container Registry (ECR) Images
+  - CloudWatch Log Groups
 
 Preqrequisites:
   amiclean uses already provided credentials in ~/.aws/credentials also it uses the
@@ -64,6 +94,12 @@ Preqrequisites:
 
 Examples:
   %s ami --help  show help for ami subcommand%s%s`, binaryname, amiDeleteCmdExamples, amiListCmdExamples),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Several sub-commands define flags with the same name (e.g. --dry-run). Viper only keeps the
+		// last binding per key, so bind the flags of the executed command again.
+		err := viper.BindPFlags(cmd.Flags())
+		eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+	},
 }
 
 func Execute(version string) {
@@ -81,7 +117,14 @@ func init() {
 	bindPersistentFlags()
 	amiBindFlags()
 	ebsBindFlags()
+	ecrBindFlags()
+	eipBindFlags()
+	elbBindFlags()
+	eniBindFlags()
+	logGroupBindFlags()
+	launchTplBindFlags()
 	secGrpBindFlags()
+	snapshotBindFlags()
 }
 
 func bindPersistentFlags() {
@@ -92,6 +135,10 @@ func bindPersistentFlags() {
 	peristentFlags.StringP(debugFlag, "", "info", "Enable debugging. Possible Values [debug,info,warn,error,fatal]")
 	peristentFlags.StringP(outputFlag, "", "table", "Define how to output results [table, json] (default: table)")
 	peristentFlags.StringP(olderthenFlag, olderthenFlagSH, "7d", "Set the duration string (e.g 5d, 1w etc.) how old an object must be to be deleted. E.g. if set to 7d, objects will be delete which are older then 7 days.")
+	peristentFlags.StringSlice(assumeAccountsFlag, []string{}, fmt.Sprintf("Set the AWS account IDs to cleanup. For each account the role given by --%s is assumed. If not set the default credentials are used.", assumeRoleFlag))
+	peristentFlags.String(assumeRoleFlag, "", fmt.Sprintf("Set the role to assume in each of the accounts given by --%s. Either a role name or an ARN template like arn:aws:iam::%s:role/cleanup.", assumeAccountsFlag, internal.ACCOUNT_PLACEHOLDER))
+	peristentFlags.String(externalIDFlag, "", "Set the external ID used when assuming the role.")
+	peristentFlags.StringSliceP(regionsFlag, regionsFlagSH, []string{}, fmt.Sprintf("Set the AWS regions to scan (e.g. eu-central-1,us-east-1). Use '%s' to scan all enabled regions. If not set the default region from ~/.aws/config is used.", internal.ALL_REGIONS))
 
 	err := viper.BindPFlags(peristentFlags)
 	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
@@ -136,6 +183,37 @@ func initConfig() {
 	eslog.LogIfError(err, eslog.Error, err)
 }
 
+// origin describes where a listed object was found. It's embedded in the output types of all commands.
+type origin struct {
+	Account string `json:",omitempty"`
+	Region  string
+}
+
+func newOrigin(awsClient *internal.AWS) origin {
+	return origin{Account: awsClient.AccountID(), Region: awsClient.Region()}
+}
+
+func (o origin) String() string {
+	if o.Account == "" {
+		return o.Region
+	}
+	return fmt.Sprintf("%s/%s", o.Account, o.Region)
+}
+
+// awsClients returns one AWS client for each combination of the accounts and regions given by flags.
+func awsClients() []*internal.AWS {
+	assumeRole := internal.AssumeRoleConfig{
+		Accounts:   viper.GetStringSlice(assumeAccountsFlag),
+		Role:       viper.GetString(assumeRoleFlag),
+		ExternalID: viper.GetString(externalIDFlag),
+	}
+	if len(assumeRole.Accounts) > 0 && assumeRole.Role == "" {
+		eslog.Fatalf("--%s must be set if --%s is used", assumeRoleFlag, assumeAccountsFlag)
+	}
+
+	return internal.NewAWSClients(viper.GetStringSlice(regionsFlag), assumeRole)
+}
+
 func nilCheck(tocheck *string) string {
 	if tocheck == nil {
 		return "nil"
@@ -6,6 +6,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/rodaine/table"
@@ -20,6 +21,7 @@ const (
 	secGrpCmdName       = "securitzGroups"
 	secGrpListCmdName   = "list"
 	secGrpDeleteCmdName = "delete"
+	secGrpAuditCmdName  = "audit"
 )
 
 var (
@@ -33,11 +35,24 @@ var (
   %[1]s %[2]s %[4]s
   %[1]s %[3]s %[4]s
   %[1]s %[3]s %[5]s
+  %[1]s %[3]s %[5]s --%[6]s
+  %[1]s %[3]s %[5]s --%[7]s
 `, binaryname,
 		secGrpCmdName,
 		secGrpCmdAliases[0],
 		secGrpDeleteCmdName,
-		secGrpDeleteCmdAliases[0])
+		secGrpDeleteCmdAliases[0],
+		unrefClustersFlag,
+		inclProtectedFlag)
+	secGrpAuditCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s
+  %[1]s %[2]s %[3]s --output json
+  %[1]s %[2]s %[3]s --%[4]s --%[5]s
+`, binaryname,
+		secGrpCmdAliases[0],
+		secGrpAuditCmdName,
+		revokeStaleFlag,
+		dryrunFlag)
 	secGrpListCmdExamples = fmt.Sprintf(`
 		%[1]s %[2]s %[4]s
 		%[1]s %[3]s %[4]s
@@ -57,9 +72,10 @@ var secGrpCmd = &cobra.Command{
 	Long: fmt.Sprintf(`
 
 Examples:
-%s%s`,
+%s%s%s`,
 		secGrpDeleteCmdExamples,
-		secGrpListCmdExamples),
+		secGrpListCmdExamples,
+		secGrpAuditCmdExamples),
 }
 
 // secGrpListCmd represents the list command
@@ -70,23 +86,36 @@ var secGrpListCmd = &cobra.Command{
 	Long: fmt.Sprintf(`Just list all SecurityGroups from connected AWS account.
 	
 Also the command tries to get the CreationTime from CloudTrail. CloudTrail only has this information for the past 90 days.
-So older SecurityGroups will have no CreationTime / Creator information.
+So older SecurityGroups will have no CreationTime / Creator information unless they were already seen
+by a previous run: the CloudTrail events are cached in --%s. To get older information use --%s to read
+the log files of your trail.
+With --output json the CreateSecurityGroup and DeleteSecurityGroup events of each SecurityGroup are shown as History.
 	
 Examples:
 %s`,
+		cacheFlag,
+		trailLogsFlag,
 		secGrpListCmdExamples),
 	Run: func(cmd *cobra.Command, args []string) {
 
-		secgrp, startDatetime, endDatetime := setup()
+		grps := []secGrpOutput{}
+		cache := secGrpCloudTrailCache()
+		for _, awsClient := range awsClients() {
+			secgrp, startDatetime, endDatetime := setup(awsClient, cache)
 
-		err := secgrp.GetSecurityGroups(startDatetime, endDatetime)
-		eslog.LogIfErrorf(err, eslog.Fatalf, "secgrp.GetSecurityGroups() failed: %s", err)
+			err := secgrp.GetSecurityGroups(startDatetime, endDatetime)
+			eslog.LogIfErrorf(err, eslog.Fatalf, "secgrp.GetSecurityGroups() failed: %s", err)
+
+			for _, grp := range secgrp.GetAllSecurityGroups() {
+				grps = append(grps, secGrpOutput{origin: newOrigin(awsClient), SecurityGroup: grp})
+			}
+		}
 
 		switch viper.GetString(outputFlag) {
 		case "json", "JSON":
-			secGrpPrintJSON(secgrp.GetAllSecurityGroups())
+			secGrpPrintJSON(grps)
 		default:
-			secGrpPrintTable(secgrp.GetAllSecurityGroups())
+			secGrpPrintTable(grps)
 		}
 
 	},
@@ -100,20 +129,85 @@ var secGrpDeleteCmd = &cobra.Command{
 
 For security groups we only get the creation date of the past 90 days. So if older then date is specified less then 90d all SecurityGroups will be deleted which are older then this duration or do not have a CreationDate set as we couldn't get it from CloudTrail (in fact that means they are older then 90d).
 
+SecurityGroups which are referenced in the rules of other SecurityGroups are treated as used. With --%s SecurityGroups which are only referenced by other unused SecurityGroups are deleted as well, referencing SecurityGroups first.
+
+Protected SecurityGroups are never deleted: the default SecurityGroup of each VPC and SecurityGroups managed by other services like EKS, ELB or Directory Service. They are identified by requester-managed network interfaces, their name or aws: and kubernetes.io/ tags. With --%s managed SecurityGroups are deleted as well, the default SecurityGroups are always kept.
+
 Examples:
 %s`,
+		unrefClustersFlag,
+		inclProtectedFlag,
 		secGrpDeleteCmdExamples),
 	Run: func(cmd *cobra.Command, args []string) {
 
-		secgrp, startDatetime, endDatetime := setup()
-
 		ignoredIDs := viper.GetStringSlice(ignoreFlag)
+		cache := secGrpCloudTrailCache()
 
-		err := secgrp.DeleteSecurityGroups(startDatetime, endDatetime, ignoredIDs...)
-		eslog.LogIfErrorf(err, eslog.Fatalf, "secgrp.DeleteSecurityGroups() failed: %s", err)
+		for _, awsClient := range awsClients() {
+			eslog.Logger.Infof("Cleanup SecurityGroups in %s", newOrigin(awsClient))
+
+			secgrp, startDatetime, endDatetime := setup(awsClient, cache)
+
+			err := secgrp.DeleteSecurityGroups(startDatetime, endDatetime, ignoredIDs...)
+			eslog.LogIfErrorf(err, eslog.Fatalf, "secgrp.DeleteSecurityGroups() failed: %s", err)
+		}
 	},
 }
 
+var secGrpAuditCmd = &cobra.Command{
+	Use:   secGrpAuditCmdName,
+	Short: "Report risky or dead rules of SecurityGroups",
+	Long: fmt.Sprintf(`Report risky or dead rules of all SecurityGroups from connected AWS account
+
+The findings are classified by severity:
+  high    ingress from 0.0.0.0/0 or ::/0 on other ports then 80 and 443
+  medium  rules referencing deleted SecurityGroups or prefix lists (stale rules)
+  low     CIDRs which are duplicates of or covered by another rule and ICMP open to the internet
+
+With --%s stale rules are revoked.
+
+Examples:
+%s`,
+		revokeStaleFlag,
+		secGrpAuditCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		findings := []findingOutput{}
+		for _, awsClient := range awsClients() {
+			secgrp := secgrp.NewInstance(awsClient, nil, viper.GetBool(dryrunFlag), false)
+
+			clientFindings, err := secgrp.Audit()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "secgrp.Audit() failed: %s", err)
+
+			if viper.GetBool(revokeStaleFlag) {
+				secgrp.RevokeStaleRules(clientFindings)
+			}
+
+			for _, finding := range clientFindings {
+				findings = append(findings, findingOutput{origin: newOrigin(awsClient), Finding: finding})
+			}
+		}
+
+		switch viper.GetString(outputFlag) {
+		case "json", "JSON":
+			out, err := json.Marshal(findings)
+			eslog.LogIfErrorf(err, eslog.Fatalf, "Json.Marshal(findings) failed: %s", err)
+			fmt.Print(string(out))
+		default:
+			findingsTable := table.New("Account", "Region", "ID", "Name", "Direction", "Rule", "Severity", "Finding", "Stale")
+			for _, finding := range findings {
+				findingsTable.AddRow(finding.Account, finding.Region, finding.GroupId, finding.GroupName, finding.Direction, finding.Rule, finding.Severity, finding.Issue, finding.Stale)
+			}
+			findingsTable.Print()
+		}
+	},
+}
+
+type findingOutput struct {
+	origin
+	secgrp.Finding
+}
+
 func secGrpBindFlags() {
 	rootCmd.AddCommand(secGrpCmd)
 
@@ -126,13 +220,25 @@ func secGrpBindFlags() {
 	secGrpDeleteCmdFlags := secGrpDeleteCmd.Flags()
 	deleteOnlyFlags(secGrpDeleteCmdFlags)
 	secGrpDeleteCmdFlags.StringArrayP(ignoreFlag, ignoreFlagSH, nil, "List of SecurityGroup IDs to ignore")
+	secGrpDeleteCmdFlags.Bool(inclProtectedFlag, false, "also delete SecurityGroups managed by other services like EKS or ELB. Default SecurityGroups of VPCs are always kept")
+
+	secGrpAuditCmdFlags := secGrpAuditCmd.Flags()
+	deleteOnlyFlags(secGrpAuditCmdFlags)
+	secGrpAuditCmdFlags.Bool(revokeStaleFlag, false, "revoke rules which reference deleted SecurityGroups or prefix lists")
 
 	secGrpCmdPersistentFlags := secGrpCmd.PersistentFlags()
+	defaultCachePath, err := internal.DefaultCloudTrailCachePath()
+	eslog.LogIfErrorf(err, eslog.Warnf, "Can not get default path of CloudTrail cache: %s", err)
+	secGrpCmdPersistentFlags.String(cacheFlag, defaultCachePath, "file to cache CloudTrail events in, so creation information is kept after CloudTrail dropped it after 90 days. Set to an empty string to disable the cache")
+	secGrpCmdPersistentFlags.Int(concurrencyFlag, secgrp.DEFAULT_CONCURRENCY, "number of SecurityGroups which are checked for attached network interfaces in parallel")
+	secGrpCmdPersistentFlags.String(trailLogsFlag, "", "read the CloudTrail events from log files instead of LookupEvents, either an S3 location like s3://bucket/prefix or a local directory. Log files can cover more then 90 days")
+	secGrpCmdPersistentFlags.Bool(unrefClustersFlag, false, "treat SecurityGroups which are only referenced by other unused SecurityGroups as unused and delete them in dependency order")
 
 	secGrpCmd.AddCommand(secGrpListCmd)
 	secGrpCmd.AddCommand(secGrpDeleteCmd)
+	secGrpCmd.AddCommand(secGrpAuditCmd)
 
-	err := viper.BindPFlags(secGrpCmdPersistentFlags)
+	err = viper.BindPFlags(secGrpCmdPersistentFlags)
 	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %w", err)
 
 	err = viper.BindPFlags(secGrpDeleteCmdFlags)
@@ -140,13 +246,34 @@ func secGrpBindFlags() {
 
 	err = viper.BindPFlags(secGrpListCmdFlags)
 	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %w", err)
+
+	err = viper.BindPFlags(secGrpAuditCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %w", err)
+}
+
+// secGrpCloudTrailCache loads the CloudTrail cache shared by all accounts and regions. It returns nil if the
+// cache is disabled.
+func secGrpCloudTrailCache() *internal.CloudTrailCache {
+	cachePath := viper.GetString(cacheFlag)
+	if cachePath == "" {
+		return nil
+	}
+	cache, err := internal.LoadCloudTrailCache(cachePath)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Could not load CloudTrail cache: %s", err)
+	return cache
 }
 
-func setup() (*secgrp.SecGrp, time.Time, time.Time) {
+func setup(awsClient *internal.AWS, cache *internal.CloudTrailCache) (*secgrp.SecGrp, time.Time, time.Time) {
+	if cache != nil {
+		awsClient.SetCloudTrailCache(cache)
+	}
+	if logs := viper.GetString(trailLogsFlag); logs != "" {
+		awsClient.SetSecGrpEventSource(awsClient.NewCloudTrailLogs(logs))
+	}
+
 	olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
 
-	awsClient := internal.NewAWSClient()
-	secgrp := secgrp.NewInstance(awsClient, &olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag))
+	secgrp := secgrp.NewInstance(awsClient, &olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag), secgrp.WithDeleteClusters(viper.GetBool(unrefClustersFlag)), secgrp.WithConcurrency(viper.GetInt(concurrencyFlag)), secgrp.WithDeleteProtected(viper.GetBool(inclProtectedFlag)))
 
 	startDatetime, err := time.Parse(time.RFC3339, viper.GetString(startTimeFlag))
 	eslog.LogIfErrorf(err, eslog.Fatalf, "Error parsing given %s: %s", startTimeFlag, err)
@@ -156,21 +283,26 @@ func setup() (*secgrp.SecGrp, time.Time, time.Time) {
 	return secgrp, startDatetime, endDatetime
 }
 
-func secGrpPrintTable(grps internal.SecurityGroups) {
-	grpsTable := table.New("ID", "Name", "Creation Datetime", "Created by", "IsUsed")
+type secGrpOutput struct {
+	origin
+	*internal.SecurityGroup
+}
+
+func secGrpPrintTable(grps []secGrpOutput) {
+	grpsTable := table.New("Account", "Region", "ID", "Name", "VPC", "Creation Datetime", "Created by", "IsUsed", "Referenced by", "Protected")
 	for _, grp := range grps {
 		// TODO: conditionally add tags here.
-		if grp.SecurityGroup != nil {
+		if grp.SecurityGroup.SecurityGroup != nil {
 			if grp.CreationTime == nil {
 				grp.CreationTime = &time.Time{}
 			}
-			grpsTable.AddRow(nilCheck(grp.GroupId), nilCheck(grp.GroupName), grp.CreationTime.Format(time.RFC3339), grp.Creator, grp.IsUsed)
+			grpsTable.AddRow(grp.Account, grp.Region, nilCheck(grp.GroupId), nilCheck(grp.GroupName), nilCheck(grp.VpcId), grp.CreationTime.Format(time.RFC3339), grp.Creator, grp.IsUsed, strings.Join(grp.ReferencedBy, ", "), grp.ProtectedReason)
 		}
 	}
 	grpsTable.Print()
 }
 
-func secGrpPrintJSON(grps internal.SecurityGroups) {
+func secGrpPrintJSON(grps []secGrpOutput) {
 	out, err := json.Marshal(grps)
 	eslog.LogIfErrorf(err, eslog.Fatalf, "Json.Marshal(grps) failed: %s", err)
 	fmt.Print(string(out))
@@ -0,0 +1,163 @@
+/*
+Copyright © 2026 steffakasid
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/snapclean"
+	eslog "github.com/steffakasid/eslog"
+)
+
+const (
+	snapshotCmdName       = "snapshot"
+	snapshotListCmdName   = "list"
+	snapshotDeleteCmdName = "delete"
+)
+
+var (
+	snapshotCmdAliases       = []string{"snap"}
+	snapshotListCmdAliases   = []string{"ls"}
+	snapshotDeleteCmdAliases = []string{"del"}
+)
+
+var (
+	snapshotDeleteCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --older-then 5w       delete all orphaned EBS snapshots which are older then 5w
+  %[1]s %[2]s %[3]s --dry-run             do not delete any EBS snapshot just show what should be done
+  %[1]s %[2]s %[3]s --ignore ^keep-.*     do not delete EBS snapshots which name or description starts with keep-
+	`,
+		binaryname,
+		snapshotCmdName,
+		snapshotDeleteCmdName)
+	snapshotListCmdExamples = fmt.Sprintf(`
+  %[1]s %[2]s %[3]s --only-unused         list only orphaned EBS snapshots
+  %[1]s %[2]s %[4]s --output json          list all EBS snapshots as JSON
+	`,
+		binaryname,
+		snapshotCmdName,
+		snapshotListCmdName,
+		snapshotListCmdAliases[0])
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:     snapshotCmdName,
+	Aliases: snapshotCmdAliases,
+	Short:   "Cleanup orphaned EBS snapshots",
+	Long: fmt.Sprintf(`This tool can be used to list or cleanup old and orphaned Elastic Block Store (EBS) snapshots.
+
+A snapshot is orphaned if the volume it was created from doesn't exist anymore and it's not used by any AMI.
+
+Examples:
+%s%s`,
+		snapshotDeleteCmdExamples,
+		snapshotListCmdExamples),
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:     snapshotListCmdName,
+	Aliases: snapshotListCmdAliases,
+	Short:   "List EBS snapshots",
+	Long: fmt.Sprintf(`This command can be used to list own EBS snapshots. Nothing will be deleted.
+
+Examples:
+%s`,
+		snapshotListCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		snapshots := []snapshotOutput{}
+		for _, awsClient := range awsClients() {
+			snapclean := snapclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), viper.GetBool(onlyUnusedFlag), viper.GetStringSlice(ignoreFlag))
+
+			err := snapclean.GetSnapshots()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "snapclean.GetSnapshots() failed: %s", err)
+
+			for _, snapshot := range snapclean.GetAllSnapshots() {
+				snapshots = append(snapshots, snapshotOutput{origin: newOrigin(awsClient), Snapshot: snapshot})
+			}
+		}
+
+		switch viper.GetString(outputFlag) {
+		case "json", "JSON":
+			snapshotPrintJSON(snapshots)
+		default:
+			snapshotPrintTable(snapshots)
+		}
+	},
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:     snapshotDeleteCmdName,
+	Aliases: snapshotDeleteCmdAliases,
+	Short:   "Cleanup orphaned EBS snapshots",
+	Long: fmt.Sprintf(`This command can be used to delete EBS snapshots which are older then the given duration,
+which volume doesn't exist anymore and which are not used by any AMI.
+
+Examples:
+%s`,
+		snapshotDeleteCmdExamples),
+	Run: func(cmd *cobra.Command, args []string) {
+		olderthenDuration := internal.ParseDuration(viper.GetString(olderthenFlag))
+
+		for _, awsClient := range awsClients() {
+			eslog.Logger.Infof("Cleanup EBS snapshots in %s", newOrigin(awsClient))
+
+			snapclean := snapclean.NewInstance(awsClient, olderthenDuration, viper.GetBool(dryrunFlag), true, viper.GetStringSlice(ignoreFlag))
+
+			err := snapclean.DeleteOrphanedSnapshots()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "snapclean.DeleteOrphanedSnapshots() failed: %s", err)
+		}
+	},
+}
+
+func snapshotBindFlags() {
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	rootCmd.AddCommand(snapshotCmd)
+
+	const objType = "EBS snapshots"
+
+	snapshotDeleteCmdFlags := snapshotDeleteCmd.Flags()
+	snapshotDeleteCmdFlags.StringArrayP(ignoreFlag, ignoreFlagSH, []string{}, "Set ignore regex patterns. If the ID, description or Name tag of a snapshot matches the pattern it will be excluded from cleanup.")
+	deleteOnlyFlags(snapshotDeleteCmdFlags)
+
+	snapshotListCmdFlags := snapshotListCmd.Flags()
+	snapshotListCmdFlags.BoolP(onlyUnusedFlag, onlyUnusedFlagSH, false, "defines if only orphaned EBS snapshots are listed or all [Default: false]")
+	listOnlyFlags(snapshotListCmdFlags, objType)
+
+	err := viper.BindPFlags(snapshotListCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+
+	err = viper.BindPFlags(snapshotDeleteCmdFlags)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Failed to bind Flags: %s", err)
+}
+
+type snapshotOutput struct {
+	origin
+	ec2Types.Snapshot
+}
+
+func snapshotPrintTable(snapshots []snapshotOutput) {
+	snapshotTable := table.New("Account", "Region", "Snapshot ID", "Name", "Volume ID", "Start Time", "Size (GiB)")
+	for _, snapshot := range snapshots {
+		// TODO: Conditionally add Tags here.
+		snapshotTable.AddRow(snapshot.Account, snapshot.Region, *snapshot.SnapshotId, snapclean.SnapshotName(snapshot.Snapshot), nilCheck(snapshot.VolumeId), formatTime(snapshot.StartTime), aws.ToInt32(snapshot.VolumeSize))
+	}
+	snapshotTable.Print()
+}
+
+func snapshotPrintJSON(snapshots []snapshotOutput) {
+	out, err := json.Marshal(snapshots)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "Json.Marshal(snapshots) failed: %s", err)
+	fmt.Print(string(out))
+}
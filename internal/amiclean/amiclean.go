@@ -1,8 +1,11 @@
 package amiclean
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/steffakasid/awsclean/internal"
 	"github.com/steffakasid/eslog"
 
@@ -10,15 +13,108 @@ import (
 )
 
 type AmiClean struct {
-	awsClient      *internal.AWS
-	olderthen      time.Duration
-	awsaccount     string
-	dryrun         bool
-	useLaunchTpls  bool
-	onlyUnused     bool
-	usedAMIs       []ec2Types.Image
-	unusedAMIs     []ec2Types.Image
-	ignorePatterns []string
+	awsClient       *internal.AWS
+	olderthen       time.Duration
+	notLaunchedFor  time.Duration
+	awsaccount      string
+	dryrun          bool
+	useLaunchTpls   bool
+	launchTplVers   internal.LaunchTplVersions
+	onlyUnused      bool
+	deleteSnapshots bool
+	strategy        Strategy
+	gracePeriod     time.Duration
+	filters         []ec2Types.Filter
+	retention       retention
+	usedBy          internal.AMIUsage
+	usedAMIs        []ec2Types.Image
+	unusedAMIs      []ec2Types.Image
+	decisions       map[string]Decision
+	ignorePatterns  []string
+}
+
+type Action string
+
+const (
+	ACTION_KEEP      Action = "keep"
+	ACTION_DELETE    Action = "delete"
+	ACTION_DEPRECATE Action = "deprecate"
+	ACTION_DISABLE   Action = "disable"
+)
+
+// Decision tells what happens to an AMI on cleanup and why.
+type Decision struct {
+	Action Action
+	Reason string
+}
+
+// Option is used to set optional behaviour in NewInstance.
+type Option func(*AmiClean)
+
+// WithDeleteSnapshots enables deleting the EBS snapshots of deregistered AMIs.
+func WithDeleteSnapshots(deleteSnapshots bool) Option {
+	return func(a *AmiClean) {
+		a.deleteSnapshots = deleteSnapshots
+	}
+}
+
+// WithLaunchTplVersions sets which launch template versions are scanned for used AMIs if launch
+// templates are used. Defaults to internal.LAUNCH_TPL_LATEST.
+func WithLaunchTplVersions(versions internal.LaunchTplVersions) Option {
+	return func(a *AmiClean) {
+		a.launchTplVers = versions
+	}
+}
+
+// WithNotLaunchedFor additionally requires that an AMI wasn't used to launch an instance for the given
+// duration before it gets deleted. A duration of 0 disables the check.
+func WithNotLaunchedFor(notLaunchedFor time.Duration) Option {
+	return func(a *AmiClean) {
+		a.notLaunchedFor = notLaunchedFor
+	}
+}
+
+// WithFilters sets filters which are applied when describing the AMIs. See ImageFilters().
+func WithFilters(filters []ec2Types.Filter) Option {
+	return func(a *AmiClean) {
+		a.filters = filters
+	}
+}
+
+// ImageFilters builds the DescribeImages filters. Name patterns can use * and ? as wildcards. Tags are
+// either given as key=value or as key only to match all images having the tag.
+func ImageFilters(namePatterns, architectures, states, tags []string) []ec2Types.Filter {
+	filters := []ec2Types.Filter{}
+
+	if len(namePatterns) > 0 {
+		filters = append(filters, ec2Types.Filter{Name: aws.String("name"), Values: namePatterns})
+	}
+	if len(architectures) > 0 {
+		filters = append(filters, ec2Types.Filter{Name: aws.String("architecture"), Values: architectures})
+	}
+	if len(states) > 0 {
+		filters = append(filters, ec2Types.Filter{Name: aws.String("state"), Values: states})
+	}
+	for _, tag := range tags {
+		if key, value, found := strings.Cut(tag, "="); found {
+			filters = append(filters, ec2Types.Filter{Name: aws.String("tag:" + key), Values: []string{value}})
+		} else {
+			filters = append(filters, ec2Types.Filter{Name: aws.String("tag-key"), Values: []string{key}})
+		}
+	}
+	return filters
+}
+
+// WithRetention always keeps the newest keepNewest AMIs of each family. The family of an AMI is either
+// the first capture group of familyPattern matched against the AMI name or the value of the tag familyTag.
+func WithRetention(keepNewest int, familyPattern, familyTag string) Option {
+	return func(a *AmiClean) {
+		a.retention = retention{
+			keepNewest:    keepNewest,
+			familyPattern: familyPattern,
+			familyTag:     familyTag,
+		}
+	}
 }
 
 func NewInstance(
@@ -27,45 +123,133 @@ func NewInstance(
 	dryrun bool,
 	onlyunused bool,
 	useLaunchTpls bool,
-	ignorePatterns []string) *AmiClean {
+	ignorePatterns []string,
+	opts ...Option) *AmiClean {
 
-	return &AmiClean{
+	amiclean := &AmiClean{
 		awsClient:      awsClient,
 		olderthen:      olderthen,
 		awsaccount:     awsaccount,
 		dryrun:         dryrun,
 		onlyUnused:     onlyunused,
 		useLaunchTpls:  useLaunchTpls,
+		launchTplVers:  internal.LAUNCH_TPL_LATEST,
+		strategy:       STRATEGY_DEREGISTER,
 		usedAMIs:       []ec2Types.Image{},
 		unusedAMIs:     []ec2Types.Image{},
 		ignorePatterns: ignorePatterns,
 	}
+	for _, opt := range opts {
+		opt(amiclean)
+	}
+	return amiclean
 }
 
 func (a *AmiClean) GetAMIs() error {
-	usedAMIs := []string{}
-	usedAMIs = append(usedAMIs, a.awsClient.GetUsedAMIsFromEC2()...)
+	a.usedBy = internal.AMIUsage{}
+	for _, imageId := range a.awsClient.GetUsedAMIsFromEC2() {
+		a.usedBy.Add(imageId, "EC2 instance")
+	}
+
+	launchConfigAMIs, err := a.awsClient.GetUsedAMIsFromLaunchConfigs()
+	if err != nil {
+		return fmt.Errorf("could not get AMIs used by launch configurations: %w", err)
+	}
+	a.usedBy.Merge(launchConfigAMIs)
+
+	autoScalingAMIs, err := a.awsClient.GetUsedAMIsFromAutoScalingGroups()
+	if err != nil {
+		return fmt.Errorf("could not get AMIs used by Auto Scaling groups: %w", err)
+	}
+	a.usedBy.Merge(autoScalingAMIs)
 
 	if a.useLaunchTpls {
-		usedAMIs = append(usedAMIs, a.awsClient.GetUsedAMIsFromLaunchTpls()...)
+		launchTplAMIs, err := a.awsClient.GetUsedAMIsFromLaunchTpls(a.launchTplVers)
+		if err != nil {
+			return fmt.Errorf("could not get AMIs used by launch templates: %w", err)
+		}
+		a.usedBy.Merge(launchTplAMIs)
 	}
 
-	images, err := a.awsClient.DescribeImages(a.awsaccount)
+	images, err := a.awsClient.DescribeImages(a.awsaccount, a.includeDisabled(), a.filters...)
 	if err != nil {
 		return err
 	}
 
 	for _, image := range images {
-		if !internal.Contains(usedAMIs, *image.ImageId) {
-			a.unusedAMIs = append(a.unusedAMIs, image)
-		} else {
+		if usedBy, used := a.usedBy[*image.ImageId]; used {
 			a.usedAMIs = append(a.usedAMIs, image)
-			eslog.Logger.Infof("Ignored %s", *image.ImageId)
+			eslog.Logger.Infof("Ignored %s used by %s", *image.ImageId, usedBy)
+		} else {
+			a.unusedAMIs = append(a.unusedAMIs, image)
+		}
+	}
+	return a.decide()
+}
+
+// decide sets the Decision for each AMI. Used AMIs, AMIs matching an ignore pattern and AMIs which are
+// retained are always kept. All other AMIs are deleted if they are older then olderthen.
+func (a *AmiClean) decide() error {
+	a.decisions = map[string]Decision{}
+
+	for _, ami := range a.usedAMIs {
+		a.decisions[*ami.ImageId] = Decision{Action: ACTION_KEEP, Reason: "in use by " + a.usedBy[*ami.ImageId]}
+	}
+
+	retained, err := a.retention.retainedAMIs(append(append([]ec2Types.Image{}, a.usedAMIs...), a.unusedAMIs...))
+	if err != nil {
+		return err
+	}
+
+	olderThenDate := time.Now().Add(a.olderthen * -1)
+
+	for _, ami := range a.unusedAMIs {
+		ignored, err := internal.MatchAny(*ami.Name, a.ignorePatterns)
+		if err != nil {
+			return err
+		}
+		if ignored {
+			a.decisions[*ami.ImageId] = Decision{Action: ACTION_KEEP, Reason: "matches ignore pattern"}
+			continue
+		}
+
+		if reason, ok := retained[*ami.ImageId]; ok {
+			a.decisions[*ami.ImageId] = Decision{Action: ACTION_KEEP, Reason: reason}
+			continue
+		}
+
+		creationDate, err := time.Parse("2006-01-02T15:04:05.000Z", *ami.CreationDate)
+		if err != nil {
+			return err
+		}
+		if !creationDate.Before(olderThenDate) {
+			a.decisions[*ami.ImageId] = Decision{Action: ACTION_KEEP, Reason: fmt.Sprintf("creationdate %s is newer then %s", *ami.CreationDate, olderThenDate.Format(time.RFC3339))}
+			continue
+		}
+
+		reason := fmt.Sprintf("creationdate %s is older then %s", *ami.CreationDate, olderThenDate.Format(time.RFC3339))
+		if a.notLaunchedFor > 0 && ami.LastLaunchedTime != nil {
+			lastLaunchedTime, err := time.Parse(time.RFC3339, *ami.LastLaunchedTime)
+			if err != nil {
+				return err
+			}
+			notLaunchedSince := time.Now().Add(a.notLaunchedFor * -1)
+			if !lastLaunchedTime.Before(notLaunchedSince) {
+				a.decisions[*ami.ImageId] = Decision{Action: ACTION_KEEP, Reason: fmt.Sprintf("last launched %s is newer then %s", *ami.LastLaunchedTime, notLaunchedSince.Format(time.RFC3339))}
+				continue
+			}
+			reason = fmt.Sprintf("%s and last launched %s is older then %s", reason, *ami.LastLaunchedTime, notLaunchedSince.Format(time.RFC3339))
 		}
+		a.decisions[*ami.ImageId] = a.softDelete(ami, reason)
 	}
 	return nil
 }
 
+// GetDecision returns what happens to the AMI with the given ID on cleanup.
+func (a AmiClean) GetDecision(imageId string) Decision {
+	return a.decisions[imageId]
+}
+
 func (a AmiClean) GetAllAMIs() []ec2Types.Image {
 	all := []ec2Types.Image{}
 
@@ -83,29 +267,122 @@ func (a AmiClean) DeleteOlderUnusedAMIs() error {
 		return err
 	}
 
-	today := time.Now()
-	olderThenDate := today.Add(a.olderthen * -1)
+	deregistered := []ec2Types.Image{}
+	deprecated := 0
+	disabled := 0
+	skipped := 0
 
 	for _, ami := range a.unusedAMIs {
-		ok, err := internal.MatchAny(*ami.Name, a.ignorePatterns)
+		decision := a.decisions[*ami.ImageId]
+		switch decision.Action {
+		case ACTION_DELETE:
+			eslog.Logger.Infof("Delete %s:%s, %s", *ami.ImageId, *ami.Name, decision.Reason)
+			err = a.awsClient.DeregisterImage(*ami.ImageId, a.dryrun)
+			if err != nil && !internal.IsDryRunOperation(err) {
+				eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeregisterImage(): %s")
+				skipped++
+				continue
+			}
+			deregistered = append(deregistered, ami)
+		case ACTION_DEPRECATE:
+			eslog.Logger.Infof("Deprecate %s:%s, %s", *ami.ImageId, *ami.Name, decision.Reason)
+			// AWS doesn't accept a deprecation time in the past
+			err = a.awsClient.EnableImageDeprecation(*ami.ImageId, time.Now().Add(time.Minute), a.dryrun)
+			if err != nil && !internal.IsDryRunOperation(err) {
+				eslog.LogIfErrorf(err, eslog.Errorf, "Error on EnableImageDeprecation(): %s")
+				skipped++
+				continue
+			}
+			deprecated++
+		case ACTION_DISABLE:
+			eslog.Logger.Infof("Disable %s:%s, %s", *ami.ImageId, *ami.Name, decision.Reason)
+			// tag first, an AMI disabled without the tag would never be deregistered
+			err = a.awsClient.CreateTag(*ami.ImageId, DISABLED_AT_TAG, time.Now().UTC().Format(time.RFC3339), a.dryrun)
+			if err != nil && !internal.IsDryRunOperation(err) {
+				eslog.LogIfErrorf(err, eslog.Errorf, "Error on CreateTag(): %s")
+				skipped++
+				continue
+			}
+			err = a.awsClient.DisableImage(*ami.ImageId, a.dryrun)
+			if err != nil && !internal.IsDryRunOperation(err) {
+				eslog.LogIfErrorf(err, eslog.Errorf, "Error on DisableImage(): %s")
+				skipped++
+				continue
+			}
+			disabled++
+		default:
+			eslog.Logger.Infof("Keeping %s:%s, %s", *ami.ImageId, *ami.Name, decision.Reason)
+			skipped++
+		}
+	}
+
+	eslog.Logger.Infof("Deregistered %d, Deprecated %d, Disabled %d, Skipped %d AMIs", len(deregistered), deprecated, disabled, skipped)
+
+	if a.deleteSnapshots {
+		deletedSnapshots, err := a.deleteSnapshotsOf(deregistered)
 		if err != nil {
 			return err
 		}
-		if !ok {
-			creationDate, err := time.Parse("2006-01-02T15:04:05.000Z", *ami.CreationDate)
-			if err != nil {
-				return err
+		eslog.Logger.Infof("Deleted %d EBS snapshots %v", len(deletedSnapshots), deletedSnapshots)
+	}
+	return nil
+}
+
+// deleteSnapshotsOf deletes the EBS snapshots of the given deregistered AMIs. Snapshots which are
+// still used by another AMI are skipped.
+func (a AmiClean) deleteSnapshotsOf(deregistered []ec2Types.Image) (deleted []string, err error) {
+	deregisteredIDs := []string{}
+	for _, ami := range deregistered {
+		deregisteredIDs = append(deregisteredIDs, *ami.ImageId)
+	}
+
+	allImages := append(append([]ec2Types.Image{}, a.usedAMIs...), a.unusedAMIs...)
+	if len(a.filters) > 0 || !a.includeDisabled() {
+		// AMIs excluded by the filters and disabled AMIs could still use the snapshots
+		allImages, err = a.awsClient.DescribeImages(a.awsaccount, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stillUsed := []string{}
+	for _, image := range allImages {
+		if !internal.Contains(deregisteredIDs, *image.ImageId) {
+			for _, snapshotID := range snapshotIDs(image) {
+				stillUsed = internal.UniqueAppend(stillUsed, snapshotID)
 			}
-			if creationDate.Before(olderThenDate) {
-				eslog.Logger.Infof("Delete %s:%s as it's creationdate %s is older then %s", *ami.ImageId, *ami.Name, *ami.CreationDate, olderThenDate.String())
-				err = a.awsClient.DeregisterImage(*ami.ImageId, a.dryrun)
-				eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeregisterImage(): %s")
-			} else {
-				eslog.Logger.Infof("Keeping %s:%s as it's creationdate %s is newer then %s", *ami.ImageId, *ami.Name, *ami.CreationDate, olderThenDate.String())
+		}
+	}
+
+	deleted = []string{}
+	for _, ami := range deregistered {
+		for _, snapshotID := range snapshotIDs(ami) {
+			if internal.Contains(stillUsed, snapshotID) {
+				eslog.Logger.Infof("Skipping snapshot %s of %s as it's still used by another AMI", snapshotID, *ami.ImageId)
+				continue
 			}
-		} else {
-			eslog.Logger.Infof("Skipping %s-%s", *ami.ImageId, *ami.Name)
+			if internal.Contains(deleted, snapshotID) {
+				continue
+			}
+
+			eslog.Logger.Infof("Delete snapshot %s of %s", snapshotID, *ami.ImageId)
+			err := a.awsClient.DeleteSnapshot(snapshotID, a.dryrun)
+			if err != nil && !internal.IsDryRunOperation(err) {
+				eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeleteSnapshot(): %s")
+				continue
+			}
+			deleted = append(deleted, snapshotID)
 		}
 	}
-	return nil
+	return deleted, nil
+}
+
+func snapshotIDs(image ec2Types.Image) []string {
+	ids := []string{}
+	for _, mapping := range image.BlockDeviceMappings {
+		if mapping.Ebs != nil && mapping.Ebs.SnapshotId != nil {
+			ids = append(ids, *mapping.Ebs.SnapshotId)
+		}
+	}
+	return ids
 }
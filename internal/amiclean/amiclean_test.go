@@ -7,8 +7,11 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	autoscalingTypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
 	"github.com/google/uuid"
 	"github.com/steffakasid/awsclean/internal"
 	"github.com/steffakasid/awsclean/internal/mocks"
@@ -26,8 +29,11 @@ func setupSUT(
 	ignorePatterns []string) (*AmiClean, *mocks.MockEc2client, *mocks.MockCloudTrail) {
 	ec2ClientMock := &mocks.MockEc2client{}
 	cloudTrailMock := &mocks.MockCloudTrail{}
+	autoScalingMock := &mocks.MockAutoScaling{}
+	autoScalingMock.EXPECT().DescribeLaunchConfigurations(context.TODO(), &autoscaling.DescribeLaunchConfigurationsInput{}).Return(&autoscaling.DescribeLaunchConfigurationsOutput{}, nil)
+	autoScalingMock.EXPECT().DescribeAutoScalingGroups(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{}).Return(&autoscaling.DescribeAutoScalingGroupsOutput{}, nil)
 
-	awsClient := internal.NewFromInterface(ec2ClientMock, cloudTrailMock)
+	awsClient := internal.NewFromInterface(ec2ClientMock, cloudTrailMock, internal.WithAutoScaling(autoScalingMock))
 	return NewInstance(awsClient, olderthen, awsaccount, dryrun, onlyUnused, useLaunchTpls, ignorePatterns), ec2ClientMock, cloudTrailMock
 }
 
@@ -139,6 +145,95 @@ func TestGetUsedAmis(t *testing.T) {
 	})
 }
 
+func TestGetUsedAmisFromAutoScaling(t *testing.T) {
+	defaultOlderthen, err := str2duration.ParseDuration("7d")
+	require.NoError(t, err)
+
+	setup := func(t *testing.T) (*AmiClean, *mocks.MockEc2client, *mocks.MockAutoScaling) {
+		ec2ClientMock := mocks.NewMockEc2client(t)
+		autoScalingMock := mocks.NewMockAutoScaling(t)
+		awsClient := internal.NewFromInterface(ec2ClientMock, mocks.NewMockCloudTrail(t), internal.WithAutoScaling(autoScalingMock))
+		return NewInstance(awsClient, defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns), ec2ClientMock, autoScalingMock
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		amiclean, ec2ClientMock, autoScalingMock := setup(t)
+
+		mockDescribeInstances(1, ec2ClientMock)
+
+		autoScalingMock.EXPECT().DescribeLaunchConfigurations(context.TODO(), &autoscaling.DescribeLaunchConfigurationsInput{}).Return(&autoscaling.DescribeLaunchConfigurationsOutput{
+			LaunchConfigurations: []autoscalingTypes.LaunchConfiguration{{ImageId: aws.String("ami-launch-config")}},
+			NextToken:            aws.String("next"),
+		}, nil).Once()
+		autoScalingMock.EXPECT().DescribeLaunchConfigurations(context.TODO(), &autoscaling.DescribeLaunchConfigurationsInput{NextToken: aws.String("next")}).Return(&autoscaling.DescribeLaunchConfigurationsOutput{
+			LaunchConfigurations: []autoscalingTypes.LaunchConfiguration{{ImageId: aws.String("ami-launch-config-2")}},
+		}, nil).Once()
+
+		pinned := &autoscalingTypes.LaunchTemplateSpecification{LaunchTemplateId: aws.String("lt-pinned"), Version: aws.String("3")}
+		autoScalingMock.EXPECT().DescribeAutoScalingGroups(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{}).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []autoscalingTypes.AutoScalingGroup{
+				{LaunchTemplate: pinned},
+				{LaunchTemplate: pinned},
+				{MixedInstancesPolicy: &autoscalingTypes.MixedInstancesPolicy{
+					LaunchTemplate: &autoscalingTypes.LaunchTemplate{
+						LaunchTemplateSpecification: &autoscalingTypes.LaunchTemplateSpecification{LaunchTemplateName: aws.String("mixed")},
+						Overrides: []autoscalingTypes.LaunchTemplateOverrides{
+							{ImageId: aws.String("ami-override")},
+						},
+					},
+				}},
+			},
+		}, nil).Once()
+
+		mockLaunchTplVersion := func(input *ec2.DescribeLaunchTemplateVersionsInput, imageId string) {
+			ec2ClientMock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), input).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+				LaunchTemplateVersions: []types.LaunchTemplateVersion{{LaunchTemplateData: &types.ResponseLaunchTemplateData{ImageId: aws.String(imageId)}}},
+			}, nil).Once()
+		}
+		mockLaunchTplVersion(&ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String("lt-pinned"), Versions: []string{"3"}}, "ami-pinned")
+		mockLaunchTplVersion(&ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateName: aws.String("mixed"), Versions: []string{"$Default"}}, "ami-mixed")
+
+		images := []types.Image{}
+		for _, imageId := range []string{"ami-launch-config", "ami-launch-config-2", "ami-pinned", "ami-mixed", "ami-override", "ami-unused"} {
+			images = append(images, types.Image{ImageId: aws.String(imageId), Name: aws.String(imageId), CreationDate: aws.String("2006-01-02T15:04:05.000Z")})
+		}
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}}).Return(&ec2.DescribeImagesOutput{Images: images}, nil).Once()
+
+		err := amiclean.GetAMIs()
+		require.NoError(t, err)
+		assert.Len(t, amiclean.usedAMIs, 5)
+		require.Len(t, amiclean.unusedAMIs, 1)
+		assert.Equal(t, "ami-unused", *amiclean.unusedAMIs[0].ImageId)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		amiclean, ec2ClientMock, autoScalingMock := setup(t)
+
+		mockDescribeInstances(1, ec2ClientMock)
+		autoScalingMock.EXPECT().DescribeLaunchConfigurations(context.TODO(), &autoscaling.DescribeLaunchConfigurationsInput{}).Return(&autoscaling.DescribeLaunchConfigurationsOutput{}, nil).Once()
+		autoScalingMock.EXPECT().DescribeAutoScalingGroups(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{}).Return(nil, errors.New("some error")).Once()
+
+		err := amiclean.GetAMIs()
+		require.EqualError(t, err, "could not get AMIs used by Auto Scaling groups: some error")
+	})
+
+	t.Run("Without Auto Scaling client", func(t *testing.T) {
+		ec2ClientMock := mocks.NewMockEc2client(t)
+		awsClient := internal.NewFromInterface(ec2ClientMock, mocks.NewMockCloudTrail(t))
+		amiclean := NewInstance(awsClient, defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+
+		mockDescribeInstances(1, ec2ClientMock)
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}}).Return(&ec2.DescribeImagesOutput{
+			Images: []types.Image{{ImageId: aws.String("ami-unused"), Name: aws.String("ami-unused"), CreationDate: aws.String("2006-01-02T15:04:05.000Z")}},
+		}, nil).Once()
+
+		err := amiclean.GetAMIs()
+		require.NoError(t, err)
+		assert.Empty(t, amiclean.usedAMIs)
+		assert.Len(t, amiclean.unusedAMIs, 1)
+	})
+}
+
 func TestDeleteOlderUnusedAMIs(t *testing.T) {
 	defaultOlderthen, err := str2duration.ParseDuration("7d")
 	assert.NoError(t, err)
@@ -399,6 +494,252 @@ func TestDeleteOlderUnusedAMIs(t *testing.T) {
 	})
 }
 
+func TestDeleteSnapshotsOfDeregisteredAMIs(t *testing.T) {
+	defaultOlderthen, err := str2duration.ParseDuration("7d")
+	require.NoError(t, err)
+
+	imageWithSnapshots := func(id string, snapshotIDs ...string) types.Image {
+		image := types.Image{
+			ImageId:      aws.String(id),
+			Name:         aws.String(id),
+			CreationDate: aws.String("2006-01-02T15:04:05.000Z"),
+		}
+		for _, snapshotID := range snapshotIDs {
+			image.BlockDeviceMappings = append(image.BlockDeviceMappings, types.BlockDeviceMapping{
+				Ebs: &types.EbsBlockDevice{SnapshotId: aws.String(snapshotID)},
+			})
+		}
+		return image
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+		amiclean.deleteSnapshots = true
+
+		usedAMIs := mockDescribeInstances(1, ec2ClientMock)
+
+		input := &ec2.DescribeImagesInput{Owners: []string{"self"}}
+		response := &ec2.DescribeImagesOutput{
+			Images: []types.Image{
+				imageWithSnapshots("to-be-deleted-id", "snap-1", "snap-shared"),
+				imageWithSnapshots(usedAMIs[0], "snap-shared"),
+			},
+		}
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), input).Return(response, nil).Once()
+		// disabled AMIs aren't listed but could still use the snapshots
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}, IncludeDisabled: aws.Bool(true)}).Return(response, nil).Once()
+		derregisterInput := &ec2.DeregisterImageInput{ImageId: aws.String("to-be-deleted-id"), DryRun: aws.Bool(noDryrun)}
+		ec2ClientMock.EXPECT().DeregisterImage(context.TODO(), derregisterInput).Return(nil, nil).Once()
+		deleteSnapshotInput := &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-1"), DryRun: aws.Bool(noDryrun)}
+		ec2ClientMock.EXPECT().DeleteSnapshot(context.TODO(), deleteSnapshotInput).Return(&ec2.DeleteSnapshotOutput{}, nil).Once()
+
+		err = amiclean.DeleteOlderUnusedAMIs()
+		assert.NoError(t, err)
+		ec2ClientMock.AssertExpectations(t)
+		ec2ClientMock.AssertNotCalled(t, "DeleteSnapshot", context.TODO(), &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-shared"), DryRun: aws.Bool(noDryrun)})
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, true, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+		amiclean.deleteSnapshots = true
+
+		mockDescribeInstances(1, ec2ClientMock)
+
+		input := &ec2.DescribeImagesInput{Owners: []string{"self"}}
+		response := &ec2.DescribeImagesOutput{
+			Images: []types.Image{
+				imageWithSnapshots("to-be-deleted-id", "snap-1"),
+			},
+		}
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), input).Return(response, nil).Once()
+		// disabled AMIs aren't listed but could still use the snapshots
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}, IncludeDisabled: aws.Bool(true)}).Return(response, nil).Once()
+		dryRunErr := &smithy.GenericAPIError{Code: "DryRunOperation", Message: "Request would have succeeded, but DryRun flag is set."}
+		derregisterInput := &ec2.DeregisterImageInput{ImageId: aws.String("to-be-deleted-id"), DryRun: aws.Bool(true)}
+		ec2ClientMock.EXPECT().DeregisterImage(context.TODO(), derregisterInput).Return(nil, dryRunErr).Once()
+		deleteSnapshotInput := &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-1"), DryRun: aws.Bool(true)}
+		ec2ClientMock.EXPECT().DeleteSnapshot(context.TODO(), deleteSnapshotInput).Return(nil, dryRunErr).Once()
+
+		err = amiclean.DeleteOlderUnusedAMIs()
+		assert.NoError(t, err)
+		ec2ClientMock.AssertExpectations(t)
+	})
+
+	t.Run("Deregister Failed", func(t *testing.T) {
+		amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+		amiclean.deleteSnapshots = true
+
+		mockDescribeInstances(1, ec2ClientMock)
+
+		input := &ec2.DescribeImagesInput{Owners: []string{"self"}}
+		response := &ec2.DescribeImagesOutput{
+			Images: []types.Image{
+				imageWithSnapshots("to-be-deleted-id", "snap-1"),
+			},
+		}
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), input).Return(response, nil).Once()
+		// disabled AMIs aren't listed but could still use the snapshots
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}, IncludeDisabled: aws.Bool(true)}).Return(response, nil).Once()
+		derregisterInput := &ec2.DeregisterImageInput{ImageId: aws.String("to-be-deleted-id"), DryRun: aws.Bool(noDryrun)}
+		ec2ClientMock.EXPECT().DeregisterImage(context.TODO(), derregisterInput).Return(nil, errors.New("Some Error")).Once()
+
+		err = amiclean.DeleteOlderUnusedAMIs()
+		assert.NoError(t, err)
+		ec2ClientMock.AssertExpectations(t)
+		ec2ClientMock.AssertNotCalled(t, "DeleteSnapshot", context.TODO(), &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-1"), DryRun: aws.Bool(noDryrun)})
+	})
+}
+
+func TestDecisions(t *testing.T) {
+	defaultOlderthen, err := str2duration.ParseDuration("7d")
+	require.NoError(t, err)
+
+	t.Run("Reasons", func(t *testing.T) {
+		ignorePatterns := []string{"^ignored.*"}
+		amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, ignorePatterns)
+		amiclean.retention = retention{keepNewest: 1, familyPattern: `^(golden)-\d+$`}
+
+		usedAMIs := mockDescribeInstances(1, ec2ClientMock)
+		creationDate := time.Now().Format("2006-01-02T15:04:05.000Z")
+
+		input := &ec2.DescribeImagesInput{Owners: []string{"self"}}
+		response := &ec2.DescribeImagesOutput{
+			Images: []types.Image{
+				{ImageId: aws.String(usedAMIs[0]), Name: aws.String("in-use"), CreationDate: aws.String("2006-01-02T15:04:05.000Z")},
+				{ImageId: aws.String("ignored-id"), Name: aws.String("ignored"), CreationDate: aws.String("2006-01-02T15:04:05.000Z")},
+				{ImageId: aws.String("golden-1-id"), Name: aws.String("golden-1"), CreationDate: aws.String("2006-01-02T15:04:05.000Z")},
+				{ImageId: aws.String("golden-2-id"), Name: aws.String("golden-2"), CreationDate: aws.String("2006-01-03T15:04:05.000Z")},
+				{ImageId: aws.String("to-young-id"), Name: aws.String("to-young"), CreationDate: aws.String(creationDate)},
+			},
+		}
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), input).Return(response, nil).Once()
+
+		err := amiclean.GetAMIs()
+		require.NoError(t, err)
+		assert.Equal(t, Decision{Action: ACTION_KEEP, Reason: "in use by EC2 instance"}, amiclean.GetDecision(usedAMIs[0]))
+		assert.Equal(t, Decision{Action: ACTION_KEEP, Reason: "matches ignore pattern"}, amiclean.GetDecision("ignored-id"))
+		assert.Equal(t, Decision{Action: ACTION_KEEP, Reason: "one of the 1 newest AMIs of family golden"}, amiclean.GetDecision("golden-2-id"))
+		assert.Equal(t, ACTION_DELETE, amiclean.GetDecision("golden-1-id").Action)
+		assert.Equal(t, ACTION_KEEP, amiclean.GetDecision("to-young-id").Action)
+	})
+
+	t.Run("Keep Newest Of Family When Deleting", func(t *testing.T) {
+		amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+		amiclean.retention = retention{keepNewest: 1, familyTag: "Family"}
+
+		mockDescribeInstances(1, ec2ClientMock)
+
+		familyTag := []types.Tag{{Key: aws.String("Family"), Value: aws.String("golden")}}
+		input := &ec2.DescribeImagesInput{Owners: []string{"self"}}
+		response := &ec2.DescribeImagesOutput{
+			Images: []types.Image{
+				{ImageId: aws.String("old-id"), Name: aws.String("old"), CreationDate: aws.String("2006-01-02T15:04:05.000Z"), Tags: familyTag},
+				{ImageId: aws.String("newest-id"), Name: aws.String("newest"), CreationDate: aws.String("2006-02-02T15:04:05.000Z"), Tags: familyTag},
+			},
+		}
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), input).Return(response, nil).Once()
+		derregisterInput := &ec2.DeregisterImageInput{ImageId: aws.String("old-id"), DryRun: aws.Bool(noDryrun)}
+		ec2ClientMock.EXPECT().DeregisterImage(context.TODO(), derregisterInput).Return(nil, nil).Once()
+
+		err := amiclean.DeleteOlderUnusedAMIs()
+		require.NoError(t, err)
+		ec2ClientMock.AssertExpectations(t)
+	})
+}
+
+func TestNotLaunchedFor(t *testing.T) {
+	defaultOlderthen, err := str2duration.ParseDuration("7d")
+	require.NoError(t, err)
+
+	amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+	WithNotLaunchedFor(30 * 24 * time.Hour)(amiclean)
+
+	mockDescribeInstances(1, ec2ClientMock)
+
+	recently := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	longAgo := time.Now().Add(-60 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	response := &ec2.DescribeImagesOutput{
+		Images: []types.Image{
+			{ImageId: aws.String("launched-recently-id"), Name: aws.String("launched-recently"), CreationDate: aws.String("2006-01-02T15:04:05.000Z"), LastLaunchedTime: aws.String(recently)},
+			{ImageId: aws.String("launched-long-ago-id"), Name: aws.String("launched-long-ago"), CreationDate: aws.String("2006-01-02T15:04:05.000Z"), LastLaunchedTime: aws.String(longAgo)},
+			{ImageId: aws.String("never-launched-id"), Name: aws.String("never-launched"), CreationDate: aws.String("2006-01-02T15:04:05.000Z")},
+		},
+	}
+	ec2ClientMock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}}).Return(response, nil).Once()
+
+	err = amiclean.GetAMIs()
+	require.NoError(t, err)
+	assert.Equal(t, ACTION_KEEP, amiclean.GetDecision("launched-recently-id").Action)
+	assert.Equal(t, ACTION_DELETE, amiclean.GetDecision("launched-long-ago-id").Action)
+	assert.Contains(t, amiclean.GetDecision("launched-long-ago-id").Reason, "last launched "+longAgo)
+	assert.Equal(t, ACTION_DELETE, amiclean.GetDecision("never-launched-id").Action)
+	ec2ClientMock.AssertExpectations(t)
+}
+
+func TestImageFilters(t *testing.T) {
+
+	t.Run("No Filters", func(t *testing.T) {
+		assert.Empty(t, ImageFilters(nil, nil, nil, nil))
+	})
+
+	t.Run("All Filters", func(t *testing.T) {
+		filters := ImageFilters([]string{"build-*"}, []string{"x86_64", "arm64"}, []string{"available"}, []string{"Family=golden", "Team"})
+		assert.Equal(t, []types.Filter{
+			{Name: aws.String("name"), Values: []string{"build-*"}},
+			{Name: aws.String("architecture"), Values: []string{"x86_64", "arm64"}},
+			{Name: aws.String("state"), Values: []string{"available"}},
+			{Name: aws.String("tag:Family"), Values: []string{"golden"}},
+			{Name: aws.String("tag-key"), Values: []string{"Team"}},
+		}, filters)
+	})
+}
+
+func TestDeleteWithFilters(t *testing.T) {
+	defaultOlderthen, err := str2duration.ParseDuration("7d")
+	require.NoError(t, err)
+
+	t.Run("Keep Snapshots Of Filtered AMIs", func(t *testing.T) {
+		filters := ImageFilters([]string{"build-*"}, nil, nil, nil)
+		amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+		amiclean.deleteSnapshots = true
+		amiclean.filters = filters
+
+		mockDescribeInstances(1, ec2ClientMock)
+
+		toBeDeleted := types.Image{
+			ImageId:      aws.String("to-be-deleted-id"),
+			Name:         aws.String("build-1"),
+			CreationDate: aws.String("2006-01-02T15:04:05.000Z"),
+			BlockDeviceMappings: []types.BlockDeviceMapping{
+				{Ebs: &types.EbsBlockDevice{SnapshotId: aws.String("snap-1")}},
+				{Ebs: &types.EbsBlockDevice{SnapshotId: aws.String("snap-shared")}},
+			},
+		}
+		filteredOut := types.Image{
+			ImageId:      aws.String("filtered-out-id"),
+			Name:         aws.String("golden-1"),
+			CreationDate: aws.String("2006-01-02T15:04:05.000Z"),
+			BlockDeviceMappings: []types.BlockDeviceMapping{
+				{Ebs: &types.EbsBlockDevice{SnapshotId: aws.String("snap-shared")}},
+			},
+		}
+
+		filteredInput := &ec2.DescribeImagesInput{Owners: []string{"self"}, Filters: filters}
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), filteredInput).Return(&ec2.DescribeImagesOutput{Images: []types.Image{toBeDeleted}}, nil).Once()
+		unfilteredInput := &ec2.DescribeImagesInput{Owners: []string{"self"}, IncludeDisabled: aws.Bool(true)}
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), unfilteredInput).Return(&ec2.DescribeImagesOutput{Images: []types.Image{toBeDeleted, filteredOut}}, nil).Once()
+
+		derregisterInput := &ec2.DeregisterImageInput{ImageId: aws.String("to-be-deleted-id"), DryRun: aws.Bool(noDryrun)}
+		ec2ClientMock.EXPECT().DeregisterImage(context.TODO(), derregisterInput).Return(nil, nil).Once()
+		deleteSnapshotInput := &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-1"), DryRun: aws.Bool(noDryrun)}
+		ec2ClientMock.EXPECT().DeleteSnapshot(context.TODO(), deleteSnapshotInput).Return(&ec2.DeleteSnapshotOutput{}, nil).Once()
+
+		err = amiclean.DeleteOlderUnusedAMIs()
+		assert.NoError(t, err)
+		ec2ClientMock.AssertExpectations(t)
+	})
+}
+
 func mockDescribeInstances(numCalls int, ec2ClientMock *mocks.MockEc2client, errCalls ...int) (imageIds []string) {
 	nextToken := ""
 	for i := 1; i <= numCalls; i++ {
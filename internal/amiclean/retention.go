@@ -0,0 +1,72 @@
+package amiclean
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+type retention struct {
+	keepNewest    int
+	familyPattern string
+	familyTag     string
+}
+
+// retainedAMIs returns the IDs of the newest AMIs per family together with the reason why they are kept.
+func (r retention) retainedAMIs(images []ec2Types.Image) (map[string]string, error) {
+	retained := map[string]string{}
+	if r.keepNewest <= 0 {
+		return retained, nil
+	}
+
+	var familyRegex *regexp.Regexp
+	if r.familyPattern != "" {
+		var err error
+		familyRegex, err = regexp.Compile(r.familyPattern)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	families := map[string][]ec2Types.Image{}
+	for _, image := range images {
+		if family := r.family(image, familyRegex); family != "" {
+			families[family] = append(families[family], image)
+		}
+	}
+
+	for family, familyImages := range families {
+		// CreationDate uses a fixed ISO 8601 format so it can be compared as string
+		slices.SortFunc(familyImages, func(a, b ec2Types.Image) int {
+			return strings.Compare(*b.CreationDate, *a.CreationDate)
+		})
+		for _, image := range familyImages[:min(r.keepNewest, len(familyImages))] {
+			retained[*image.ImageId] = fmt.Sprintf("one of the %d newest AMIs of family %s", r.keepNewest, family)
+		}
+	}
+	return retained, nil
+}
+
+// family returns the family of the image or an empty string if the image doesn't belong to a family.
+func (r retention) family(image ec2Types.Image, familyRegex *regexp.Regexp) string {
+	if r.familyTag != "" {
+		for _, tag := range image.Tags {
+			if tag.Key != nil && *tag.Key == r.familyTag && tag.Value != nil {
+				return *tag.Value
+			}
+		}
+	}
+
+	if familyRegex != nil && image.Name != nil {
+		match := familyRegex.FindStringSubmatch(*image.Name)
+		if len(match) > 1 {
+			return match[1]
+		} else if len(match) == 1 {
+			return match[0]
+		}
+	}
+	return ""
+}
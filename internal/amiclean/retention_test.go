@@ -0,0 +1,57 @@
+package amiclean
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetainedAMIs(t *testing.T) {
+	image := func(id, name, creationDate string, tags ...types.Tag) types.Image {
+		return types.Image{
+			ImageId:      aws.String(id),
+			Name:         aws.String(name),
+			CreationDate: aws.String(creationDate),
+			Tags:         tags,
+		}
+	}
+	images := []types.Image{
+		image("ami-1", "golden-ubuntu-1", "2024-01-01T00:00:00.000Z", types.Tag{Key: aws.String("Family"), Value: aws.String("ubuntu")}),
+		image("ami-2", "golden-ubuntu-2", "2024-02-01T00:00:00.000Z", types.Tag{Key: aws.String("Family"), Value: aws.String("ubuntu")}),
+		image("ami-3", "golden-ubuntu-3", "2024-03-01T00:00:00.000Z", types.Tag{Key: aws.String("Family"), Value: aws.String("ubuntu")}),
+		image("ami-4", "golden-debian-1", "2024-01-01T00:00:00.000Z", types.Tag{Key: aws.String("Family"), Value: aws.String("debian")}),
+		image("ami-5", "something-else", "2024-01-01T00:00:00.000Z"),
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		retained, err := retention{}.retainedAMIs(images)
+		require.NoError(t, err)
+		assert.Empty(t, retained)
+	})
+
+	t.Run("By Tag", func(t *testing.T) {
+		retained, err := retention{keepNewest: 2, familyTag: "Family"}.retainedAMIs(images)
+		require.NoError(t, err)
+		assert.Len(t, retained, 3)
+		assert.Contains(t, retained, "ami-2")
+		assert.Contains(t, retained, "ami-3")
+		assert.Contains(t, retained, "ami-4")
+		assert.Equal(t, "one of the 2 newest AMIs of family ubuntu", retained["ami-3"])
+	})
+
+	t.Run("By Name Pattern", func(t *testing.T) {
+		retained, err := retention{keepNewest: 1, familyPattern: `^golden-(\w+)-\d+$`}.retainedAMIs(images)
+		require.NoError(t, err)
+		assert.Len(t, retained, 2)
+		assert.Contains(t, retained, "ami-3")
+		assert.Contains(t, retained, "ami-4")
+	})
+
+	t.Run("Invalid Pattern", func(t *testing.T) {
+		_, err := retention{keepNewest: 1, familyPattern: `(`}.retainedAMIs(images)
+		require.Error(t, err)
+	})
+}
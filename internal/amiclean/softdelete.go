@@ -0,0 +1,110 @@
+package amiclean
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Strategy defines how unused AMIs are removed.
+type Strategy string
+
+const (
+	// STRATEGY_DEREGISTER deregisters unused AMIs immediately.
+	STRATEGY_DEREGISTER Strategy = "deregister"
+	// STRATEGY_DEPRECATE deprecates unused AMIs first and deregisters them after the grace period.
+	STRATEGY_DEPRECATE Strategy = "deprecate"
+	// STRATEGY_DISABLE disables unused AMIs first and deregisters them after the grace period.
+	STRATEGY_DISABLE Strategy = "disable"
+)
+
+var strategies = []Strategy{STRATEGY_DEREGISTER, STRATEGY_DEPRECATE, STRATEGY_DISABLE}
+
+// DISABLED_AT_TAG is set on AMIs disabled by awsclean as AWS doesn't record when an AMI was disabled.
+const DISABLED_AT_TAG = "awsclean:disabled-at"
+
+func ParseStrategy(strategy string) (Strategy, error) {
+	if !slices.Contains(strategies, Strategy(strategy)) {
+		return "", fmt.Errorf("unknown strategy %q, must be one of %v", strategy, strategies)
+	}
+	return Strategy(strategy), nil
+}
+
+// WithSoftDelete sets the strategy used to remove unused AMIs. For STRATEGY_DEPRECATE and STRATEGY_DISABLE
+// an AMI is only deregistered if it is deprecated or disabled for longer then gracePeriod.
+func WithSoftDelete(strategy Strategy, gracePeriod time.Duration) Option {
+	return func(a *AmiClean) {
+		a.strategy = strategy
+		a.gracePeriod = gracePeriod
+	}
+}
+
+// includeDisabled tells if disabled AMIs are selected. They are only of interest for STRATEGY_DISABLE which
+// deregisters them after the grace period.
+func (a AmiClean) includeDisabled() bool {
+	return a.strategy == STRATEGY_DISABLE
+}
+
+// softDelete returns the Decision for an AMI which should be removed according to the strategy.
+func (a AmiClean) softDelete(ami ec2Types.Image, reason string) Decision {
+	switch a.strategy {
+	case STRATEGY_DEPRECATE:
+		deprecatedAt, deprecated := deprecationTime(ami)
+		if !deprecated {
+			return Decision{Action: ACTION_DEPRECATE, Reason: reason}
+		}
+		return a.afterGracePeriod("deprecated", deprecatedAt)
+	case STRATEGY_DISABLE:
+		if ami.State != ec2Types.ImageStateDisabled {
+			return Decision{Action: ACTION_DISABLE, Reason: reason}
+		}
+		disabledAt, ok := disabledTime(ami)
+		if !ok {
+			return Decision{Action: ACTION_KEEP, Reason: fmt.Sprintf("disabled without %s tag", DISABLED_AT_TAG)}
+		}
+		return a.afterGracePeriod("disabled", disabledAt)
+	}
+	return Decision{Action: ACTION_DELETE, Reason: reason}
+}
+
+func (a AmiClean) afterGracePeriod(state string, since time.Time) Decision {
+	deregisterAt := since.Add(a.gracePeriod)
+	if time.Now().After(deregisterAt) {
+		return Decision{Action: ACTION_DELETE, Reason: fmt.Sprintf("%s since %s, grace period is over", state, since.Format(time.RFC3339))}
+	}
+	return Decision{Action: ACTION_KEEP, Reason: fmt.Sprintf("%s since %s, deregister after %s", state, since.Format(time.RFC3339), deregisterAt.Format(time.RFC3339))}
+}
+
+// deprecationTime returns when the AMI got deprecated. It returns false if the AMI isn't deprecated yet.
+func deprecationTime(ami ec2Types.Image) (time.Time, bool) {
+	if ami.DeprecationTime == nil {
+		return time.Time{}, false
+	}
+	deprecatedAt, err := time.Parse(time.RFC3339, *ami.DeprecationTime)
+	if err != nil || deprecatedAt.After(time.Now()) {
+		return time.Time{}, false
+	}
+	return deprecatedAt, true
+}
+
+func disabledTime(ami ec2Types.Image) (time.Time, bool) {
+	for _, tag := range ami.Tags {
+		if tag.Key != nil && *tag.Key == DISABLED_AT_TAG && tag.Value != nil {
+			disabledAt, err := time.Parse(time.RFC3339, *tag.Value)
+			return disabledAt, err == nil
+		}
+	}
+	return time.Time{}, false
+}
+
+// ImageState returns the state of the AMI. Other then AWS it reports deprecated AMIs as deprecated.
+func ImageState(ami ec2Types.Image) string {
+	if ami.State != ec2Types.ImageStateDisabled {
+		if _, deprecated := deprecationTime(ami); deprecated {
+			return "deprecated"
+		}
+	}
+	return string(ami.State)
+}
@@ -0,0 +1,149 @@
+package amiclean
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+func TestParseStrategy(t *testing.T) {
+	strategy, err := ParseStrategy("deprecate")
+	require.NoError(t, err)
+	assert.Equal(t, STRATEGY_DEPRECATE, strategy)
+
+	_, err = ParseStrategy("delete")
+	require.Error(t, err)
+}
+
+func TestSoftDelete(t *testing.T) {
+	const reason = "creationdate is older"
+	gracePeriod := 14 * 24 * time.Hour
+	longAgo := time.Now().Add(-30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	recently := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	inFuture := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+
+	t.Run("Deregister", func(t *testing.T) {
+		a := AmiClean{strategy: STRATEGY_DEREGISTER}
+		assert.Equal(t, Decision{Action: ACTION_DELETE, Reason: reason}, a.softDelete(types.Image{}, reason))
+	})
+
+	t.Run("Deprecate", func(t *testing.T) {
+		a := AmiClean{strategy: STRATEGY_DEPRECATE, gracePeriod: gracePeriod}
+
+		assert.Equal(t, Decision{Action: ACTION_DEPRECATE, Reason: reason}, a.softDelete(types.Image{}, reason))
+		assert.Equal(t, ACTION_DEPRECATE, a.softDelete(types.Image{DeprecationTime: aws.String(inFuture)}, reason).Action)
+
+		decision := a.softDelete(types.Image{DeprecationTime: aws.String(recently)}, reason)
+		assert.Equal(t, ACTION_KEEP, decision.Action)
+		assert.Contains(t, decision.Reason, "deprecated since "+recently)
+
+		assert.Equal(t, ACTION_DELETE, a.softDelete(types.Image{DeprecationTime: aws.String(longAgo)}, reason).Action)
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		a := AmiClean{strategy: STRATEGY_DISABLE, gracePeriod: gracePeriod}
+		disabledAt := func(value string) []types.Tag {
+			return []types.Tag{{Key: aws.String(DISABLED_AT_TAG), Value: aws.String(value)}}
+		}
+
+		assert.Equal(t, Decision{Action: ACTION_DISABLE, Reason: reason}, a.softDelete(types.Image{State: types.ImageStateAvailable}, reason))
+		assert.Equal(t, ACTION_KEEP, a.softDelete(types.Image{State: types.ImageStateDisabled}, reason).Action)
+		assert.Equal(t, ACTION_KEEP, a.softDelete(types.Image{State: types.ImageStateDisabled, Tags: disabledAt(recently)}, reason).Action)
+		assert.Equal(t, ACTION_DELETE, a.softDelete(types.Image{State: types.ImageStateDisabled, Tags: disabledAt(longAgo)}, reason).Action)
+	})
+}
+
+func TestIncludeDisabled(t *testing.T) {
+	defaultOlderthen, err := str2duration.ParseDuration("7d")
+	require.NoError(t, err)
+
+	for strategy, includeDisabled := range map[Strategy]*bool{
+		STRATEGY_DEREGISTER: nil,
+		STRATEGY_DEPRECATE:  nil,
+		STRATEGY_DISABLE:    aws.Bool(true),
+	} {
+		t.Run(string(strategy), func(t *testing.T) {
+			amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+			WithSoftDelete(strategy, 14*24*time.Hour)(amiclean)
+
+			mockDescribeInstances(1, ec2ClientMock)
+			ec2ClientMock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}, IncludeDisabled: includeDisabled}).Return(&ec2.DescribeImagesOutput{}, nil).Once()
+
+			err := amiclean.GetAMIs()
+			require.NoError(t, err)
+			ec2ClientMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImageState(t *testing.T) {
+	deprecated := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	assert.Equal(t, "available", ImageState(types.Image{State: types.ImageStateAvailable}))
+	assert.Equal(t, "deprecated", ImageState(types.Image{State: types.ImageStateAvailable, DeprecationTime: aws.String(deprecated)}))
+	assert.Equal(t, "disabled", ImageState(types.Image{State: types.ImageStateDisabled, DeprecationTime: aws.String(deprecated)}))
+}
+
+func TestDeleteWithSoftDelete(t *testing.T) {
+	defaultOlderthen, err := str2duration.ParseDuration("7d")
+	require.NoError(t, err)
+
+	images := []types.Image{
+		{ImageId: aws.String("old-id"), Name: aws.String("old"), CreationDate: aws.String("2006-01-02T15:04:05.000Z"), State: types.ImageStateAvailable},
+		{ImageId: aws.String("deprecated-id"), Name: aws.String("deprecated"), CreationDate: aws.String("2006-01-02T15:04:05.000Z"), State: types.ImageStateAvailable, DeprecationTime: aws.String("2007-01-02T15:04:05.000Z")},
+	}
+
+	t.Run("Deprecate", func(t *testing.T) {
+		amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+		WithSoftDelete(STRATEGY_DEPRECATE, 14*24*time.Hour)(amiclean)
+
+		mockDescribeInstances(1, ec2ClientMock)
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}}).Return(&ec2.DescribeImagesOutput{Images: images}, nil).Once()
+		ec2ClientMock.EXPECT().EnableImageDeprecation(context.TODO(), mock.MatchedBy(func(in *ec2.EnableImageDeprecationInput) bool {
+			return *in.ImageId == "old-id" && in.DeprecateAt.After(time.Now()) && !*in.DryRun
+		})).Return(&ec2.EnableImageDeprecationOutput{}, nil).Once()
+		ec2ClientMock.EXPECT().DeregisterImage(context.TODO(), &ec2.DeregisterImageInput{ImageId: aws.String("deprecated-id"), DryRun: aws.Bool(noDryrun)}).Return(&ec2.DeregisterImageOutput{}, nil).Once()
+
+		err := amiclean.DeleteOlderUnusedAMIs()
+		require.NoError(t, err)
+		ec2ClientMock.AssertExpectations(t)
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+		WithSoftDelete(STRATEGY_DISABLE, 14*24*time.Hour)(amiclean)
+
+		mockDescribeInstances(1, ec2ClientMock)
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}, IncludeDisabled: aws.Bool(true)}).Return(&ec2.DescribeImagesOutput{Images: images[:1]}, nil).Once()
+		tagged := ec2ClientMock.EXPECT().CreateTags(context.TODO(), mock.MatchedBy(func(in *ec2.CreateTagsInput) bool {
+			return in.Resources[0] == "old-id" && *in.Tags[0].Key == DISABLED_AT_TAG
+		})).Return(&ec2.CreateTagsOutput{}, nil).Once()
+		ec2ClientMock.EXPECT().DisableImage(context.TODO(), &ec2.DisableImageInput{ImageId: aws.String("old-id"), DryRun: aws.Bool(noDryrun)}).Return(&ec2.DisableImageOutput{}, nil).Once().NotBefore(tagged)
+
+		err := amiclean.DeleteOlderUnusedAMIs()
+		require.NoError(t, err)
+		ec2ClientMock.AssertExpectations(t)
+	})
+
+	t.Run("Disable CreateTag Failed", func(t *testing.T) {
+		amiclean, ec2ClientMock, _ := setupSUT(defaultOlderthen, noAWSAccount, noDryrun, notOnlyUnused, dontUseLaunchTpls, noFilterPatterns)
+		WithSoftDelete(STRATEGY_DISABLE, 14*24*time.Hour)(amiclean)
+
+		mockDescribeInstances(1, ec2ClientMock)
+		ec2ClientMock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}, IncludeDisabled: aws.Bool(true)}).Return(&ec2.DescribeImagesOutput{Images: images[:1]}, nil).Once()
+		ec2ClientMock.EXPECT().CreateTags(context.TODO(), mock.Anything).Return(nil, errors.New("some error")).Once()
+
+		err := amiclean.DeleteOlderUnusedAMIs()
+		require.NoError(t, err)
+		ec2ClientMock.AssertNotCalled(t, "DisableImage", mock.Anything, mock.Anything)
+	})
+}
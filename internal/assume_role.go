@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ROLE_SESSION_NAME = "awsclean"
+
+	// placeholder which is replaced with the account ID in AssumeRoleConfig.Role
+	ACCOUNT_PLACEHOLDER = "{account}"
+
+	// DEFAULT_PARTITION is used for plain role names if the partition of the caller can't be determined
+	DEFAULT_PARTITION = "aws"
+)
+
+// AssumeRoleConfig defines the accounts to cleanup and the role to assume in each of them.
+type AssumeRoleConfig struct {
+	Accounts []string
+	// Role can either be a plain role name or an ARN template like arn:aws:iam::{account}:role/cleanup
+	Role       string
+	ExternalID string
+}
+
+// IsARN returns true if Role is an ARN template instead of a plain role name.
+func (c AssumeRoleConfig) IsARN() bool {
+	return strings.HasPrefix(c.Role, "arn:")
+}
+
+// RoleARN returns the ARN of the role to assume in the given account. A plain role name is resolved in the
+// given partition, e.g. aws-cn or aws-us-gov.
+func (c AssumeRoleConfig) RoleARN(accountID, partition string) string {
+	if c.IsARN() {
+		return strings.ReplaceAll(c.Role, ACCOUNT_PLACEHOLDER, accountID)
+	}
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, accountID, c.Role)
+}
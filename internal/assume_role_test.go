@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleARN(t *testing.T) {
+
+	t.Run("Role Name", func(t *testing.T) {
+		cfg := AssumeRoleConfig{Role: "cleanup"}
+		assert.False(t, cfg.IsARN())
+		assert.Equal(t, "arn:aws:iam::123456789012:role/cleanup", cfg.RoleARN("123456789012", DEFAULT_PARTITION))
+		assert.Equal(t, "arn:aws-us-gov:iam::123456789012:role/cleanup", cfg.RoleARN("123456789012", "aws-us-gov"))
+	})
+
+	t.Run("ARN Template", func(t *testing.T) {
+		cfg := AssumeRoleConfig{Role: "arn:aws-cn:iam::{account}:role/tooling/cleanup"}
+		assert.Equal(t, "arn:aws-cn:iam::123456789012:role/tooling/cleanup", cfg.RoleARN("123456789012", "aws-us-gov"))
+	})
+
+	t.Run("ARN Without Placeholder", func(t *testing.T) {
+		cfg := AssumeRoleConfig{Role: "arn:aws:iam::210987654321:role/cleanup"}
+		assert.Equal(t, "arn:aws:iam::210987654321:role/cleanup", cfg.RoleARN("123456789012", "aws-us-gov"))
+	})
+}
+
+func TestPartition(t *testing.T) {
+	t.Run("GovCloud", func(t *testing.T) {
+		stsMock := mocks.NewMockSTS(t)
+		SUT := NewFromInterface(mocks.NewMockEc2client(t), mocks.NewMockCloudTrail(t), WithSTS(stsMock))
+		stsMock.EXPECT().GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{}).Return(&sts.GetCallerIdentityOutput{
+			Arn: aws.String("arn:aws-us-gov:sts::123456789012:assumed-role/admin/session"),
+		}, nil).Once()
+
+		assert.Equal(t, "aws-us-gov", SUT.Partition())
+	})
+
+	t.Run("Unknown Caller", func(t *testing.T) {
+		stsMock := mocks.NewMockSTS(t)
+		SUT := NewFromInterface(mocks.NewMockEc2client(t), mocks.NewMockCloudTrail(t), WithSTS(stsMock))
+		stsMock.EXPECT().GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{}).Return(nil, errors.New("expired token")).Once()
+
+		assert.Equal(t, DEFAULT_PARTITION, SUT.Partition())
+	})
+}
@@ -5,65 +5,408 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	autoscalingTypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
 	cloudtrailTypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
 	"github.com/steffakasid/eslog"
 )
 
 const CLOUDTRAIL_RESOURCE_TYPE = "AWS::EC2::SecurityGroup"
 
+// ALL_REGIONS can be used instead of a region name to select all regions enabled for the account.
+const ALL_REGIONS = "all"
+
 type Ec2client interface {
 	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
 	DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)
 	DeregisterImage(ctx context.Context, params *ec2.DeregisterImageInput, optFns ...func(*ec2.Options)) (*ec2.DeregisterImageOutput, error)
 	DescribeLaunchTemplateVersions(ctx context.Context, params *ec2.DescribeLaunchTemplateVersionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplateVersionsOutput, error)
+	DescribeLaunchTemplates(ctx context.Context, params *ec2.DescribeLaunchTemplatesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplatesOutput, error)
 	DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, opftFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error)
 	DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
 	DeleteSecurityGroup(ctx context.Context, params *ec2.DeleteSecurityGroupInput, optFns ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error)
 	DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
 	DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error)
+	DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error)
+	DeleteSnapshot(ctx context.Context, params *ec2.DeleteSnapshotInput, optFns ...func(*ec2.Options)) (*ec2.DeleteSnapshotOutput, error)
+	DescribeSnapshots(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error)
+	EnableImageDeprecation(ctx context.Context, params *ec2.EnableImageDeprecationInput, optFns ...func(*ec2.Options)) (*ec2.EnableImageDeprecationOutput, error)
+	DisableImage(ctx context.Context, params *ec2.DisableImageInput, optFns ...func(*ec2.Options)) (*ec2.DisableImageOutput, error)
+	CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)
+	DescribeManagedPrefixLists(ctx context.Context, params *ec2.DescribeManagedPrefixListsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeManagedPrefixListsOutput, error)
+	DescribeStaleSecurityGroups(ctx context.Context, params *ec2.DescribeStaleSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeStaleSecurityGroupsOutput, error)
+	RevokeSecurityGroupIngress(ctx context.Context, params *ec2.RevokeSecurityGroupIngressInput, optFns ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error)
+	RevokeSecurityGroupEgress(ctx context.Context, params *ec2.RevokeSecurityGroupEgressInput, optFns ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupEgressOutput, error)
+	DeleteNetworkInterface(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error)
+	DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)
+	ReleaseAddress(ctx context.Context, params *ec2.ReleaseAddressInput, optFns ...func(*ec2.Options)) (*ec2.ReleaseAddressOutput, error)
+	DeleteLaunchTemplateVersions(ctx context.Context, params *ec2.DeleteLaunchTemplateVersionsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteLaunchTemplateVersionsOutput, error)
+	DeleteLaunchTemplate(ctx context.Context, params *ec2.DeleteLaunchTemplateInput, optFns ...func(*ec2.Options)) (*ec2.DeleteLaunchTemplateOutput, error)
 }
 
 type CloudTrail interface {
 	LookupEvents(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error)
 }
 
+type AutoScaling interface {
+	DescribeAutoScalingGroups(ctx context.Context, params *autoscaling.DescribeAutoScalingGroupsInput, optFns ...func(*autoscaling.Options)) (*autoscaling.DescribeAutoScalingGroupsOutput, error)
+	DescribeLaunchConfigurations(ctx context.Context, params *autoscaling.DescribeLaunchConfigurationsInput, optFns ...func(*autoscaling.Options)) (*autoscaling.DescribeLaunchConfigurationsOutput, error)
+}
+
+type ELBv2 interface {
+	DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
+	DescribeTargetGroups(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetGroupsInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error)
+	DescribeTargetHealth(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetHealthInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error)
+	DescribeListeners(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error)
+	DeleteLoadBalancer(ctx context.Context, params *elasticloadbalancingv2.DeleteLoadBalancerInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DeleteLoadBalancerOutput, error)
+	DeleteTargetGroup(ctx context.Context, params *elasticloadbalancingv2.DeleteTargetGroupInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DeleteTargetGroupOutput, error)
+}
+
+type ECR interface {
+	DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error)
+	DescribeImages(ctx context.Context, params *ecr.DescribeImagesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error)
+	BatchGetImage(ctx context.Context, params *ecr.BatchGetImageInput, optFns ...func(*ecr.Options)) (*ecr.BatchGetImageOutput, error)
+	BatchDeleteImage(ctx context.Context, params *ecr.BatchDeleteImageInput, optFns ...func(*ecr.Options)) (*ecr.BatchDeleteImageOutput, error)
+}
+
+type ECS interface {
+	ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error)
+	ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error)
+	DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+	ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error)
+	DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
+	DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error)
+}
+
+type CloudWatchLogs interface {
+	DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+	DescribeLogStreams(ctx context.Context, params *cloudwatchlogs.DescribeLogStreamsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error)
+	DeleteLogGroup(ctx context.Context, params *cloudwatchlogs.DeleteLogGroupInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DeleteLogGroupOutput, error)
+	PutRetentionPolicy(ctx context.Context, params *cloudwatchlogs.PutRetentionPolicyInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutRetentionPolicyOutput, error)
+}
+
+type S3 interface {
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+// SecGrpEventSource provides CreateSecurityGroup and DeleteSecurityGroup events as alternative to LookupEvents.
+// String must identify the source, it's used as part of the key in the CloudTrail cache.
+type SecGrpEventSource interface {
+	fmt.Stringer
+	// SecGrpEvents returns the lifecycle events between startTime and endTime by security group ID.
+	SecGrpEvents(startTime, endTime time.Time) (map[string][]LifecycleEvent, error)
+}
+
+type STS interface {
+	AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
 type AWS struct {
-	ec2        Ec2client
-	cloudtrail CloudTrail
+	ec2         Ec2client
+	cloudtrail  CloudTrail
+	sts         STS
+	autoscaling AutoScaling
+	s3          S3
+	elbv2       ELBv2
+	ecr         ECR
+	ecs         ECS
+	logs        CloudWatchLogs
+	cfg         aws.Config
+	account     string
+	// optional, if set CloudTrail events are cached beyond the 90 days CloudTrail keeps them
+	cloudTrailCache *CloudTrailCache
+	// optional, if set security group events are read from it instead of LookupEvents
+	secGrpEventSource SecGrpEventSource
+	cloudTrailLimiter *rateLimiter
+	cloudTrailBackoff backoff
+	logStreamsLimiter *rateLimiter
+	logStreamsBackoff backoff
+}
+
+// Option is used to set additional service clients in NewFromInterface.
+type Option func(*AWS)
+
+func WithSTS(sts STS) Option {
+	return func(a *AWS) {
+		a.sts = sts
+	}
+}
+
+func WithAutoScaling(autoscaling AutoScaling) Option {
+	return func(a *AWS) {
+		a.autoscaling = autoscaling
+	}
 }
 
 type cloudTrailEventType string
 
 const (
-	SECURITYGROUP_CREATED cloudTrailEventType = "CreateSecurityGroup"
+	SECURITYGROUP_CREATED    cloudTrailEventType = "CreateSecurityGroup"
+	SECURITYGROUP_DELETED    cloudTrailEventType = "DeleteSecurityGroup"
+	NETWORKINTERFACE_CREATED cloudTrailEventType = "CreateNetworkInterface"
+	ADDRESS_ALLOCATED        cloudTrailEventType = "AllocateAddress"
+	TARGETGROUP_CREATED      cloudTrailEventType = "CreateTargetGroup"
+	TARGETS_DEREGISTERED     cloudTrailEventType = "DeregisterTargets"
 )
 
-func NewFromInterface(ec2 Ec2client, cloudtrail CloudTrail) *AWS {
-	return &AWS{
-		ec2:        ec2,
-		cloudtrail: cloudtrail,
+func WithS3(s3 S3) Option {
+	return func(a *AWS) {
+		a.s3 = s3
 	}
 }
 
-func NewAWSClient() *AWS {
-	aws := &AWS{}
+func WithELBv2(elbv2 ELBv2) Option {
+	return func(a *AWS) {
+		a.elbv2 = elbv2
+	}
+}
 
-	cfg, err := config.LoadDefaultConfig(context.TODO())
-	eslog.LogIfErrorf(err, eslog.Fatalf, "aws.LoadDefaultConfig() failed: %d")
+func WithECR(ecr ECR) Option {
+	return func(a *AWS) {
+		a.ecr = ecr
+	}
+}
+
+func WithECS(ecs ECS) Option {
+	return func(a *AWS) {
+		a.ecs = ecs
+	}
+}
 
-	aws.ec2 = ec2.NewFromConfig(cfg)
-	aws.cloudtrail = cloudtrail.NewFromConfig(cfg)
+func WithCloudWatchLogs(logs CloudWatchLogs) Option {
+	return func(a *AWS) {
+		a.logs = logs
+	}
+}
+
+// WithRateLimits limits the LookupEvents and DescribeLogStreams calls to the rates AWS allows per account
+// and region.
+func WithRateLimits() Option {
+	return func(a *AWS) {
+		a.cloudTrailLimiter = newRateLimiter(CLOUDTRAIL_TPS)
+		a.logStreamsLimiter = newRateLimiter(DESCRIBE_LOG_STREAMS_TPS)
+	}
+}
+
+// NewFromInterface returns a client using the given service clients. Calls are only rate limited if
+// WithRateLimits is given, throttled calls are always retried.
+func NewFromInterface(ec2 Ec2client, cloudtrail CloudTrail, opts ...Option) *AWS {
+	aws := &AWS{
+		ec2:               ec2,
+		cloudtrail:        cloudtrail,
+		cloudTrailBackoff: defaultBackoff,
+		logStreamsBackoff: defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(aws)
+	}
 	return aws
 }
 
+func NewAWSClient(optFns ...func(*config.LoadOptions) error) *AWS {
+	cfg, err := config.LoadDefaultConfig(context.TODO(), optFns...)
+	eslog.LogIfErrorf(err, eslog.Fatalf, "aws.LoadDefaultConfig() failed: %d")
+
+	return newFromConfig(cfg)
+}
+
+func newFromConfig(cfg aws.Config) *AWS {
+	return &AWS{
+		ec2:         ec2.NewFromConfig(cfg),
+		cloudtrail:  cloudtrail.NewFromConfig(cfg),
+		sts:         sts.NewFromConfig(cfg),
+		autoscaling: autoscaling.NewFromConfig(cfg),
+		s3:          s3.NewFromConfig(cfg),
+		elbv2:       elasticloadbalancingv2.NewFromConfig(cfg),
+		ecr:         ecr.NewFromConfig(cfg),
+		ecs:         ecs.NewFromConfig(cfg),
+		logs:        cloudwatchlogs.NewFromConfig(cfg),
+		cfg:         cfg,
+		// the limits apply per account and region, so each client has its own limiters
+		cloudTrailLimiter: newRateLimiter(CLOUDTRAIL_TPS),
+		cloudTrailBackoff: defaultBackoff,
+		logStreamsLimiter: newRateLimiter(DESCRIBE_LOG_STREAMS_TPS),
+		logStreamsBackoff: defaultBackoff,
+	}
+}
+
+// NewAWSClients returns one client per given account and region. If no account is configured in
+// assumeRole the default credentials are used. If no region is given the default region from
+// ~/.aws/config is used. ALL_REGIONS is resolved to all enabled regions of each account.
+func NewAWSClients(regions []string, assumeRole AssumeRoleConfig) []*AWS {
+	accountClients := []*AWS{NewAWSClient()}
+	if len(assumeRole.Accounts) > 0 {
+		defaultClient := accountClients[0]
+		partition := DEFAULT_PARTITION
+		if !assumeRole.IsARN() {
+			partition = defaultClient.Partition()
+		}
+		accountClients = []*AWS{}
+		for _, accountID := range assumeRole.Accounts {
+			accountClients = append(accountClients, defaultClient.AssumeRole(accountID, assumeRole.RoleARN(accountID, partition), assumeRole.ExternalID))
+		}
+	}
+
+	if len(regions) == 0 {
+		return accountClients
+	}
+
+	clients := []*AWS{}
+	for _, accountClient := range accountClients {
+		accountRegions := regions
+		if slices.Contains(regions, ALL_REGIONS) {
+			var err error
+			accountRegions, err = accountClient.GetEnabledRegions()
+			eslog.LogIfErrorf(err, eslog.Fatalf, "GetEnabledRegions() for account %s failed: %s", accountClient.AccountID(), err)
+		}
+
+		for _, region := range accountRegions {
+			clients = append(clients, accountClient.ForRegion(region))
+		}
+	}
+	return clients
+}
+
+// ForRegion returns a copy of the client which is connected to the given region.
+func (a AWS) ForRegion(region string) *AWS {
+	cfg := a.cfg.Copy()
+	cfg.Region = region
+
+	client := newFromConfig(cfg)
+	client.account = a.account
+	return client
+}
+
+// AssumeRole returns a copy of the client which uses the credentials of roleARN to access the given account.
+// The role is assumed lazily on the first request.
+func (a AWS) AssumeRole(accountID, roleARN, externalID string) *AWS {
+	provider := stscreds.NewAssumeRoleProvider(a.sts, roleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = ROLE_SESSION_NAME
+		if externalID != "" {
+			o.ExternalID = aws.String(externalID)
+		}
+	})
+
+	cfg := a.cfg.Copy()
+	cfg.Credentials = aws.NewCredentialsCache(provider)
+
+	client := newFromConfig(cfg)
+	client.account = accountID
+	return client
+}
+
+// Region returns the AWS region the client is connected to.
+func (a AWS) Region() string {
+	return a.cfg.Region
+}
+
+// AccountID returns the ID of the assumed account. It's empty if the default credentials are used.
+func (a AWS) AccountID() string {
+	return a.account
+}
+
+// SetCloudTrailCache sets the cache used by GetCloudTrailForSecGroups.
+func (a *AWS) SetCloudTrailCache(cache *CloudTrailCache) {
+	a.cloudTrailCache = cache
+}
+
+// SetSecGrpEventSource sets the source used by GetCloudTrailForSecGroups instead of LookupEvents.
+func (a *AWS) SetSecGrpEventSource(source SecGrpEventSource) {
+	a.secGrpEventSource = source
+}
+
+// callerAccountID returns the ID of the account the client is connected to. If the default credentials are
+// used the account is looked up. It's empty if the account can't be determined.
+func (a AWS) callerAccountID() string {
+	if a.account != "" {
+		return a.account
+	}
+	out := a.callerIdentity()
+	if out == nil {
+		return ""
+	}
+	return aws.ToString(out.Account)
+}
+
+// Partition returns the partition of the caller, e.g. aws-cn or aws-us-gov. It's DEFAULT_PARTITION if the
+// caller can't be determined.
+func (a AWS) Partition() string {
+	out := a.callerIdentity()
+	if out == nil {
+		return DEFAULT_PARTITION
+	}
+	// arn:<partition>:sts::123456789012:assumed-role/cleanup/session
+	parts := strings.SplitN(aws.ToString(out.Arn), ":", 3)
+	if len(parts) < 3 || parts[1] == "" {
+		return DEFAULT_PARTITION
+	}
+	return parts[1]
+}
+
+// callerIdentity looks up the identity of the credentials. It's nil if it can't be determined.
+func (a AWS) callerIdentity() *sts.GetCallerIdentityOutput {
+	if a.sts == nil {
+		return nil
+	}
+	out, err := a.sts.GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
+	eslog.LogIfErrorf(err, eslog.Warnf, "Could not get caller identity: %s")
+	return out
+}
+
+// cacheScope returns the key of the account, region and event source in the CloudTrail cache. An error is returned
+// if the account can't be determined, as the events of different accounts must not be mixed up.
+func (a AWS) cacheScope() (string, error) {
+	account := a.callerAccountID()
+	if account == "" {
+		return "", errors.New("could not determine the account")
+	}
+	if a.secGrpEventSource != nil {
+		return fmt.Sprintf("%s/%s/%s", account, a.Region(), a.secGrpEventSource), nil
+	}
+	return fmt.Sprintf("%s/%s", account, a.Region()), nil
+}
+
+// GetEnabledRegions returns the names of all regions which are enabled for the account.
+func (a AWS) GetEnabledRegions() ([]string, error) {
+	out, err := a.ec2.DescribeRegions(context.TODO(), &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	regions := []string{}
+	for _, region := range out.Regions {
+		if region.RegionName != nil {
+			regions = append(regions, *region.RegionName)
+		}
+	}
+	return regions, nil
+}
+
 func (a *AWS) GetSecurityGroups() (SecurityGroups, error) {
 	secGrpsRet := SecurityGroups{}
 
@@ -98,42 +441,119 @@ func (a *AWS) GetSecurityGroups() (SecurityGroups, error) {
 	return secGrpsRet, nil
 }
 
+// GetManagedPrefixListIds returns the IDs of all prefix lists, including the ones managed by AWS.
+func (a *AWS) GetManagedPrefixListIds() ([]string, error) {
+	prefixListIds := []string{}
+	in := &ec2.DescribeManagedPrefixListsInput{}
+	for {
+		out, err := a.ec2.DescribeManagedPrefixLists(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		for _, prefixList := range out.PrefixLists {
+			prefixListIds = append(prefixListIds, aws.ToString(prefixList.PrefixListId))
+		}
+
+		if out.NextToken == nil {
+			return prefixListIds, nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// GetStaleSecurityGroups returns the groups of the VPC with rules which reference deleted security groups,
+// including groups in peered VPCs.
+func (a *AWS) GetStaleSecurityGroups(vpcId string) ([]ec2Types.StaleSecurityGroup, error) {
+	staleGroups := []ec2Types.StaleSecurityGroup{}
+	in := &ec2.DescribeStaleSecurityGroupsInput{VpcId: &vpcId}
+	for {
+		out, err := a.ec2.DescribeStaleSecurityGroups(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		staleGroups = append(staleGroups, out.StaleSecurityGroupSet...)
+
+		if out.NextToken == nil {
+			return staleGroups, nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// RevokeSecurityGroupRule removes perm from the ingress or egress rules of the group.
+func (a *AWS) RevokeSecurityGroupRule(groupID string, egress bool, perm ec2Types.IpPermission, dryrun bool) error {
+	eslog.Logger.Debugf("RevokeSecurityGroupRule(%s, egress: %t), dryrun: %t", groupID, egress, dryrun)
+
+	var err error
+	if egress {
+		_, err = a.ec2.RevokeSecurityGroupEgress(context.TODO(), &ec2.RevokeSecurityGroupEgressInput{
+			DryRun:        aws.Bool(dryrun),
+			GroupId:       aws.String(groupID),
+			IpPermissions: []ec2Types.IpPermission{perm},
+		})
+	} else {
+		_, err = a.ec2.RevokeSecurityGroupIngress(context.TODO(), &ec2.RevokeSecurityGroupIngressInput{
+			DryRun:        aws.Bool(dryrun),
+			GroupId:       aws.String(groupID),
+			IpPermissions: []ec2Types.IpPermission{perm},
+		})
+	}
+	return err
+}
+
 // TODO: move to secgrp.go or security_group.go
-func (a *AWS) GetNotUsedSecGrpsFromENI(secGrps SecurityGroups) (used *SecurityGroups, unused *SecurityGroups, err error) {
+// GetNotUsedSecGrpsFromENI splits secGrps into groups which are attached to network interfaces and groups
+// which are not. Up to concurrency groups are looked up at the same time. The result doesn't depend on the
+// concurrency: on an error the groups before the failed one (ordered by ID) are returned.
+func (a *AWS) GetNotUsedSecGrpsFromENI(secGrps SecurityGroups, concurrency int) (used *SecurityGroups, unused *SecurityGroups, err error) {
 	used = &SecurityGroups{}
 	unused = &SecurityGroups{}
 
-	// TODO: must use go routines
-	for _, secGrp := range secGrps {
+	groupIDs := slices.Sorted(maps.Keys(secGrps))
+	attachedIfaces := make([][]ec2Types.NetworkInterface, len(groupIDs))
+	errs := make([]error, len(groupIDs))
 
-		filter := *secGrp.GroupName
-		eslog.Logger.Debugf("GetNotUsedSecGrpsFromENI(): filter %s", filter)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, max(concurrency, 1))
+	for i, groupID := range groupIDs {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			// later groups are not needed anymore
+			if failed.Load() {
+				return
+			}
+			attachedIfaces[i], errs[i] = a.getNetIfacesOfSecGrp(*secGrps[groupID].GroupId)
+			if errs[i] != nil {
+				failed.Store(true)
+			}
+		}()
+	}
+	wg.Wait()
 
-		in := &ec2.DescribeNetworkInterfacesInput{
-			Filters: []ec2Types.Filter{
-				{
-					Name:   aws.String("group-name"),
-					Values: []string{filter},
-				},
-			},
-		}
+	for i, groupID := range groupIDs {
+		secGrp := secGrps[groupID]
 
-		out, err := a.ec2.DescribeNetworkInterfaces(context.TODO(), in)
-		if nil != err {
-			return used, unused, fmt.Errorf("error describing network interfaces: %w", err)
+		if errs[i] != nil {
+			return used, unused, fmt.Errorf("error describing network interfaces: %w", errs[i])
 		}
 
-		if len(out.NetworkInterfaces) > 0 {
-			attachedIfaces := []string{}
-			for _, iface := range out.NetworkInterfaces {
-				attachedIfaces = append(attachedIfaces, *iface.NetworkInterfaceId)
-			}
+		if len(attachedIfaces[i]) > 0 {
 			secGrp.IsUsed = true
-			secGrp.AttachedToNetIfaces = attachedIfaces
+			secGrp.AttachedToNetIfaces = []string{}
+			for _, iface := range attachedIfaces[i] {
+				secGrp.AttachedToNetIfaces = append(secGrp.AttachedToNetIfaces, aws.ToString(iface.NetworkInterfaceId))
+				if aws.ToBool(iface.RequesterManaged) {
+					secGrp.ManagedBy = UniqueAppend(secGrp.ManagedBy, aws.ToString(iface.RequesterId))
+				}
+			}
 			err := used.AddOrUpdate(*secGrp)
 			eslog.LogIfErrorf(err, eslog.Errorf, "GetNotUsedSecGrpFromENI() AddOrUpdate() of used SecGrp failed: %s")
 		} else {
-			eslog.Logger.Debugf("No ENI attached to group with Name: %s", *secGrp.GroupName)
+			eslog.Logger.Debugf("No ENI attached to group %s (%s) in VPC %s", *secGrp.GroupId, aws.ToString(secGrp.GroupName), aws.ToString(secGrp.VpcId))
 			err := unused.AddOrUpdate(*secGrp)
 			eslog.LogIfErrorf(err, eslog.Errorf, "GetNotUsedSecGrpFromENI() AddOrUpdate() of unused SecGrp failed: %s")
 		}
@@ -141,66 +561,256 @@ func (a *AWS) GetNotUsedSecGrpsFromENI(secGrps SecurityGroups) (used *SecurityGr
 	return used, unused, nil
 }
 
-// TODO: move to secgrp.go
-func (a AWS) GetCloudTrailForSecGroups(startTime, endTime time.Time) SecurityGroups {
-	nextToken := "empty"
-
-	secGrps := SecurityGroups{}
-
-	// TODO: must use go routines
-	for nextToken != "" {
-		time.Sleep(5 * time.Second)
-		lookup := &cloudtrail.LookupEventsInput{
-			StartTime: aws.Time(startTime),
-			EndTime:   aws.Time(endTime),
-			LookupAttributes: []cloudtrailTypes.LookupAttribute{
-				{
-					AttributeKey:   cloudtrailTypes.LookupAttributeKeyEventName, // Todo: Need to get DeleteSecurityGroup as well and delete them from the list...
-					AttributeValue: aws.String(string(SECURITYGROUP_CREATED)),
-				},
+// getNetIfacesOfSecGrp returns the network interfaces the group is attached to.
+func (a *AWS) getNetIfacesOfSecGrp(groupID string) ([]ec2Types.NetworkInterface, error) {
+	eslog.Logger.Debugf("GetNotUsedSecGrpsFromENI(): filter %s", groupID)
+
+	in := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []ec2Types.Filter{
+			{
+				Name:   aws.String("group-id"),
+				Values: []string{groupID},
 			},
+		},
+	}
+
+	out, err := a.ec2.DescribeNetworkInterfaces(context.TODO(), in)
+	if nil != err {
+		return nil, err
+	}
+
+	return out.NetworkInterfaces, nil
+}
+
+// TODO: move to secgrp.go
+// GetCloudTrailForSecGroups returns the groups created or deleted between startTime and endTime with their
+// lifecycle history. Failed calls, e.g. dry runs, are ignored. If a cache is set, only events after the
+// last run are looked up and all cached events until endTime are returned, even if CloudTrail dropped them.
+// The cache isn't used if the account can't be determined. An error is returned if not all events could be read.
+func (a AWS) GetCloudTrailForSecGroups(startTime, endTime time.Time) (SecurityGroups, error) {
+	scopeKey := ""
+	if a.cloudTrailCache != nil {
+		var err error
+		scopeKey, err = a.cacheScope()
+		eslog.LogIfErrorf(err, eslog.Warnf, "Not using the CloudTrail cache: %s")
+	}
+	if scopeKey == "" {
+		events, err := a.secGrpEvents(startTime, endTime)
+		if err != nil {
+			return nil, err
 		}
-		if nextToken != "empty" {
-			lookup.NextToken = aws.String(nextToken)
+		return a.getDetailsForSecGrpsFromCloudTrail(events), nil
+	}
+
+	// log files are kept as long as configured, so all events are available
+	oldestAvailable := time.Time{}
+	if a.secGrpEventSource == nil {
+		oldestAvailable = time.Now().Add(CLOUDTRAIL_RETENTION * -1)
+	}
+
+	scope := a.cloudTrailCache.Scope(scopeKey)
+	lookupStart := scope.LookupStart(startTime, oldestAvailable)
+	if lookupStart.Before(endTime) {
+		eslog.Logger.Debugf("Looking up CloudTrail events from %s", lookupStart.Format(time.RFC3339))
+		newEvents, err := a.secGrpEvents(lookupStart, endTime)
+		if err != nil {
+			// the cache must not claim to cover the time range
+			return nil, err
 		}
+		scope.Add(lookupStart, endTime, newEvents)
+		err = a.cloudTrailCache.Save()
+		eslog.LogIfErrorf(err, eslog.Errorf, "Could not save CloudTrail cache: %s")
+	}
 
-		// We only get CloudTrailEvents of the last 90d: https://docs.aws.amazon.com/sdk-for-go/api/service/cloudtrail/#CloudTrail.LookupEvents
-		out, err := a.cloudtrail.LookupEvents(context.TODO(), lookup)
-		eslog.LogIfErrorf(err, eslog.Errorf, "Error on LookupEvents(): %s")
-		// out could be nil if rate is exceeded
-		// TODO: needs unit test
-		if out != nil && out.NextToken != nil {
-			nextToken = *out.NextToken
-		} else {
-			nextToken = ""
+	events := map[string][]LifecycleEvent{}
+	for resourceName, resourceEvents := range scope.Events {
+		for _, ev := range resourceEvents {
+			if !aws.ToTime(ev.EventTime).After(endTime) {
+				events[resourceName] = append(events[resourceName], ev)
+			}
 		}
+	}
+	return a.getDetailsForSecGrpsFromCloudTrail(events), nil
+}
 
-		if out != nil {
-			secGrps.AppendAll(a.getDetailsForSecGrpsFromCloudTrail(out))
+// secGrpEvents returns the lifecycle events of security groups from the configured event source.
+func (a AWS) secGrpEvents(startTime, endTime time.Time) (map[string][]LifecycleEvent, error) {
+	if a.secGrpEventSource == nil {
+		return a.lookupSecGrpEvents(startTime, endTime)
+	}
+	events, err := a.secGrpEventSource.SecGrpEvents(startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("error reading events from %s: %w", a.secGrpEventSource, err)
+	}
+	return events, nil
+}
+
+// lookupSecGrpEvents returns the lifecycle events of security groups by CloudTrail resource name.
+func (a AWS) lookupSecGrpEvents(startTime, endTime time.Time) (map[string][]LifecycleEvent, error) {
+	events := []cloudtrailTypes.Event{}
+	for _, eventName := range []cloudTrailEventType{SECURITYGROUP_CREATED, SECURITYGROUP_DELETED} {
+		nameEvents, err := a.lookupEvents(eventName, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, nameEvents...)
+	}
+
+	lifecycleEvents := map[string][]LifecycleEvent{}
+	for _, ev := range events {
+		if failedCloudTrailEvent(ev) {
+			eslog.Logger.Debugf("Ignoring failed %s event of %s", aws.ToString(ev.EventName), aws.ToTime(ev.EventTime).Format(time.RFC3339))
+			continue
+		}
+		for _, res := range ev.Resources {
+			if aws.ToString(res.ResourceType) == CLOUDTRAIL_RESOURCE_TYPE && res.ResourceName != nil {
+				lifecycleEvents[*res.ResourceName] = append(lifecycleEvents[*res.ResourceName], LifecycleEvent{
+					EventName: aws.ToString(ev.EventName),
+					Username:  aws.ToString(ev.Username),
+					EventTime: ev.EventTime,
+				})
+			}
 		}
 	}
-	return secGrps
+	return lifecycleEvents, nil
 }
 
-func (a AWS) getDetailsForSecGrpsFromCloudTrail(out *cloudtrail.LookupEventsOutput) SecurityGroups {
+// lookupLatestEvents returns the latest successful eventName event of each resource by the resource name
+// returned by resourceNames.
+func (a AWS) lookupLatestEvents(eventName cloudTrailEventType, resourceNames func(cloudtrailTypes.Event) []string, startTime, endTime time.Time) (map[string]LifecycleEvent, error) {
+	events, err := a.lookupEvents(eventName, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
 
-	additionalDetails := SecurityGroups{}
+	creationEvents := map[string]LifecycleEvent{}
+	for _, ev := range events {
+		if failedCloudTrailEvent(ev) {
+			eslog.Logger.Debugf("Ignoring failed %s event of %s", aws.ToString(ev.EventName), aws.ToTime(ev.EventTime).Format(time.RFC3339))
+			continue
+		}
+		for _, resourceName := range resourceNames(ev) {
+			if existing, exists := creationEvents[resourceName]; exists && aws.ToTime(existing.EventTime).After(aws.ToTime(ev.EventTime)) {
+				continue
+			}
+			creationEvents[resourceName] = LifecycleEvent{
+				EventName: aws.ToString(ev.EventName),
+				Username:  aws.ToString(ev.Username),
+				EventTime: ev.EventTime,
+			}
+		}
+	}
+	return creationEvents, nil
+}
 
-	for _, ev := range out.Events {
+// resourcesOfType returns a function which returns the names of the resources of resourceType of an event.
+func resourcesOfType(resourceType string) func(cloudtrailTypes.Event) []string {
+	return func(ev cloudtrailTypes.Event) []string {
+		names := []string{}
 		for _, res := range ev.Resources {
-			// TODO: needs unit testing
-			if *res.ResourceType == CLOUDTRAIL_RESOURCE_TYPE {
-				additionalDetails[*res.ResourceName] = &SecurityGroup{
-					Creator:      *ev.Username,
-					CreationTime: ev.EventTime,
-				}
+			if aws.ToString(res.ResourceType) == resourceType && res.ResourceName != nil {
+				names = append(names, *res.ResourceName)
 			}
 		}
+		return names
+	}
+}
+
+// EventBefore returns true if the CloudTrail event at eventTime happened before olderThenDate. Without an event
+// it happened before lookupStart, which is only sufficient if the lookup covered the whole time since
+// olderThenDate.
+func EventBefore(eventTime *time.Time, lookupStart, olderThenDate time.Time) bool {
+	if eventTime != nil {
+		return eventTime.Before(olderThenDate)
+	}
+	return !lookupStart.After(olderThenDate)
+}
+
+func (a AWS) lookupEvents(eventName cloudTrailEventType, startTime, endTime time.Time) ([]cloudtrailTypes.Event, error) {
+	events := []cloudtrailTypes.Event{}
+
+	lookup := &cloudtrail.LookupEventsInput{
+		StartTime: aws.Time(startTime),
+		EndTime:   aws.Time(endTime),
+		LookupAttributes: []cloudtrailTypes.LookupAttribute{
+			{
+				// LookupEvents only supports one attribute, so each event name needs an own lookup
+				AttributeKey:   cloudtrailTypes.LookupAttributeKeyEventName,
+				AttributeValue: aws.String(string(eventName)),
+			},
+		},
+	}
+	for page := 1; ; page++ {
+		// We only get CloudTrailEvents of the last 90d: https://docs.aws.amazon.com/sdk-for-go/api/service/cloudtrail/#CloudTrail.LookupEvents
+		out, err := a.lookupEventsPage(lookup)
+		if err != nil {
+			return events, fmt.Errorf("could not get page %d of %s events from CloudTrail: %w", page, eventName, err)
+		}
+		events = append(events, out.Events...)
+
+		if out.NextToken == nil {
+			return events, nil
+		}
+		lookup.NextToken = out.NextToken
+	}
+}
+
+// lookupEventsPage calls LookupEvents within the rate limit of CloudTrail. Throttled calls are retried with
+// exponential backoff.
+func (a AWS) lookupEventsPage(lookup *cloudtrail.LookupEventsInput) (*cloudtrail.LookupEventsOutput, error) {
+	out, err := callThrottled("LookupEvents", a.cloudTrailLimiter, a.cloudTrailBackoff, func() (*cloudtrail.LookupEventsOutput, error) {
+		return a.cloudtrail.LookupEvents(context.TODO(), lookup)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, errors.New("LookupEvents() returned no result")
+	}
+	return out, nil
+}
+
+func (a AWS) getDetailsForSecGrpsFromCloudTrail(events map[string][]LifecycleEvent) SecurityGroups {
+
+	additionalDetails := SecurityGroups{}
+
+	for resourceName, resourceEvents := range events {
+		grp := &SecurityGroup{}
+		// addLifecycleEvent keeps the history in chronological order
+		for _, ev := range resourceEvents {
+			grp.addLifecycleEvent(ev)
+		}
+		additionalDetails[resourceName] = grp
 	}
 
 	return additionalDetails
 }
 
+// failedCloudTrailEvent returns true if the API call of the event failed. E.g. a DeleteSecurityGroup with
+// DryRun or of a group which is still in use.
+func failedCloudTrailEvent(ev cloudtrailTypes.Event) bool {
+	if ev.CloudTrailEvent == nil {
+		return false
+	}
+	details := struct {
+		ErrorCode string `json:"errorCode"`
+	}{}
+	if err := json.Unmarshal([]byte(*ev.CloudTrailEvent), &details); err != nil {
+		eslog.Logger.Debugf("Could not parse CloudTrailEvent: %s", err)
+		return false
+	}
+	return details.ErrorCode != ""
+}
+
+// parseCloudTrailEvent unmarshals the JSON details of the event into v. It returns false if they can't be parsed.
+func parseCloudTrailEvent(ev cloudtrailTypes.Event, v any) bool {
+	if err := json.Unmarshal([]byte(aws.ToString(ev.CloudTrailEvent)), v); err != nil {
+		eslog.Logger.Debugf("Could not parse CloudTrailEvent: %s", err)
+		return false
+	}
+	return true
+}
+
 func (a *AWS) DeleteSecurityGroup(secGrp SecurityGroup, dryrun bool) error {
 	if secGrp.SecurityGroup == nil || secGrp.GroupId == nil {
 		return fmt.Errorf("can not delete SecurityGroup without GroupId") // this should usually never happen
@@ -248,44 +858,278 @@ func (a *AWS) GetUsedAMIsFromEC2() []string {
 	return usedImages
 }
 
-func (a *AWS) GetUsedAMIsFromLaunchTpls() []string {
-	usedImages := []string{}
-	nextToken := ""
+// GetUsedAMIsFromLaunchTpls returns the AMIs of the given launch template versions. The versions pinned
+// by Auto Scaling groups are returned by GetUsedAMIsFromAutoScalingGroups.
+func (a *AWS) GetUsedAMIsFromLaunchTpls(versions LaunchTplVersions) (AMIUsage, error) {
+	usedImages := AMIUsage{}
+	switch versions {
+	case LAUNCH_TPL_ALL:
+		launchTplIds, err := a.getLaunchTplIds()
+		if err != nil {
+			return nil, err
+		}
+		for _, launchTplId := range launchTplIds {
+			err := a.addUsedAMIsFromLaunchTplVersions(usedImages, &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String(launchTplId)})
+			if err != nil {
+				return nil, err
+			}
+		}
+	case LAUNCH_TPL_DEFAULT:
+		err := a.addUsedAMIsFromLaunchTplVersions(usedImages, &ec2.DescribeLaunchTemplateVersionsInput{Versions: []string{"$Default"}})
+		if err != nil {
+			return nil, err
+		}
+	case LAUNCH_TPL_REFERENCED:
+		err := a.addUsedAMIsFromLaunchTplVersions(usedImages, &ec2.DescribeLaunchTemplateVersionsInput{Versions: []string{"$Default"}})
+		if err != nil {
+			return nil, err
+		}
+		err = a.addUsedAMIsFromInstanceLaunchTplVersions(usedImages)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		err := a.addUsedAMIsFromLaunchTplVersions(usedImages, &ec2.DescribeLaunchTemplateVersionsInput{Versions: []string{"$Latest"}})
+		if err != nil {
+			return nil, err
+		}
+	}
+	eslog.Logger.Debugf("UsedImages[] from Launch Templates %v", usedImages)
+	return usedImages, nil
+}
+
+// addUsedAMIsFromInstanceLaunchTplVersions adds the AMIs of the launch template versions instances were
+// launched from. Deleted launch templates and versions are skipped.
+func (a *AWS) addUsedAMIsFromInstanceLaunchTplVersions(usedImages AMIUsage) error {
+	versions, err := a.GetLaunchTplVersionsOfInstances()
+	if err != nil {
+		return err
+	}
+
+	for _, launchTplId := range slices.Sorted(maps.Keys(versions)) {
+		err := a.addUsedAMIsFromLaunchTplVersions(usedImages, &ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String(launchTplId),
+			Versions:         versions[launchTplId],
+		})
+		if isNotFound(err) {
+			eslog.Logger.Warnf("Skipping launch template %s versions %v of instances: %s", launchTplId, versions[launchTplId], err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AWS) getLaunchTplIds() ([]string, error) {
+	launchTpls, err := a.GetLaunchTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	launchTplIds := []string{}
+	for _, launchTpl := range launchTpls {
+		launchTplIds = append(launchTplIds, *launchTpl.LaunchTemplateId)
+	}
+	return launchTplIds, nil
+}
+
+func (a *AWS) addUsedAMIsFromLaunchTplVersions(usedImages AMIUsage, input *ec2.DescribeLaunchTemplateVersionsInput) error {
 	for {
-		opts := &ec2.DescribeLaunchTemplateVersionsInput{
-			Versions: []string{"$Latest"},
+		launchTpls, err := a.ec2.DescribeLaunchTemplateVersions(context.TODO(), input)
+		if err != nil {
+			return err
 		}
-		if nextToken != "" {
-			opts.NextToken = &nextToken
+
+		for _, launchTplVersion := range launchTpls.LaunchTemplateVersions {
+			if launchTplVersion.LaunchTemplateData != nil && launchTplVersion.LaunchTemplateData.ImageId != nil {
+				usedImages.Add(*launchTplVersion.LaunchTemplateData.ImageId, launchTplVersionName(launchTplVersion))
+			}
 		}
-		launchTpls, err := a.ec2.DescribeLaunchTemplateVersions(context.TODO(), opts)
-		eslog.LogIfError(err, eslog.Error, err)
-		if launchTpls != nil {
-			for _, launchTplVersion := range launchTpls.LaunchTemplateVersions {
-				if launchTplVersion.LaunchTemplateData.ImageId != nil {
-					usedImages = append(usedImages, *launchTplVersion.LaunchTemplateData.ImageId)
+
+		if launchTpls.NextToken == nil {
+			return nil
+		}
+		input.NextToken = launchTpls.NextToken
+	}
+}
+
+func launchTplVersionName(launchTplVersion ec2Types.LaunchTemplateVersion) string {
+	return fmt.Sprintf("launch template %s version %d", aws.ToString(launchTplVersion.LaunchTemplateName), aws.ToInt64(launchTplVersion.VersionNumber))
+}
+
+// GetUsedAMIsFromLaunchConfigs returns the AMIs referenced by launch configurations. It's empty if no
+// Auto Scaling client is set.
+func (a AWS) GetUsedAMIsFromLaunchConfigs() (AMIUsage, error) {
+	usedImages := AMIUsage{}
+	if a.autoscaling == nil {
+		return usedImages, nil
+	}
+	var nextToken *string
+	for {
+		out, err := a.autoscaling.DescribeLaunchConfigurations(context.TODO(), &autoscaling.DescribeLaunchConfigurationsInput{NextToken: nextToken})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, launchConfig := range out.LaunchConfigurations {
+			if launchConfig.ImageId != nil {
+				usedImages.Add(*launchConfig.ImageId, fmt.Sprintf("launch configuration %s", aws.ToString(launchConfig.LaunchConfigurationName)))
+			}
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		nextToken = out.NextToken
+	}
+	eslog.Logger.Debugf("UsedImages[] from Launch Configurations %v", usedImages)
+	return usedImages, nil
+}
+
+// GetUsedAMIsFromAutoScalingGroups returns the AMIs of the launch template versions which are referenced
+// by Auto Scaling groups, either directly or by a mixed instances policy. Other then
+// GetUsedAMIsFromLaunchTpls the exact version pinned by the group is resolved. It's empty if no Auto Scaling
+// client is set.
+func (a AWS) GetUsedAMIsFromAutoScalingGroups() (AMIUsage, error) {
+	usedImages := AMIUsage{}
+	if a.autoscaling == nil {
+		return usedImages, nil
+	}
+	resolved := map[string]ec2Types.LaunchTemplateVersion{}
+
+	addLaunchTpl := func(group string, launchTpl autoscalingTypes.LaunchTemplateSpecification) error {
+		key := fmt.Sprintf("%s/%s/%s", aws.ToString(launchTpl.LaunchTemplateId), aws.ToString(launchTpl.LaunchTemplateName), aws.ToString(launchTpl.Version))
+		launchTplVersion, exists := resolved[key]
+		if !exists {
+			var err error
+			launchTplVersion, err = a.getLaunchTplVersion(launchTpl)
+			if err != nil {
+				return err
+			}
+			resolved[key] = launchTplVersion
+		}
+
+		if launchTplVersion.LaunchTemplateData != nil && launchTplVersion.LaunchTemplateData.ImageId != nil {
+			usedImages.Add(*launchTplVersion.LaunchTemplateData.ImageId, fmt.Sprintf("%s of Auto Scaling group %s", launchTplVersionName(launchTplVersion), group))
+		}
+		return nil
+	}
+
+	var nextToken *string
+	for {
+		out, err := a.autoscaling.DescribeAutoScalingGroups(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{NextToken: nextToken})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range out.AutoScalingGroups {
+			groupName := aws.ToString(group.AutoScalingGroupName)
+			for _, launchTpl := range launchTplsOfAutoScalingGroup(group) {
+				if err := addLaunchTpl(groupName, launchTpl); err != nil {
+					return nil, err
+				}
+			}
+			if group.MixedInstancesPolicy != nil && group.MixedInstancesPolicy.LaunchTemplate != nil {
+				for _, override := range group.MixedInstancesPolicy.LaunchTemplate.Overrides {
+					if override.ImageId != nil {
+						usedImages.Add(*override.ImageId, fmt.Sprintf("mixed instances policy of Auto Scaling group %s", groupName))
+					}
 				}
 			}
 		}
-		if launchTpls == nil || launchTpls.NextToken == nil {
+
+		if out.NextToken == nil {
 			break
 		}
-		nextToken = *launchTpls.NextToken
+		nextToken = out.NextToken
 	}
-	eslog.Logger.Debugf("UsedImages[] from Launch Templates %v", usedImages)
-	return usedImages
+	eslog.Logger.Debugf("UsedImages[] from Auto Scaling Groups %v", usedImages)
+	return usedImages, nil
 }
 
-func (a AWS) DescribeImages(accountId string) ([]ec2Types.Image, error) {
-	describeImageInput := &ec2.DescribeImagesInput{Owners: []string{"self"}}
-	if accountId != "" {
-		describeImageInput.Owners = append(describeImageInput.Owners, accountId)
+// launchTplsOfAutoScalingGroup returns the launch templates referenced by the group, either directly or by
+// a mixed instances policy.
+func launchTplsOfAutoScalingGroup(group autoscalingTypes.AutoScalingGroup) []autoscalingTypes.LaunchTemplateSpecification {
+	launchTpls := []autoscalingTypes.LaunchTemplateSpecification{}
+	if group.LaunchTemplate != nil {
+		launchTpls = append(launchTpls, *group.LaunchTemplate)
 	}
-	imagesOutput, err := a.ec2.DescribeImages(context.TODO(), describeImageInput)
+	if group.MixedInstancesPolicy != nil && group.MixedInstancesPolicy.LaunchTemplate != nil {
+		policyTpl := group.MixedInstancesPolicy.LaunchTemplate
+		if policyTpl.LaunchTemplateSpecification != nil {
+			launchTpls = append(launchTpls, *policyTpl.LaunchTemplateSpecification)
+		}
+		for _, override := range policyTpl.Overrides {
+			if override.LaunchTemplateSpecification != nil {
+				launchTpls = append(launchTpls, *override.LaunchTemplateSpecification)
+			}
+		}
+	}
+	return launchTpls
+}
+
+// getLaunchTplVersion returns the given launch template version. If no version is set AWS uses the
+// default version of the launch template.
+func (a AWS) getLaunchTplVersion(launchTpl autoscalingTypes.LaunchTemplateSpecification) (ec2Types.LaunchTemplateVersion, error) {
+	version := aws.ToString(launchTpl.Version)
+	if version == "" {
+		version = "$Default"
+	}
+
+	input := &ec2.DescribeLaunchTemplateVersionsInput{
+		Versions: []string{version},
+	}
+	if launchTpl.LaunchTemplateId != nil {
+		input.LaunchTemplateId = launchTpl.LaunchTemplateId
+	} else {
+		input.LaunchTemplateName = launchTpl.LaunchTemplateName
+	}
+
+	out, err := a.ec2.DescribeLaunchTemplateVersions(context.TODO(), input)
 	if err != nil {
-		return nil, err
+		return ec2Types.LaunchTemplateVersion{}, fmt.Errorf("could not get version %s of launch template %s%s: %w", version, aws.ToString(launchTpl.LaunchTemplateId), aws.ToString(launchTpl.LaunchTemplateName), err)
+	}
+	if len(out.LaunchTemplateVersions) == 0 {
+		return ec2Types.LaunchTemplateVersion{}, nil
+	}
+	return out.LaunchTemplateVersions[0], nil
+}
+
+// DescribeImages returns all images owned by self or the given account. The filters are applied server side.
+// Disabled images are only returned if includeDisabled is set.
+func (a AWS) DescribeImages(accountId string, includeDisabled bool, filters ...ec2Types.Filter) ([]ec2Types.Image, error) {
+	images := []ec2Types.Image{}
+	nextToken := ""
+
+	for {
+		describeImageInput := &ec2.DescribeImagesInput{Owners: []string{"self"}}
+		if includeDisabled {
+			describeImageInput.IncludeDisabled = aws.Bool(true)
+		}
+		if accountId != "" {
+			describeImageInput.Owners = append(describeImageInput.Owners, accountId)
+		}
+		if len(filters) > 0 {
+			describeImageInput.Filters = filters
+		}
+		if nextToken != "" {
+			describeImageInput.NextToken = &nextToken
+		}
+
+		imagesOutput, err := a.ec2.DescribeImages(context.TODO(), describeImageInput)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, imagesOutput.Images...)
+
+		if imagesOutput.NextToken == nil {
+			break
+		}
+		nextToken = *imagesOutput.NextToken
 	}
-	return imagesOutput.Images, nil
+	return images, nil
 }
 
 func (a AWS) DeregisterImage(imageId string, dryRun bool) error {
@@ -297,7 +1141,64 @@ func (a AWS) DeregisterImage(imageId string, dryRun bool) error {
 	return err
 }
 
+// EnableImageDeprecation marks the AMI as deprecated at the given time.
+func (a AWS) EnableImageDeprecation(imageId string, deprecateAt time.Time, dryRun bool) error {
+	_, err := a.ec2.EnableImageDeprecation(context.TODO(), &ec2.EnableImageDeprecationInput{
+		ImageId:     &imageId,
+		DeprecateAt: &deprecateAt,
+		DryRun:      &dryRun,
+	})
+	return err
+}
+
+// DisableImage disables the AMI so that it can't be used to launch new instances anymore.
+func (a AWS) DisableImage(imageId string, dryRun bool) error {
+	_, err := a.ec2.DisableImage(context.TODO(), &ec2.DisableImageInput{
+		ImageId: &imageId,
+		DryRun:  &dryRun,
+	})
+	return err
+}
+
+// CreateTag adds or overwrites the tag of the given resource.
+func (a AWS) CreateTag(resourceId, key, value string, dryRun bool) error {
+	_, err := a.ec2.CreateTags(context.TODO(), &ec2.CreateTagsInput{
+		Resources: []string{resourceId},
+		Tags:      []ec2Types.Tag{{Key: &key, Value: &value}},
+		DryRun:    &dryRun,
+	})
+	return err
+}
+
+func (a AWS) DeleteSnapshot(snapshotId string, dryRun bool) error {
+	opts := &ec2.DeleteSnapshotInput{
+		SnapshotId: &snapshotId,
+		DryRun:     &dryRun,
+	}
+	_, err := a.ec2.DeleteSnapshot(context.TODO(), opts)
+	return err
+}
+
+// IsDryRunOperation returns true if err just tells that the request would have succeeded without the DryRun flag.
+func IsDryRunOperation(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "DryRunOperation"
+}
+
+// isNotFound returns true if err is caused by a resource which doesn't exist, e.g. InvalidLaunchTemplateId.NotFound.
+func isNotFound(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && strings.Contains(apiErr.ErrorCode(), "NotFound")
+}
+
 func (a AWS) GetAvailableEBSVolumes() []ec2Types.Volume {
+	volumes, err := a.GetEBSVolumes()
+	eslog.LogIfError(err, eslog.Error, err)
+	return volumes
+}
+
+// GetEBSVolumes returns all EBS volumes. On error the volumes fetched so far are returned together with the error.
+func (a AWS) GetEBSVolumes() ([]ec2Types.Volume, error) {
 	volumes := []ec2Types.Volume{}
 	nextToken := ""
 
@@ -307,18 +1208,43 @@ func (a AWS) GetAvailableEBSVolumes() []ec2Types.Volume {
 			opts.NextToken = &nextToken
 		}
 		volumeOutput, err := a.ec2.DescribeVolumes(context.TODO(), opts)
-		eslog.LogIfError(err, eslog.Error, err)
-
-		if volumeOutput != nil {
-			volumes = append(volumes, volumeOutput.Volumes...)
+		if err != nil {
+			return volumes, err
 		}
 
-		if volumeOutput == nil || volumeOutput.NextToken == nil {
+		volumes = append(volumes, volumeOutput.Volumes...)
+
+		if volumeOutput.NextToken == nil {
 			break
 		}
 		nextToken = *volumeOutput.NextToken
 	}
-	return volumes
+	return volumes, nil
+}
+
+// GetOwnSnapshots returns all EBS snapshots owned by the account.
+func (a AWS) GetOwnSnapshots() ([]ec2Types.Snapshot, error) {
+	snapshots := []ec2Types.Snapshot{}
+	nextToken := ""
+
+	for {
+		opts := &ec2.DescribeSnapshotsInput{OwnerIds: []string{"self"}}
+		if nextToken != "" {
+			opts.NextToken = &nextToken
+		}
+		snapshotOutput, err := a.ec2.DescribeSnapshots(context.TODO(), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, snapshotOutput.Snapshots...)
+
+		if snapshotOutput.NextToken == nil {
+			break
+		}
+		nextToken = *snapshotOutput.NextToken
+	}
+	return snapshots, nil
 }
 
 func (a AWS) DeleteVolume(volumeId string, dryrun bool) error {
@@ -2,17 +2,27 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	autoscalingTypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
 	cloudtrailTypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	stsTypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/aws/smithy-go"
 	"github.com/steffakasid/awsclean/internal/mocks"
 	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -110,8 +120,8 @@ func TestGetNotUsedSecGrpsFromENI(t *testing.T) {
 		expectedOpts1 := &ec2.DescribeNetworkInterfacesInput{
 			Filters: []types.Filter{
 				{
-					Name:   aws.String("group-name"),
-					Values: []string{expectedGrpName1}},
+					Name:   aws.String("group-id"),
+					Values: []string{expectedGrpID1}},
 			},
 		}
 		expectedOut1 := ec2.DescribeNetworkInterfacesOutput{
@@ -126,8 +136,8 @@ func TestGetNotUsedSecGrpsFromENI(t *testing.T) {
 		expectedOpts2 := &ec2.DescribeNetworkInterfacesInput{
 			Filters: []types.Filter{
 				{
-					Name:   aws.String("group-name"),
-					Values: []string{expectedGrpName2}},
+					Name:   aws.String("group-id"),
+					Values: []string{expectedGrpID2}},
 			},
 		}
 		expectedOut2 := ec2.DescribeNetworkInterfacesOutput{
@@ -136,28 +146,51 @@ func TestGetNotUsedSecGrpsFromENI(t *testing.T) {
 		mock.EXPECT().DescribeNetworkInterfaces(context.TODO(), expectedOpts2).Return(&expectedOut2, nil).Once()
 
 		secGrps := SecurityGroups{
-			"Group1": &SecurityGroup{
+			expectedGrpID1: &SecurityGroup{
 				SecurityGroup: &types.SecurityGroup{
 					GroupName: &expectedGrpName1,
 					GroupId:   &expectedGrpID1,
 				},
 			},
-			"Group2": &SecurityGroup{
+			expectedGrpID2: &SecurityGroup{
 				SecurityGroup: &types.SecurityGroup{
 					GroupName: &expectedGrpName2,
 					GroupId:   &expectedGrpID2,
 				},
 			},
 		}
-		usedSecGrps, notUsedSecGrps, err := SUT.GetNotUsedSecGrpsFromENI(secGrps)
+		usedSecGrps, notUsedSecGrps, err := SUT.GetNotUsedSecGrpsFromENI(secGrps, 1)
 		require.NoError(t, err)
 		assert.Len(t, *notUsedSecGrps, 1)
 		assert.Len(t, *usedSecGrps, 1)
 		mock.AssertExpectations(t)
 	})
 
+	t.Run("Requester Managed", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+
+		mock.EXPECT().DescribeNetworkInterfaces(context.TODO(), &ec2.DescribeNetworkInterfacesInput{
+			Filters: []types.Filter{{Name: aws.String("group-id"), Values: []string{"sg-lb"}}},
+		}).Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []types.NetworkInterface{
+				{NetworkInterfaceId: aws.String("eni-1"), RequesterManaged: aws.Bool(true), RequesterId: aws.String("amazon-elb")},
+				{NetworkInterfaceId: aws.String("eni-2"), RequesterManaged: aws.Bool(true), RequesterId: aws.String("amazon-elb")},
+				{NetworkInterfaceId: aws.String("eni-3"), RequesterManaged: aws.Bool(false)},
+			},
+		}, nil).Once()
+
+		secGrps := SecurityGroups{"sg-lb": &SecurityGroup{SecurityGroup: &types.SecurityGroup{GroupId: aws.String("sg-lb")}}}
+		usedSecGrps, _, err := SUT.GetNotUsedSecGrpsFromENI(secGrps, 1)
+		require.NoError(t, err)
+		require.Contains(t, *usedSecGrps, "sg-lb")
+		assert.Equal(t, []string{"eni-1", "eni-2", "eni-3"}, (*usedSecGrps)["sg-lb"].AttachedToNetIfaces)
+		assert.Equal(t, []string{"amazon-elb"}, (*usedSecGrps)["sg-lb"].ManagedBy)
+	})
+
 	t.Run("Error from AWS", func(t *testing.T) {
+		expectedGrpID1 := "1234"
 		expectedGrpName1 := "groupname1"
+		expectedGrpID2 := "5678"
 		expectedGrpName2 := "groupname2"
 
 		SUT, mock, _ := setupSUT(t)
@@ -165,8 +198,8 @@ func TestGetNotUsedSecGrpsFromENI(t *testing.T) {
 		expectedOpts1 := &ec2.DescribeNetworkInterfacesInput{
 			Filters: []types.Filter{
 				{
-					Name:   aws.String("group-name"),
-					Values: []string{expectedGrpName1}},
+					Name:   aws.String("group-id"),
+					Values: []string{expectedGrpID1}},
 			},
 		}
 		expectedOut1 := ec2.DescribeNetworkInterfacesOutput{
@@ -181,25 +214,27 @@ func TestGetNotUsedSecGrpsFromENI(t *testing.T) {
 		expectedOpts2 := &ec2.DescribeNetworkInterfacesInput{
 			Filters: []types.Filter{
 				{
-					Name:   aws.String("group-name"),
-					Values: []string{expectedGrpName2}},
+					Name:   aws.String("group-id"),
+					Values: []string{expectedGrpID2}},
 			},
 		}
 		mock.EXPECT().DescribeNetworkInterfaces(context.TODO(), expectedOpts2).Return(nil, fmt.Errorf("Something went wrong")).Once()
 
 		secGrps := SecurityGroups{
-			"Group1": &SecurityGroup{
+			expectedGrpID1: &SecurityGroup{
 				SecurityGroup: &types.SecurityGroup{
 					GroupName: &expectedGrpName1,
+					GroupId:   &expectedGrpID1,
 				},
 			},
-			"Group2": &SecurityGroup{
+			expectedGrpID2: &SecurityGroup{
 				SecurityGroup: &types.SecurityGroup{
 					GroupName: &expectedGrpName2,
+					GroupId:   &expectedGrpID2,
 				},
 			},
 		}
-		usedSecGrps, notUsedSecGrps, err := SUT.GetNotUsedSecGrpsFromENI(secGrps)
+		usedSecGrps, notUsedSecGrps, err := SUT.GetNotUsedSecGrpsFromENI(secGrps, 1)
 		require.Error(t, err)
 		require.EqualError(t, err, "error describing network interfaces: Something went wrong")
 		assert.Len(t, *notUsedSecGrps, 0)
@@ -207,6 +242,63 @@ func TestGetNotUsedSecGrpsFromENI(t *testing.T) {
 
 		mock.AssertExpectations(t)
 	})
+
+	t.Run("Same Name In Different VPCs", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+
+		mockENIs := func(groupID string, ifaces ...types.NetworkInterface) {
+			in := &ec2.DescribeNetworkInterfacesInput{
+				Filters: []types.Filter{{Name: aws.String("group-id"), Values: []string{groupID}}},
+			}
+			mock.EXPECT().DescribeNetworkInterfaces(context.TODO(), in).Return(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: ifaces}, nil).Once()
+		}
+		mockENIs("sg-1", types.NetworkInterface{NetworkInterfaceId: aws.String("eni-1")})
+		mockENIs("sg-2")
+
+		secGrps := SecurityGroups{}
+		require.NoError(t, secGrps.AddOrUpdate(SecurityGroup{SecurityGroup: &types.SecurityGroup{GroupId: aws.String("sg-1"), GroupName: aws.String("default"), VpcId: aws.String("vpc-1")}}))
+		require.NoError(t, secGrps.AddOrUpdate(SecurityGroup{SecurityGroup: &types.SecurityGroup{GroupId: aws.String("sg-2"), GroupName: aws.String("default"), VpcId: aws.String("vpc-2")}}))
+		require.Len(t, secGrps, 2)
+
+		usedSecGrps, notUsedSecGrps, err := SUT.GetNotUsedSecGrpsFromENI(secGrps, 1)
+		require.NoError(t, err)
+		assert.Contains(t, *usedSecGrps, "sg-1")
+		assert.Equal(t, "vpc-2", *(*notUsedSecGrps)["sg-2"].VpcId)
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		getNotUsed := func(concurrency int) (*SecurityGroups, *SecurityGroups) {
+			SUT, mock, _ := setupSUT(t)
+			mock.EXPECT().DescribeNetworkInterfaces(context.TODO(), testifyMock.Anything).RunAndReturn(
+				func(ctx context.Context, in *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+					groupID := in.Filters[0].Values[0]
+					out := &ec2.DescribeNetworkInterfacesOutput{}
+					// every third group is attached to an interface
+					if id, _ := strconv.Atoi(strings.TrimPrefix(groupID, "sg-")); id%3 == 0 {
+						out.NetworkInterfaces = []types.NetworkInterface{{NetworkInterfaceId: aws.String("eni-" + groupID)}}
+					}
+					return out, nil
+				}).Times(30)
+
+			secGrps := SecurityGroups{}
+			for i := range 30 {
+				groupID := fmt.Sprintf("sg-%d", i)
+				require.NoError(t, secGrps.AddOrUpdate(SecurityGroup{SecurityGroup: &types.SecurityGroup{GroupId: aws.String(groupID)}}))
+			}
+
+			usedSecGrps, notUsedSecGrps, err := SUT.GetNotUsedSecGrpsFromENI(secGrps, concurrency)
+			require.NoError(t, err)
+			return usedSecGrps, notUsedSecGrps
+		}
+
+		expectedUsed, expectedNotUsed := getNotUsed(1)
+		assert.Len(t, *expectedUsed, 10)
+		assert.Len(t, *expectedNotUsed, 20)
+		assert.Equal(t, []string{"eni-sg-3"}, (*expectedUsed)["sg-3"].AttachedToNetIfaces)
+
+		usedSecGrps, notUsedSecGrps := getNotUsed(8)
+		assert.Equal(t, expectedUsed, usedSecGrps)
+		assert.Equal(t, expectedNotUsed, notUsedSecGrps)
+	})
 }
 
 func TestGetCloudTrailForSecGroups(t *testing.T) {
@@ -218,9 +310,176 @@ func TestGetCloudTrailForSecGroups(t *testing.T) {
 		endtime, err := time.Parse(time.DateTime, "2006-01-30 15:04:05")
 		require.NoError(t, err)
 
+		createdAt := starttime.Add(time.Hour)
+		recreatedAt := starttime.Add(3 * time.Hour)
+		deletedAt := starttime.Add(2 * time.Hour)
+
 		SUT, ec2Mock, cloudtrailMOck := setupSUT(t)
 
-		lookupEventsIn := &cloudtrail.LookupEventsInput{
+		lookupEventsIn := func(eventName string) *cloudtrail.LookupEventsInput {
+			return &cloudtrail.LookupEventsInput{
+				StartTime: &starttime,
+				EndTime:   &endtime,
+				LookupAttributes: []cloudtrailTypes.LookupAttribute{
+					{
+						AttributeKey:   cloudtrailTypes.LookupAttributeKeyEventName,
+						AttributeValue: aws.String(eventName),
+					},
+				},
+			}
+		}
+		event := func(eventName, user, resourceName string, eventTime time.Time) cloudtrailTypes.Event {
+			return cloudtrailTypes.Event{
+				EventName: aws.String(eventName),
+				EventTime: aws.Time(eventTime),
+				Username:  aws.String(user),
+				Resources: []cloudtrailTypes.Resource{
+					{
+						ResourceName: aws.String(resourceName),
+						ResourceType: aws.String(CLOUDTRAIL_RESOURCE_TYPE),
+					},
+				},
+			}
+		}
+		failedDelete := event("DeleteSecurityGroup", "cleanup", "sg-2", endtime.Add(-time.Hour))
+		failedDelete.CloudTrailEvent = aws.String(`{"errorCode": "Client.DryRunOperation"}`)
+
+		cloudtrailMOck.EXPECT().LookupEvents(context.TODO(), lookupEventsIn("CreateSecurityGroup")).Return(&cloudtrail.LookupEventsOutput{
+			Events: []cloudtrailTypes.Event{
+				event("CreateSecurityGroup", "seconduser", "somename", recreatedAt),
+				event("CreateSecurityGroup", "someuser", "somename", createdAt),
+				event("CreateSecurityGroup", "someuser", "sg-2", createdAt),
+			},
+		}, nil)
+		cloudtrailMOck.EXPECT().LookupEvents(context.TODO(), lookupEventsIn("DeleteSecurityGroup")).Return(&cloudtrail.LookupEventsOutput{
+			Events: []cloudtrailTypes.Event{
+				event("DeleteSecurityGroup", "deleter", "somename", deletedAt),
+				failedDelete,
+			},
+		}, nil)
+
+		secGrps, err := SUT.GetCloudTrailForSecGroups(starttime, endtime)
+		require.NoError(t, err)
+
+		require.Contains(t, secGrps, "somename")
+		recreated := secGrps["somename"]
+		assert.Equal(t, "seconduser", recreated.Creator)
+		assert.Equal(t, recreatedAt, *recreated.CreationTime)
+		assert.Equal(t, "deleter", recreated.Deleter)
+		assert.Equal(t, deletedAt, *recreated.DeletionTime)
+		assert.Equal(t, []LifecycleEvent{
+			{EventName: "CreateSecurityGroup", Username: "someuser", EventTime: aws.Time(createdAt)},
+			{EventName: "DeleteSecurityGroup", Username: "deleter", EventTime: aws.Time(deletedAt)},
+			{EventName: "CreateSecurityGroup", Username: "seconduser", EventTime: aws.Time(recreatedAt)},
+		}, recreated.History)
+		assert.False(t, recreated.IsDeleted())
+
+		require.Contains(t, secGrps, "sg-2")
+		assert.Len(t, secGrps["sg-2"].History, 1)
+		assert.Nil(t, secGrps["sg-2"].DeletionTime)
+
+		ec2Mock.AssertExpectations(t)
+		cloudtrailMOck.AssertExpectations(t)
+	})
+
+	t.Run("With Cache", func(t *testing.T) {
+		starttime, err := time.Parse(time.DateTime, "2006-01-02 15:04:05")
+		require.NoError(t, err)
+		endtime, err := time.Parse(time.DateTime, "2006-01-30 15:04:05")
+		require.NoError(t, err)
+		lastRun := endtime.Add(-24 * time.Hour)
+		expectedLookupStart := lastRun.Add(CLOUDTRAIL_DELIVERY_DELAY * -1)
+		cachedCreation := starttime.Add(time.Hour)
+
+		cloudtrailMock := mocks.NewMockCloudTrail(t)
+		stsMock := mocks.NewMockSTS(t)
+		SUT := NewFromInterface(mocks.NewMockEc2client(t), cloudtrailMock, WithSTS(stsMock))
+
+		cachePath := filepath.Join(t.TempDir(), CLOUDTRAIL_CACHE_FILE)
+		cache, err := LoadCloudTrailCache(cachePath)
+		require.NoError(t, err)
+		cache.Scope("123456789012/").Add(starttime, lastRun, map[string][]LifecycleEvent{
+			"sg-cached": {{EventName: string(SECURITYGROUP_CREATED), Username: "olduser", EventTime: aws.Time(cachedCreation)}},
+		})
+		SUT.SetCloudTrailCache(cache)
+
+		stsMock.EXPECT().GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{}).Return(&sts.GetCallerIdentityOutput{Account: aws.String("123456789012")}, nil).Once()
+		for _, eventName := range []string{"CreateSecurityGroup", "DeleteSecurityGroup"} {
+			out := &cloudtrail.LookupEventsOutput{}
+			if eventName == "CreateSecurityGroup" {
+				out.Events = []cloudtrailTypes.Event{
+					{
+						EventName: aws.String(eventName),
+						EventTime: aws.Time(endtime.Add(-time.Hour)),
+						Username:  aws.String("newuser"),
+						Resources: []cloudtrailTypes.Resource{
+							{ResourceName: aws.String("sg-new"), ResourceType: aws.String(CLOUDTRAIL_RESOURCE_TYPE)},
+						},
+					},
+				}
+			}
+			cloudtrailMock.EXPECT().LookupEvents(context.TODO(), &cloudtrail.LookupEventsInput{
+				StartTime: &expectedLookupStart,
+				EndTime:   &endtime,
+				LookupAttributes: []cloudtrailTypes.LookupAttribute{
+					{
+						AttributeKey:   cloudtrailTypes.LookupAttributeKeyEventName,
+						AttributeValue: aws.String(eventName),
+					},
+				},
+			}).Return(out, nil).Once()
+		}
+
+		secGrps, err := SUT.GetCloudTrailForSecGroups(starttime, endtime)
+		require.NoError(t, err)
+
+		require.Contains(t, secGrps, "sg-cached")
+		assert.Equal(t, "olduser", secGrps["sg-cached"].Creator)
+		require.Contains(t, secGrps, "sg-new")
+		assert.Equal(t, "newuser", secGrps["sg-new"].Creator)
+
+		saved, err := LoadCloudTrailCache(cachePath)
+		require.NoError(t, err)
+		assert.Contains(t, saved.Scopes["123456789012/"].Events, "sg-new")
+		assert.Equal(t, endtime, saved.Scopes["123456789012/"].To)
+	})
+
+	t.Run("With Cache Unknown Account", func(t *testing.T) {
+		starttime, err := time.Parse(time.DateTime, "2006-01-02 15:04:05")
+		require.NoError(t, err)
+		endtime, err := time.Parse(time.DateTime, "2006-01-30 15:04:05")
+		require.NoError(t, err)
+
+		cloudtrailMock := mocks.NewMockCloudTrail(t)
+		stsMock := mocks.NewMockSTS(t)
+		SUT := NewFromInterface(mocks.NewMockEc2client(t), cloudtrailMock, WithSTS(stsMock))
+
+		cachePath := filepath.Join(t.TempDir(), CLOUDTRAIL_CACHE_FILE)
+		cache, err := LoadCloudTrailCache(cachePath)
+		require.NoError(t, err)
+		SUT.SetCloudTrailCache(cache)
+
+		stsMock.EXPECT().GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{}).Return(nil, errors.New("expired token")).Once()
+		cloudtrailMock.EXPECT().LookupEvents(context.TODO(), testifyMock.MatchedBy(func(in *cloudtrail.LookupEventsInput) bool {
+			return in.StartTime.Equal(starttime) && in.EndTime.Equal(endtime)
+		})).Return(&cloudtrail.LookupEventsOutput{}, nil).Twice()
+
+		_, err = SUT.GetCloudTrailForSecGroups(starttime, endtime)
+		require.NoError(t, err)
+
+		assert.Empty(t, cache.Scopes)
+		assert.NoFileExists(t, cachePath)
+	})
+}
+
+func TestLookupEventsThrottled(t *testing.T) {
+	starttime, err := time.Parse(time.DateTime, "2006-01-02 15:04:05")
+	require.NoError(t, err)
+	endtime, err := time.Parse(time.DateTime, "2006-01-30 15:04:05")
+	require.NoError(t, err)
+
+	lookupEventsIn := func(nextToken *string) *cloudtrail.LookupEventsInput {
+		return &cloudtrail.LookupEventsInput{
 			StartTime: &starttime,
 			EndTime:   &endtime,
 			LookupAttributes: []cloudtrailTypes.LookupAttribute{
@@ -229,28 +488,70 @@ func TestGetCloudTrailForSecGroups(t *testing.T) {
 					AttributeValue: aws.String("CreateSecurityGroup"),
 				},
 			},
+			NextToken: nextToken,
 		}
-		cloudtrailMOck.EXPECT().LookupEvents(context.TODO(), lookupEventsIn).Return(&cloudtrail.LookupEventsOutput{
-			Events: []cloudtrailTypes.Event{
-				{
-					EventTime: aws.Time(time.Now()),
-					Username:  aws.String("someuser"),
-					Resources: []cloudtrailTypes.Resource{
-						{
-							ResourceName: aws.String("somename"),
-							ResourceType: aws.String(CLOUDTRAIL_RESOURCE_TYPE),
-						},
-					},
-				},
-			},
-		}, nil)
+	}
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+	firstPage := &cloudtrail.LookupEventsOutput{
+		Events:    []cloudtrailTypes.Event{{EventName: aws.String("CreateSecurityGroup")}},
+		NextToken: aws.String("page2"),
+	}
+
+	setupThrottledSUT := func(t *testing.T) (*AWS, *mocks.MockCloudTrail) {
+		SUT, _, cloudtrailMock := setupSUT(t)
+		SUT.cloudTrailBackoff = backoff{base: time.Millisecond, max: 5 * time.Millisecond, retries: 2}
+		return SUT, cloudtrailMock
+	}
+
+	t.Run("Retry", func(t *testing.T) {
+		SUT, cloudtrailMock := setupThrottledSUT(t)
+
+		cloudtrailMock.EXPECT().LookupEvents(context.TODO(), lookupEventsIn(nil)).Return(nil, throttled).Once()
+		cloudtrailMock.EXPECT().LookupEvents(context.TODO(), lookupEventsIn(nil)).Return(firstPage, nil).Once()
+		cloudtrailMock.EXPECT().LookupEvents(context.TODO(), lookupEventsIn(aws.String("page2"))).Return(nil, throttled).Twice()
+		cloudtrailMock.EXPECT().LookupEvents(context.TODO(), lookupEventsIn(aws.String("page2"))).Return(&cloudtrail.LookupEventsOutput{
+			Events: []cloudtrailTypes.Event{{EventName: aws.String("CreateSecurityGroup")}},
+		}, nil).Once()
+
+		events, err := SUT.lookupEvents(SECURITYGROUP_CREATED, starttime, endtime)
+		require.NoError(t, err)
+		assert.Len(t, events, 2)
+	})
 
-		SUT.GetCloudTrailForSecGroups(starttime, endtime)
+	t.Run("Pages Lost", func(t *testing.T) {
+		SUT, cloudtrailMock := setupThrottledSUT(t)
 
-		ec2Mock.AssertExpectations(t)
-		cloudtrailMOck.AssertExpectations(t)
+		cloudtrailMock.EXPECT().LookupEvents(context.TODO(), lookupEventsIn(nil)).Return(firstPage, nil).Once()
+		cloudtrailMock.EXPECT().LookupEvents(context.TODO(), lookupEventsIn(aws.String("page2"))).Return(nil, throttled).Times(3)
+
+		_, err := SUT.GetCloudTrailForSecGroups(starttime, endtime)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "page 2")
+		assert.ErrorIs(t, err, throttled)
+	})
+
+	t.Run("Not Throttled Error", func(t *testing.T) {
+		SUT, cloudtrailMock := setupThrottledSUT(t)
+
+		accessDenied := &smithy.GenericAPIError{Code: "AccessDeniedException"}
+		cloudtrailMock.EXPECT().LookupEvents(context.TODO(), lookupEventsIn(nil)).Return(nil, accessDenied).Once()
+
+		_, err := SUT.lookupEvents(SECURITYGROUP_CREATED, starttime, endtime)
+		assert.ErrorIs(t, err, accessDenied)
 	})
+}
 
+func TestEventBefore(t *testing.T) {
+	now := time.Now()
+	olderThenDate := now.Add(-30 * 24 * time.Hour)
+	coveringLookup := olderThenDate
+	shortLookup := now.Add(-7 * 24 * time.Hour)
+
+	assert.True(t, EventBefore(aws.Time(olderThenDate.Add(-time.Hour)), shortLookup, olderThenDate))
+	assert.False(t, EventBefore(aws.Time(olderThenDate.Add(time.Hour)), coveringLookup, olderThenDate))
+	// without event it's only known to be older if the lookup covered the whole duration
+	assert.True(t, EventBefore(nil, coveringLookup, olderThenDate))
+	assert.False(t, EventBefore(nil, shortLookup, olderThenDate))
 }
 
 func TestDeleteSecurityGroup(t *testing.T) {
@@ -293,6 +594,79 @@ func TestDeleteSecurityGroup(t *testing.T) {
 	})
 }
 
+func TestGetManagedPrefixListIds(t *testing.T) {
+	t.Run("Paginated", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+		mock.EXPECT().DescribeManagedPrefixLists(context.TODO(), &ec2.DescribeManagedPrefixListsInput{}).Return(&ec2.DescribeManagedPrefixListsOutput{
+			PrefixLists: []types.ManagedPrefixList{{PrefixListId: aws.String("pl-1")}},
+			NextToken:   aws.String("next"),
+		}, nil).Once()
+		mock.EXPECT().DescribeManagedPrefixLists(context.TODO(), &ec2.DescribeManagedPrefixListsInput{NextToken: aws.String("next")}).Return(&ec2.DescribeManagedPrefixListsOutput{
+			PrefixLists: []types.ManagedPrefixList{{PrefixListId: aws.String("pl-2")}},
+		}, nil).Once()
+
+		prefixListIds, err := SUT.GetManagedPrefixListIds()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"pl-1", "pl-2"}, prefixListIds)
+	})
+
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+		mock.EXPECT().DescribeManagedPrefixLists(context.TODO(), &ec2.DescribeManagedPrefixListsInput{}).Return(nil, fmt.Errorf("Something went wrong")).Once()
+
+		_, err := SUT.GetManagedPrefixListIds()
+		require.EqualError(t, err, "Something went wrong")
+	})
+}
+
+func TestGetStaleSecurityGroups(t *testing.T) {
+	SUT, mock, _ := setupSUT(t)
+	mock.EXPECT().DescribeStaleSecurityGroups(context.TODO(), &ec2.DescribeStaleSecurityGroupsInput{VpcId: aws.String("vpc-1")}).Return(&ec2.DescribeStaleSecurityGroupsOutput{
+		StaleSecurityGroupSet: []types.StaleSecurityGroup{{GroupId: aws.String("sg-1")}},
+		NextToken:             aws.String("next"),
+	}, nil).Once()
+	mock.EXPECT().DescribeStaleSecurityGroups(context.TODO(), &ec2.DescribeStaleSecurityGroupsInput{VpcId: aws.String("vpc-1"), NextToken: aws.String("next")}).Return(&ec2.DescribeStaleSecurityGroupsOutput{
+		StaleSecurityGroupSet: []types.StaleSecurityGroup{{GroupId: aws.String("sg-2")}},
+	}, nil).Once()
+
+	staleGroups, err := SUT.GetStaleSecurityGroups("vpc-1")
+	require.NoError(t, err)
+	require.Len(t, staleGroups, 2)
+	assert.Equal(t, "sg-2", *staleGroups[1].GroupId)
+}
+
+func TestRevokeSecurityGroupRule(t *testing.T) {
+	perm := types.IpPermission{
+		IpProtocol:    aws.String("tcp"),
+		FromPort:      aws.Int32(443),
+		ToPort:        aws.Int32(443),
+		PrefixListIds: []types.PrefixListId{{PrefixListId: aws.String("pl-1")}},
+	}
+
+	t.Run("Ingress", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+		mock.EXPECT().RevokeSecurityGroupIngress(context.TODO(), &ec2.RevokeSecurityGroupIngressInput{
+			DryRun:        aws.Bool(false),
+			GroupId:       aws.String("sg-1"),
+			IpPermissions: []types.IpPermission{perm},
+		}).Return(nil, nil).Once()
+
+		require.NoError(t, SUT.RevokeSecurityGroupRule("sg-1", false, perm, false))
+	})
+
+	t.Run("Egress", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+		mock.EXPECT().RevokeSecurityGroupEgress(context.TODO(), &ec2.RevokeSecurityGroupEgressInput{
+			DryRun:        aws.Bool(true),
+			GroupId:       aws.String("sg-1"),
+			IpPermissions: []types.IpPermission{perm},
+		}).Return(nil, fmt.Errorf("Something went wrong")).Once()
+
+		err := SUT.RevokeSecurityGroupRule("sg-1", true, perm, true)
+		require.EqualError(t, err, "Something went wrong")
+	})
+}
+
 func TestGetUsedAMIsFromEC2(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
@@ -369,11 +743,132 @@ func TestGetUsedAMIsFromLaunchTpls(t *testing.T) {
 		}
 		mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), expectedOpts2).Return(expectedOutput2, nil).Once()
 
-		usedAmis := SUT.GetUsedAMIsFromLaunchTpls()
+		usedAmis, err := SUT.GetUsedAMIsFromLaunchTpls(LAUNCH_TPL_LATEST)
+		require.NoError(t, err)
 		assert.Len(t, usedAmis, 2)
 
 		mock.AssertExpectations(t)
 	})
+
+	t.Run("Default Versions", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+
+		expectedOpts := &ec2.DescribeLaunchTemplateVersionsInput{Versions: []string{"$Default"}}
+		expectedOutput := &ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []types.LaunchTemplateVersion{
+				{
+					LaunchTemplateName: aws.String("my-template"),
+					VersionNumber:      aws.Int64(3),
+					LaunchTemplateData: &types.ResponseLaunchTemplateData{ImageId: aws.String("1234")},
+				},
+			},
+		}
+		mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), expectedOpts).Return(expectedOutput, nil).Once()
+
+		usedAmis, err := SUT.GetUsedAMIsFromLaunchTpls(LAUNCH_TPL_DEFAULT)
+		require.NoError(t, err)
+		assert.Equal(t, AMIUsage{"1234": "launch template my-template version 3"}, usedAmis)
+	})
+
+	t.Run("Referenced Versions", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+
+		mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{Versions: []string{"$Default"}}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []types.LaunchTemplateVersion{
+				{LaunchTemplateName: aws.String("web"), VersionNumber: aws.Int64(5), LaunchTemplateData: &types.ResponseLaunchTemplateData{ImageId: aws.String("ami-default")}},
+			},
+		}, nil).Once()
+		mock.EXPECT().DescribeInstances(context.TODO(), &ec2.DescribeInstancesInput{
+			Filters: []types.Filter{
+				{Name: aws.String("tag-key"), Values: []string{LAUNCH_TPL_ID_TAG}},
+				{Name: aws.String("instance-state-name"), Values: []string{"pending", "running", "shutting-down", "stopping", "stopped"}},
+			},
+		}).Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{{
+				Instances: []types.Instance{
+					{InstanceId: aws.String("i-1"), Tags: []types.Tag{{Key: aws.String(LAUNCH_TPL_ID_TAG), Value: aws.String("lt-web")}, {Key: aws.String(LAUNCH_TPL_VERSION_TAG), Value: aws.String("3")}}},
+					{InstanceId: aws.String("i-2"), Tags: []types.Tag{{Key: aws.String(LAUNCH_TPL_ID_TAG), Value: aws.String("lt-deleted")}, {Key: aws.String(LAUNCH_TPL_VERSION_TAG), Value: aws.String("1")}}},
+				},
+			}},
+		}, nil).Once()
+		mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String("lt-web"), Versions: []string{"3"}}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []types.LaunchTemplateVersion{
+				{LaunchTemplateName: aws.String("web"), VersionNumber: aws.Int64(3), LaunchTemplateData: &types.ResponseLaunchTemplateData{ImageId: aws.String("ami-launched")}},
+			},
+		}, nil).Once()
+		mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String("lt-deleted"), Versions: []string{"1"}}).Return(nil, &smithy.GenericAPIError{Code: "InvalidLaunchTemplateId.NotFound"}).Once()
+
+		usedAmis, err := SUT.GetUsedAMIsFromLaunchTpls(LAUNCH_TPL_REFERENCED)
+		require.NoError(t, err)
+		assert.Equal(t, AMIUsage{
+			"ami-default":  "launch template web version 5",
+			"ami-launched": "launch template web version 3",
+		}, usedAmis)
+	})
+
+	t.Run("All Versions", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+
+		mock.EXPECT().DescribeLaunchTemplates(context.TODO(), &ec2.DescribeLaunchTemplatesInput{}).Return(&ec2.DescribeLaunchTemplatesOutput{
+			LaunchTemplates: []types.LaunchTemplate{{LaunchTemplateId: aws.String("lt-1")}},
+			NextToken:       aws.String("next"),
+		}, nil).Once()
+		mock.EXPECT().DescribeLaunchTemplates(context.TODO(), &ec2.DescribeLaunchTemplatesInput{NextToken: aws.String("next")}).Return(&ec2.DescribeLaunchTemplatesOutput{
+			LaunchTemplates: []types.LaunchTemplate{{LaunchTemplateId: aws.String("lt-2")}},
+		}, nil).Once()
+
+		for _, launchTplId := range []string{"lt-1", "lt-2"} {
+			expectedOpts := &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String(launchTplId)}
+			expectedOutput := &ec2.DescribeLaunchTemplateVersionsOutput{
+				LaunchTemplateVersions: []types.LaunchTemplateVersion{
+					{LaunchTemplateData: &types.ResponseLaunchTemplateData{ImageId: aws.String(launchTplId + "-v1")}},
+					{LaunchTemplateData: &types.ResponseLaunchTemplateData{ImageId: aws.String(launchTplId + "-v2")}},
+				},
+			}
+			mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), expectedOpts).Return(expectedOutput, nil).Once()
+		}
+
+		usedAmis, err := SUT.GetUsedAMIsFromLaunchTpls(LAUNCH_TPL_ALL)
+		require.NoError(t, err)
+		assert.Len(t, usedAmis, 4)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+
+		mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{Versions: []string{"$Latest"}}).Return(nil, errors.New("some error")).Once()
+
+		_, err := SUT.GetUsedAMIsFromLaunchTpls(LAUNCH_TPL_LATEST)
+		require.EqualError(t, err, "some error")
+	})
+}
+
+func TestGetUsedAMIsFromAutoScalingGroups(t *testing.T) {
+	SUT, ec2Mock, _ := setupSUT(t)
+	autoScalingMock := mocks.NewMockAutoScaling(t)
+	WithAutoScaling(autoScalingMock)(SUT)
+
+	autoScalingMock.EXPECT().DescribeAutoScalingGroups(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{}).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+		AutoScalingGroups: []autoscalingTypes.AutoScalingGroup{
+			{
+				AutoScalingGroupName: aws.String("my-group"),
+				LaunchTemplate:       &autoscalingTypes.LaunchTemplateSpecification{LaunchTemplateId: aws.String("lt-1"), Version: aws.String("2")},
+			},
+		},
+	}, nil).Once()
+	ec2Mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String("lt-1"), Versions: []string{"2"}}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+		LaunchTemplateVersions: []types.LaunchTemplateVersion{
+			{
+				LaunchTemplateName: aws.String("my-template"),
+				VersionNumber:      aws.Int64(2),
+				LaunchTemplateData: &types.ResponseLaunchTemplateData{ImageId: aws.String("1234")},
+			},
+		},
+	}, nil).Once()
+
+	usedAmis, err := SUT.GetUsedAMIsFromAutoScalingGroups()
+	require.NoError(t, err)
+	assert.Equal(t, AMIUsage{"1234": "launch template my-template version 2 of Auto Scaling group my-group"}, usedAmis)
 }
 
 func TestDescribeImages(t *testing.T) {
@@ -383,25 +878,60 @@ func TestDescribeImages(t *testing.T) {
 
 		SUT, mock, _ := setupSUT(t)
 		expectedOpts := &ec2.DescribeImagesInput{
-			Owners: []string{"self", expetedAccountID},
+			Owners:          []string{"self", expetedAccountID},
+			IncludeDisabled: aws.Bool(true),
 		}
 		mock.EXPECT().DescribeImages(context.TODO(), expectedOpts).Return(&ec2.DescribeImagesOutput{Images: []types.Image{}}, nil).Once()
 
-		out, err := SUT.DescribeImages(expetedAccountID)
+		out, err := SUT.DescribeImages(expetedAccountID, true)
 		require.NoError(t, err)
 		assert.NotNil(t, out)
 	})
 
+	t.Run("With Paging And Filters", func(t *testing.T) {
+		expectedNextToken := "next token"
+		expectedFilters := []types.Filter{
+			{Name: aws.String("architecture"), Values: []string{"arm64"}},
+		}
+
+		SUT, mock, _ := setupSUT(t)
+		expectedOpts1 := &ec2.DescribeImagesInput{
+			Owners:  []string{"self"},
+			Filters: expectedFilters,
+		}
+		expectedOut1 := &ec2.DescribeImagesOutput{
+			NextToken: &expectedNextToken,
+			Images:    []types.Image{{ImageId: aws.String("ami-1")}},
+		}
+		mock.EXPECT().DescribeImages(context.TODO(), expectedOpts1).Return(expectedOut1, nil).Once()
+		expectedOpts2 := &ec2.DescribeImagesInput{
+			Owners:    []string{"self"},
+			Filters:   expectedFilters,
+			NextToken: &expectedNextToken,
+		}
+		expectedOut2 := &ec2.DescribeImagesOutput{
+			Images: []types.Image{{ImageId: aws.String("ami-2")}},
+		}
+		mock.EXPECT().DescribeImages(context.TODO(), expectedOpts2).Return(expectedOut2, nil).Once()
+
+		out, err := SUT.DescribeImages("", false, expectedFilters...)
+		require.NoError(t, err)
+		assert.Len(t, out, 2)
+
+		mock.AssertExpectations(t)
+	})
+
 	t.Run("Error from AWS", func(t *testing.T) {
 		expetedAccountID := "1234567890"
 
 		SUT, mock, _ := setupSUT(t)
 		expectedOpts := &ec2.DescribeImagesInput{
-			Owners: []string{"self", expetedAccountID},
+			Owners:          []string{"self", expetedAccountID},
+			IncludeDisabled: aws.Bool(true),
 		}
 		mock.EXPECT().DescribeImages(context.TODO(), expectedOpts).Return(nil, fmt.Errorf("Something went wrong")).Once()
 
-		out, err := SUT.DescribeImages(expetedAccountID)
+		out, err := SUT.DescribeImages(expetedAccountID, true)
 		assert.Nil(t, out)
 		require.Error(t, err)
 		require.EqualError(t, err, "Something went wrong")
@@ -519,3 +1049,161 @@ func TestDeleteVolume(t *testing.T) {
 	})
 
 }
+
+func TestGetEnabledRegions(t *testing.T) {
+
+	t.Run("Success", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+
+		expectedOpts := &ec2.DescribeRegionsInput{}
+		expectedOut := &ec2.DescribeRegionsOutput{
+			Regions: []types.Region{
+				{RegionName: aws.String("eu-central-1")},
+				{RegionName: aws.String("us-east-1")},
+			},
+		}
+		mock.EXPECT().DescribeRegions(context.TODO(), expectedOpts).Return(expectedOut, nil).Once()
+
+		regions, err := SUT.GetEnabledRegions()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"eu-central-1", "us-east-1"}, regions)
+
+		mock.AssertExpectations(t)
+	})
+
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+
+		mock.EXPECT().DescribeRegions(context.TODO(), &ec2.DescribeRegionsInput{}).Return(nil, fmt.Errorf("Something went wrong")).Once()
+
+		regions, err := SUT.GetEnabledRegions()
+		assert.Nil(t, regions)
+		require.EqualError(t, err, "Something went wrong")
+
+		mock.AssertExpectations(t)
+	})
+}
+
+func TestAssumeRole(t *testing.T) {
+
+	t.Run("Success", func(t *testing.T) {
+		expectedAccountID := "123456789012"
+		expectedRoleARN := "arn:aws:iam::123456789012:role/cleanup"
+		expectedExternalID := "some-external-id"
+
+		stsMock := mocks.NewMockSTS(t)
+		SUT := NewFromInterface(mocks.NewMockEc2client(t), mocks.NewMockCloudTrail(t), WithSTS(stsMock))
+
+		stsMock.EXPECT().AssumeRole(testifyMock.Anything, testifyMock.MatchedBy(func(in *sts.AssumeRoleInput) bool {
+			return *in.RoleArn == expectedRoleARN &&
+				*in.RoleSessionName == ROLE_SESSION_NAME &&
+				*in.ExternalId == expectedExternalID
+		})).Return(&sts.AssumeRoleOutput{
+			Credentials: &stsTypes.Credentials{
+				AccessKeyId:     aws.String("access-key"),
+				SecretAccessKey: aws.String("secret"),
+				SessionToken:    aws.String("token"),
+				Expiration:      aws.Time(time.Now().Add(time.Hour)),
+			},
+		}, nil).Once()
+
+		assumed := SUT.AssumeRole(expectedAccountID, expectedRoleARN, expectedExternalID)
+		assert.Equal(t, expectedAccountID, assumed.AccountID())
+
+		creds, err := assumed.cfg.Credentials.Retrieve(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, "access-key", creds.AccessKeyID)
+
+		stsMock.AssertExpectations(t)
+	})
+
+	t.Run("Keep Account For Region", func(t *testing.T) {
+		SUT := NewFromInterface(mocks.NewMockEc2client(t), mocks.NewMockCloudTrail(t), WithSTS(mocks.NewMockSTS(t)))
+
+		regional := SUT.AssumeRole("123456789012", "arn:aws:iam::123456789012:role/cleanup", "").ForRegion("eu-west-1")
+		assert.Equal(t, "123456789012", regional.AccountID())
+		assert.Equal(t, "eu-west-1", regional.Region())
+	})
+}
+
+func TestDeleteSnapshot(t *testing.T) {
+
+	t.Run("Success", func(t *testing.T) {
+		snapshotID := "snap-1234"
+		dryRun := false
+
+		SUT, mock, _ := setupSUT(t)
+
+		expectedOpts := &ec2.DeleteSnapshotInput{
+			SnapshotId: &snapshotID,
+			DryRun:     &dryRun,
+		}
+		mock.EXPECT().DeleteSnapshot(context.TODO(), expectedOpts).Return(&ec2.DeleteSnapshotOutput{}, nil).Once()
+
+		err := SUT.DeleteSnapshot(snapshotID, dryRun)
+		require.NoError(t, err)
+
+		mock.AssertExpectations(t)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		snapshotID := "snap-1234"
+		dryRun := true
+
+		SUT, mock, _ := setupSUT(t)
+
+		expectedOpts := &ec2.DeleteSnapshotInput{
+			SnapshotId: &snapshotID,
+			DryRun:     &dryRun,
+		}
+		mock.EXPECT().DeleteSnapshot(context.TODO(), expectedOpts).Return(nil, &smithy.GenericAPIError{Code: "DryRunOperation"}).Once()
+
+		err := SUT.DeleteSnapshot(snapshotID, dryRun)
+		require.Error(t, err)
+		assert.True(t, IsDryRunOperation(err))
+		assert.False(t, IsDryRunOperation(fmt.Errorf("Something went wrong")))
+
+		mock.AssertExpectations(t)
+	})
+}
+
+func TestGetOwnSnapshots(t *testing.T) {
+
+	t.Run("Success", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+
+		expectedNextToken := "12345"
+		expectedOpts1 := &ec2.DescribeSnapshotsInput{OwnerIds: []string{"self"}}
+		expectedOutput1 := &ec2.DescribeSnapshotsOutput{
+			NextToken: &expectedNextToken,
+			Snapshots: []types.Snapshot{{}, {}},
+		}
+		mock.EXPECT().DescribeSnapshots(context.TODO(), expectedOpts1).Return(expectedOutput1, nil).Once()
+		expectedOpts2 := &ec2.DescribeSnapshotsInput{
+			OwnerIds:  []string{"self"},
+			NextToken: &expectedNextToken,
+		}
+		expectedOutput2 := &ec2.DescribeSnapshotsOutput{
+			Snapshots: []types.Snapshot{{}},
+		}
+		mock.EXPECT().DescribeSnapshots(context.TODO(), expectedOpts2).Return(expectedOutput2, nil).Once()
+
+		snapshots, err := SUT.GetOwnSnapshots()
+		require.NoError(t, err)
+		assert.Len(t, snapshots, 3)
+
+		mock.AssertExpectations(t)
+	})
+
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+
+		mock.EXPECT().DescribeSnapshots(context.TODO(), &ec2.DescribeSnapshotsInput{OwnerIds: []string{"self"}}).Return(nil, fmt.Errorf("Something went wrong")).Once()
+
+		snapshots, err := SUT.GetOwnSnapshots()
+		assert.Nil(t, snapshots)
+		require.EqualError(t, err, "Something went wrong")
+
+		mock.AssertExpectations(t)
+	})
+}
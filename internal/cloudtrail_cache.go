@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// CLOUDTRAIL_CACHE_FILE is the name of the cache file in the awsclean config directory.
+	CLOUDTRAIL_CACHE_FILE = "cloudtrail-cache.json"
+	// CLOUDTRAIL_RETENTION is how long LookupEvents returns events.
+	CLOUDTRAIL_RETENTION = 90 * 24 * time.Hour
+	// CloudTrail delivers events up to 15 minutes late, so the last lookup is repeated for this duration.
+	CLOUDTRAIL_DELIVERY_DELAY = 15 * time.Minute
+)
+
+// CloudTrailCache persists the lifecycle events of security groups as CloudTrail only keeps them for 90 days.
+// The events are stored per account and region.
+type CloudTrailCache struct {
+	path   string
+	Scopes map[string]*CloudTrailCacheScope
+}
+
+// CloudTrailCacheScope holds the events of one account and region.
+type CloudTrailCacheScope struct {
+	// the time range which was already looked up in CloudTrail
+	From time.Time
+	To   time.Time
+	// lifecycle events by the CloudTrail resource name of the security group
+	Events map[string][]LifecycleEvent
+}
+
+// DefaultCloudTrailCachePath returns the path of the cache file in ~/.config/awsclean.
+func DefaultCloudTrailCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "awsclean", CLOUDTRAIL_CACHE_FILE), nil
+}
+
+// LoadCloudTrailCache reads the cache from path. If the file doesn't exist an empty cache is returned.
+func LoadCloudTrailCache(path string) (*CloudTrailCache, error) {
+	cache := &CloudTrailCache{path: path, Scopes: map[string]*CloudTrailCacheScope{}}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read CloudTrail cache: %w", err)
+	}
+
+	if err := json.Unmarshal(content, cache); err != nil {
+		return nil, fmt.Errorf("could not parse CloudTrail cache %s: %w", path, err)
+	}
+	if cache.Scopes == nil {
+		cache.Scopes = map[string]*CloudTrailCacheScope{}
+	}
+	return cache, nil
+}
+
+// Save writes the cache to its file. The file is replaced atomically so an interrupted run doesn't corrupt it.
+func (c *CloudTrailCache) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("could not create directory for CloudTrail cache: %w", err)
+	}
+
+	content, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return fmt.Errorf("could not write CloudTrail cache: %w", err)
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// Scope returns the cached events of the given scope. A new scope is created if it doesn't exist yet.
+func (c *CloudTrailCache) Scope(name string) *CloudTrailCacheScope {
+	scope, exists := c.Scopes[name]
+	if !exists {
+		scope = &CloudTrailCacheScope{Events: map[string][]LifecycleEvent{}}
+		c.Scopes[name] = scope
+	}
+	return scope
+}
+
+// LookupStart returns the time from which events must be looked up in CloudTrail to cover startTime. If
+// the scope already covers startTime only events after the last lookup are needed. Events older then
+// oldestAvailable can't be looked up anymore, so they count as covered.
+func (s CloudTrailCacheScope) LookupStart(startTime, oldestAvailable time.Time) time.Time {
+	if s.To.IsZero() || s.To.Before(startTime) {
+		return startTime
+	}
+	if startTime.Before(oldestAvailable) {
+		startTime = oldestAvailable
+	}
+	if s.From.After(startTime) {
+		return startTime
+	}
+	return s.To.Add(-CLOUDTRAIL_DELIVERY_DELAY)
+}
+
+// Add records that events were looked up between from and to.
+func (s *CloudTrailCacheScope) Add(from, to time.Time, events map[string][]LifecycleEvent) {
+	if s.To.IsZero() || from.After(s.To) {
+		// a gap to the previous lookups, so only the new range is covered
+		s.From = from
+	} else if from.Before(s.From) {
+		s.From = from
+	}
+	if to.After(s.To) {
+		s.To = to
+	}
+	if s.Events == nil {
+		s.Events = map[string][]LifecycleEvent{}
+	}
+
+	for resourceName, resourceEvents := range events {
+		grp := &SecurityGroup{History: s.Events[resourceName]}
+		for _, ev := range resourceEvents {
+			grp.addLifecycleEvent(ev)
+		}
+		s.Events[resourceName] = grp.History
+	}
+}
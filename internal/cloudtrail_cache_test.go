@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCloudTrailCache(t *testing.T) {
+	t.Run("Not Existing", func(t *testing.T) {
+		cache, err := LoadCloudTrailCache(filepath.Join(t.TempDir(), CLOUDTRAIL_CACHE_FILE))
+		require.NoError(t, err)
+		assert.Empty(t, cache.Scopes)
+	})
+
+	t.Run("Save And Load", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "awsclean", CLOUDTRAIL_CACHE_FILE)
+		createdAt := time.Now().Add(-100 * 24 * time.Hour).UTC()
+
+		cache, err := LoadCloudTrailCache(path)
+		require.NoError(t, err)
+		cache.Scope("123/eu-central-1").Add(createdAt, createdAt.Add(time.Hour), map[string][]LifecycleEvent{
+			"sg-1": {{EventName: string(SECURITYGROUP_CREATED), Username: "creator", EventTime: aws.Time(createdAt)}},
+		})
+		require.NoError(t, cache.Save())
+
+		loaded, err := LoadCloudTrailCache(path)
+		require.NoError(t, err)
+		require.Contains(t, loaded.Scopes, "123/eu-central-1")
+		scope := loaded.Scopes["123/eu-central-1"]
+		assert.True(t, createdAt.Equal(scope.From))
+		require.Len(t, scope.Events["sg-1"], 1)
+		assert.Equal(t, "creator", scope.Events["sg-1"][0].Username)
+	})
+
+	t.Run("Invalid File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), CLOUDTRAIL_CACHE_FILE)
+		require.NoError(t, os.WriteFile(path, []byte("no json"), 0o600))
+
+		_, err := LoadCloudTrailCache(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestLookupStart(t *testing.T) {
+	now := time.Now()
+	oldestAvailable := now.Add(CLOUDTRAIL_RETENTION * -1)
+	lastRun := now.Add(-time.Hour)
+
+	tblTest := map[string]struct {
+		scope     CloudTrailCacheScope
+		startTime time.Time
+		expected  time.Time
+	}{
+		"empty cache": {
+			scope:     CloudTrailCacheScope{},
+			startTime: oldestAvailable,
+			expected:  oldestAvailable,
+		},
+		"covered": {
+			scope:     CloudTrailCacheScope{From: oldestAvailable.Add(-time.Hour), To: lastRun},
+			startTime: oldestAvailable,
+			expected:  lastRun.Add(CLOUDTRAIL_DELIVERY_DELAY * -1),
+		},
+		"older then available": {
+			scope:     CloudTrailCacheScope{From: oldestAvailable, To: lastRun},
+			startTime: oldestAvailable.Add(-24 * time.Hour),
+			expected:  lastRun.Add(CLOUDTRAIL_DELIVERY_DELAY * -1),
+		},
+		"start before covered": {
+			scope:     CloudTrailCacheScope{From: now.Add(-48 * time.Hour), To: lastRun},
+			startTime: now.Add(-72 * time.Hour),
+			expected:  now.Add(-72 * time.Hour),
+		},
+		"start after covered": {
+			scope:     CloudTrailCacheScope{From: now.Add(-48 * time.Hour), To: now.Add(-24 * time.Hour)},
+			startTime: lastRun,
+			expected:  lastRun,
+		},
+	}
+	for name, test := range tblTest {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.scope.LookupStart(test.startTime, oldestAvailable))
+		})
+	}
+}
+
+func TestCloudTrailCacheScopeAdd(t *testing.T) {
+	now := time.Now()
+	createdAt := now.Add(-2 * time.Hour)
+	deletedAt := now.Add(-time.Hour)
+
+	scope := &CloudTrailCacheScope{}
+	scope.Add(now.Add(-3*time.Hour), deletedAt, map[string][]LifecycleEvent{
+		"sg-1": {{EventName: string(SECURITYGROUP_CREATED), Username: "creator", EventTime: aws.Time(createdAt)}},
+	})
+	// the overlapping lookup returns the create event again
+	scope.Add(deletedAt.Add(CLOUDTRAIL_DELIVERY_DELAY*-1), now, map[string][]LifecycleEvent{
+		"sg-1": {
+			{EventName: string(SECURITYGROUP_CREATED), Username: "creator", EventTime: aws.Time(createdAt)},
+			{EventName: string(SECURITYGROUP_DELETED), Username: "deleter", EventTime: aws.Time(deletedAt)},
+		},
+	})
+
+	assert.Equal(t, now.Add(-3*time.Hour), scope.From)
+	assert.Equal(t, now, scope.To)
+	require.Len(t, scope.Events["sg-1"], 2)
+	assert.Equal(t, string(SECURITYGROUP_DELETED), scope.Events["sg-1"][1].EventName)
+
+	t.Run("Gap", func(t *testing.T) {
+		scope.Add(now.Add(time.Hour), now.Add(2*time.Hour), nil)
+		assert.Equal(t, now.Add(time.Hour), scope.From)
+		assert.Equal(t, now.Add(2*time.Hour), scope.To)
+		assert.Len(t, scope.Events["sg-1"], 2)
+	})
+}
@@ -0,0 +1,271 @@
+package internal
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/steffakasid/eslog"
+)
+
+const S3_SCHEME = "s3://"
+
+// CloudTrail names log files <account>_CloudTrail_<region>_<YYYYMMDDTHHmmZ>_<unique>.json.gz
+var cloudTrailLogFileName = regexp.MustCompile(`^(\d{12})_CloudTrail_([a-z0-9-]+)_(\d{8}T\d{4}Z)_`)
+
+// CloudTrail stores log files below AWSLogs/[<organization>/]<account>/CloudTrail/<region>/<YYYY>/<MM>/<DD>/
+var cloudTrailLogsDir = regexp.MustCompile(`(^|/)AWSLogs/(o-[a-z0-9]+/)?$`)
+
+// CloudTrailLogs reads the security group events from CloudTrail log files in S3 or a local directory.
+// In contrast to LookupEvents the log files can cover more then 90 days.
+type CloudTrailLogs struct {
+	location string
+	s3       S3
+	// only events of this account and region are returned, the account is ignored if empty
+	account string
+	region  string
+}
+
+type cloudTrailLogFile struct {
+	Records []cloudTrailRecord
+}
+
+type cloudTrailRecord struct {
+	EventName          string    `json:"eventName"`
+	EventTime          time.Time `json:"eventTime"`
+	AwsRegion          string    `json:"awsRegion"`
+	RecipientAccountId string    `json:"recipientAccountId"`
+	ErrorCode          string    `json:"errorCode"`
+	UserIdentity       struct {
+		UserName string `json:"userName"`
+		Arn      string `json:"arn"`
+	} `json:"userIdentity"`
+	RequestParameters struct {
+		GroupId   string `json:"groupId"`
+		GroupName string `json:"groupName"`
+	} `json:"requestParameters"`
+	ResponseElements struct {
+		GroupId string `json:"groupId"`
+	} `json:"responseElements"`
+}
+
+// NewCloudTrailLogs returns an event source for the log files at location, which is either an S3 URL like
+// s3://bucket/prefix or a local directory. Only events of the account and region of the client are used.
+func (a AWS) NewCloudTrailLogs(location string) *CloudTrailLogs {
+	return &CloudTrailLogs{
+		location: location,
+		s3:       a.s3,
+		account:  a.callerAccountID(),
+		region:   a.Region(),
+	}
+}
+
+func (l CloudTrailLogs) String() string {
+	return l.location
+}
+
+func (l CloudTrailLogs) SecGrpEvents(startTime, endTime time.Time) (map[string][]LifecycleEvent, error) {
+	events := map[string][]LifecycleEvent{}
+	read := func(name string, open func() (io.ReadCloser, error)) error {
+		if !l.logFileMatches(path.Base(name), startTime, endTime) {
+			return nil
+		}
+		eslog.Logger.Debugf("Reading CloudTrail log file %s", name)
+
+		file, err := open()
+		if err != nil {
+			return fmt.Errorf("could not open %s: %w", name, err)
+		}
+		defer file.Close()
+
+		err = l.addEvents(events, file, strings.HasSuffix(name, ".gz"), startTime, endTime)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if strings.HasPrefix(l.location, S3_SCHEME) {
+		return events, l.walkS3(read, startTime, endTime)
+	}
+	return events, l.walkDir(read)
+}
+
+func (l CloudTrailLogs) walkDir(read func(string, func() (io.ReadCloser, error)) error) error {
+	return filepath.WalkDir(l.location, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isCloudTrailLogFile(name) {
+			return nil
+		}
+		return read(name, func() (io.ReadCloser, error) {
+			return os.Open(name)
+		})
+	})
+}
+
+func (l CloudTrailLogs) walkS3(read func(string, func() (io.ReadCloser, error)) error, startTime, endTime time.Time) error {
+	if l.s3 == nil {
+		return fmt.Errorf("no S3 client to read %s", l.location)
+	}
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(l.location, S3_SCHEME), "/")
+
+	for _, dayPrefix := range l.s3Prefixes(prefix, startTime, endTime) {
+		err := l.walkS3Prefix(read, bucket, dayPrefix)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// s3Prefixes returns the prefixes to list below prefix. If prefix points to the AWSLogs directory of a trail,
+// e.g. of an organization trail, only the directories of the account and region for each day between
+// startTime and endTime are listed. Otherwise the whole prefix is listed.
+func (l CloudTrailLogs) s3Prefixes(prefix string, startTime, endTime time.Time) []string {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if l.account == "" || l.region == "" || !cloudTrailLogsDir.MatchString(prefix) {
+		return []string{prefix}
+	}
+
+	prefixes := []string{}
+	day := startTime.UTC().Truncate(24 * time.Hour)
+	// a file contains the events of some minutes before it was delivered
+	for lastDay := endTime.Add(time.Hour).UTC(); !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		prefixes = append(prefixes, fmt.Sprintf("%s%s/CloudTrail/%s/%s/", prefix, l.account, l.region, day.Format("2006/01/02")))
+	}
+	return prefixes
+}
+
+func (l CloudTrailLogs) walkS3Prefix(read func(string, func() (io.ReadCloser, error)) error, bucket, prefix string) error {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}
+	for {
+		out, err := l.s3.ListObjectsV2(context.TODO(), input)
+		if err != nil {
+			return fmt.Errorf("could not list %s: %w", l.location, err)
+		}
+
+		for _, object := range out.Contents {
+			key := aws.ToString(object.Key)
+			if !isCloudTrailLogFile(key) {
+				continue
+			}
+			err := read(key, func() (io.ReadCloser, error) {
+				obj, err := l.s3.GetObject(context.TODO(), &s3.GetObjectInput{Bucket: aws.String(bucket), Key: object.Key})
+				if err != nil {
+					return nil, err
+				}
+				return obj.Body, nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if !aws.ToBool(out.IsTruncated) || out.NextContinuationToken == nil {
+			return nil
+		}
+		input.ContinuationToken = out.NextContinuationToken
+	}
+}
+
+func isCloudTrailLogFile(name string) bool {
+	return strings.HasSuffix(name, ".json.gz") || strings.HasSuffix(name, ".json")
+}
+
+// logFileMatches uses the name of a log file to skip files of other accounts, regions or times. Files which
+// are not named like CloudTrail does are always read.
+func (l CloudTrailLogs) logFileMatches(name string, startTime, endTime time.Time) bool {
+	match := cloudTrailLogFileName.FindStringSubmatch(name)
+	if match == nil {
+		return true
+	}
+	if (l.account != "" && match[1] != l.account) || (l.region != "" && match[2] != l.region) {
+		return false
+	}
+	deliveredAt, err := time.Parse("20060102T1504Z", match[3])
+	if err != nil {
+		return true
+	}
+	// a file contains the events of some minutes before it was delivered
+	return !deliveredAt.Before(startTime) && !deliveredAt.After(endTime.Add(time.Hour))
+}
+
+func (l CloudTrailLogs) addEvents(events map[string][]LifecycleEvent, file io.Reader, gzipped bool, startTime, endTime time.Time) error {
+	if gzipped {
+		gzReader, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gzReader.Close()
+		file = gzReader
+	}
+
+	logFile := cloudTrailLogFile{}
+	if err := json.NewDecoder(file).Decode(&logFile); err != nil {
+		return err
+	}
+
+	for _, record := range logFile.Records {
+		// like the resource names of LookupEvents this is either the group ID or the group name, names are
+		// resolved when the events are merged into the security groups
+		var resourceName string
+		switch cloudTrailEventType(record.EventName) {
+		case SECURITYGROUP_CREATED:
+			resourceName = record.ResponseElements.GroupId
+		case SECURITYGROUP_DELETED:
+			// calls in EC2-Classic or the default VPC may only pass the group name
+			resourceName = record.RequestParameters.GroupId
+			if resourceName == "" {
+				resourceName = record.RequestParameters.GroupName
+			}
+		default:
+			continue
+		}
+
+		if resourceName == "" {
+			eslog.Logger.Debugf("Skipping %s event of %s without group ID or name", record.EventName, record.EventTime.Format(time.RFC3339))
+			continue
+		}
+		if record.ErrorCode != "" ||
+			(l.region != "" && record.AwsRegion != l.region) ||
+			(l.account != "" && record.RecipientAccountId != l.account) ||
+			record.EventTime.Before(startTime) || record.EventTime.After(endTime) {
+			continue
+		}
+
+		events[resourceName] = append(events[resourceName], LifecycleEvent{
+			EventName: record.EventName,
+			Username:  record.username(),
+			EventTime: aws.Time(record.EventTime),
+		})
+	}
+	return nil
+}
+
+// username returns the same name LookupEvents reports: the IAM user or the session name of an assumed role.
+func (r cloudTrailRecord) username() string {
+	if r.UserIdentity.UserName != "" {
+		return r.UserIdentity.UserName
+	}
+	// e.g. arn:aws:sts::123456789012:assumed-role/cleanup/session or arn:aws:iam::123456789012:root
+	arn := r.UserIdentity.Arn
+	return arn[strings.LastIndexAny(arn, "/:")+1:]
+}
@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCloudTrailLog = `{"Records": [
+	{
+		"eventName": "CreateSecurityGroup",
+		"eventTime": "2021-03-04T10:00:00Z",
+		"awsRegion": "eu-central-1",
+		"recipientAccountId": "123456789012",
+		"userIdentity": {"type": "IAMUser", "userName": "creator", "arn": "arn:aws:iam::123456789012:user/creator"},
+		"requestParameters": {"groupName": "web"},
+		"responseElements": {"_return": true, "groupId": "sg-1"}
+	},
+	{
+		"eventName": "DeleteSecurityGroup",
+		"eventTime": "2021-03-05T10:00:00Z",
+		"awsRegion": "eu-central-1",
+		"recipientAccountId": "123456789012",
+		"userIdentity": {"type": "AssumedRole", "arn": "arn:aws:sts::123456789012:assumed-role/cleanup/session"},
+		"requestParameters": {"groupId": "sg-1"},
+		"responseElements": null
+	},
+	{
+		"eventName": "DeleteSecurityGroup",
+		"eventTime": "2021-03-05T10:30:00Z",
+		"awsRegion": "eu-central-1",
+		"recipientAccountId": "123456789012",
+		"userIdentity": {"type": "IAMUser", "userName": "deleter", "arn": "arn:aws:iam::123456789012:user/deleter"},
+		"requestParameters": {"groupName": "legacy"}
+	},
+	{
+		"eventName": "DeleteSecurityGroup",
+		"eventTime": "2021-03-05T11:00:00Z",
+		"awsRegion": "eu-central-1",
+		"recipientAccountId": "123456789012",
+		"errorCode": "Client.DependencyViolation",
+		"userIdentity": {"type": "AssumedRole", "arn": "arn:aws:sts::123456789012:assumed-role/cleanup/session"},
+		"requestParameters": {"groupId": "sg-2"}
+	},
+	{
+		"eventName": "CreateSecurityGroup",
+		"eventTime": "2021-03-04T10:00:00Z",
+		"awsRegion": "us-east-1",
+		"recipientAccountId": "123456789012",
+		"userIdentity": {"type": "Root", "arn": "arn:aws:iam::123456789012:root"},
+		"responseElements": {"groupId": "sg-3"}
+	},
+	{
+		"eventName": "RunInstances",
+		"eventTime": "2021-03-04T10:00:00Z",
+		"awsRegion": "eu-central-1",
+		"recipientAccountId": "123456789012"
+	}
+]}`
+
+func gzipped(t *testing.T, content string) []byte {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	_, err := writer.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func assertTestCloudTrailLogEvents(t *testing.T, events map[string][]LifecycleEvent) {
+	require.Len(t, events, 2)
+	require.Len(t, events["sg-1"], 2)
+	assert.Equal(t, "creator", events["sg-1"][0].Username)
+	assert.Equal(t, string(SECURITYGROUP_DELETED), events["sg-1"][1].EventName)
+	assert.Equal(t, "session", events["sg-1"][1].Username)
+	// only the group name is known, it's resolved like the resource names of LookupEvents
+	require.Len(t, events["legacy"], 1)
+	assert.Equal(t, "deleter", events["legacy"][0].Username)
+}
+
+func TestCloudTrailLogs(t *testing.T) {
+	starttime, err := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
+	require.NoError(t, err)
+	endtime, err := time.Parse(time.RFC3339, "2021-12-31T00:00:00Z")
+	require.NoError(t, err)
+
+	t.Run("Local Directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "AWSLogs", "123456789012", "CloudTrail", "eu-central-1", "2021", "03", "05")
+		require.NoError(t, os.MkdirAll(dir, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "123456789012_CloudTrail_eu-central-1_20210305T1005Z_abc.json.gz"), gzipped(t, testCloudTrailLog), 0o600))
+		// must be skipped because of the region in the name
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "123456789012_CloudTrail_us-east-1_20210305T1005Z_abc.json.gz"), []byte("no json"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("no log file"), 0o600))
+
+		SUT := CloudTrailLogs{location: filepath.Dir(dir), account: "123456789012", region: "eu-central-1"}
+
+		events, err := SUT.SecGrpEvents(starttime, endtime)
+		require.NoError(t, err)
+		assertTestCloudTrailLogEvents(t, events)
+	})
+
+	t.Run("S3", func(t *testing.T) {
+		s3Mock := mocks.NewMockS3(t)
+		key := "AWSLogs/o-abc123/123456789012/CloudTrail/eu-central-1/2021/03/05/123456789012_CloudTrail_eu-central-1_20210305T1005Z_abc.json.gz"
+
+		// only the directories of the account and region for the days of the time window are listed
+		s3Mock.EXPECT().ListObjectsV2(context.TODO(), &s3.ListObjectsV2Input{
+			Bucket: aws.String("trail"),
+			Prefix: aws.String("AWSLogs/o-abc123/123456789012/CloudTrail/eu-central-1/2021/03/04/"),
+		}).Return(&s3.ListObjectsV2Output{
+			IsTruncated:           aws.Bool(true),
+			NextContinuationToken: aws.String("next"),
+		}, nil).Once()
+		s3Mock.EXPECT().ListObjectsV2(context.TODO(), &s3.ListObjectsV2Input{
+			Bucket:            aws.String("trail"),
+			Prefix:            aws.String("AWSLogs/o-abc123/123456789012/CloudTrail/eu-central-1/2021/03/04/"),
+			ContinuationToken: aws.String("next"),
+		}).Return(&s3.ListObjectsV2Output{}, nil).Once()
+		s3Mock.EXPECT().ListObjectsV2(context.TODO(), &s3.ListObjectsV2Input{
+			Bucket: aws.String("trail"),
+			Prefix: aws.String("AWSLogs/o-abc123/123456789012/CloudTrail/eu-central-1/2021/03/05/"),
+		}).Return(&s3.ListObjectsV2Output{
+			Contents: []s3Types.Object{{Key: aws.String(key)}},
+		}, nil).Once()
+		s3Mock.EXPECT().GetObject(context.TODO(), &s3.GetObjectInput{
+			Bucket: aws.String("trail"),
+			Key:    aws.String(key),
+		}).Return(&s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(gzipped(t, testCloudTrailLog)))}, nil).Once()
+
+		SUT := NewFromInterface(mocks.NewMockEc2client(t), mocks.NewMockCloudTrail(t), WithS3(s3Mock))
+		SUT.account = "123456789012"
+		SUT.cfg.Region = "eu-central-1"
+		SUT.SetSecGrpEventSource(SUT.NewCloudTrailLogs("s3://trail/AWSLogs/o-abc123"))
+
+		windowStart, err := time.Parse(time.RFC3339, "2021-03-04T08:00:00Z")
+		require.NoError(t, err)
+		windowEnd, err := time.Parse(time.RFC3339, "2021-03-05T12:00:00Z")
+		require.NoError(t, err)
+
+		secGrps, err := SUT.GetCloudTrailForSecGroups(windowStart, windowEnd)
+		require.NoError(t, err)
+		require.Contains(t, secGrps, "sg-1")
+		assert.Equal(t, "creator", secGrps["sg-1"].Creator)
+		assert.Equal(t, "session", secGrps["sg-1"].Deleter)
+		assert.True(t, secGrps["sg-1"].IsDeleted())
+	})
+
+	t.Run("S3 Other Prefix", func(t *testing.T) {
+		s3Mock := mocks.NewMockS3(t)
+		s3Mock.EXPECT().ListObjectsV2(context.TODO(), &s3.ListObjectsV2Input{
+			Bucket: aws.String("trail"),
+			Prefix: aws.String("custom/"),
+		}).Return(&s3.ListObjectsV2Output{}, nil).Once()
+
+		SUT := CloudTrailLogs{location: "s3://trail/custom", s3: s3Mock, account: "123456789012", region: "eu-central-1"}
+
+		events, err := SUT.SecGrpEvents(starttime, endtime)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("Invalid File", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "trail.json"), []byte("no json"), 0o600))
+
+		SUT := CloudTrailLogs{location: dir}
+
+		_, err := SUT.SecGrpEvents(starttime, endtime)
+		assert.Error(t, err)
+	})
+}
+
+func TestCloudTrailRecordUsername(t *testing.T) {
+	tblTest := map[string]string{
+		"arn:aws:iam::123456789012:user/creator":                 "creator",
+		"arn:aws:sts::123456789012:assumed-role/cleanup/session": "session",
+		"arn:aws:iam::123456789012:root":                         "root",
+	}
+	for arn, expected := range tblTest {
+		t.Run(expected, func(t *testing.T) {
+			record := cloudTrailRecord{}
+			record.UserIdentity.Arn = arn
+			assert.Equal(t, expected, record.username())
+		})
+	}
+}
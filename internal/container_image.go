@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrTypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/steffakasid/eslog"
+)
+
+// BatchDeleteImage and BatchGetImage accept at most 100 images per call.
+const (
+	maxImagesPerDelete = 100
+	maxImagesPerGet    = 100
+)
+
+// ImageIndexMediaTypes are the media types of manifests which reference the images of other platforms.
+var ImageIndexMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// imageIndex is the part of a manifest list or OCI image index which references the platform manifests.
+type imageIndex struct {
+	Manifests []struct {
+		Digest string `json:"digest"`
+	} `json:"manifests"`
+}
+
+// ContainerImageUsage maps an image reference like repository:tag or repository@digest to a description of
+// the resource which uses it.
+type ContainerImageUsage map[string]string
+
+// Add records that the image is used by usedBy. The first recorded usage of an image is kept.
+func (u ContainerImageUsage) Add(image, usedBy string) {
+	if _, exists := u[image]; !exists {
+		u[image] = usedBy
+	}
+}
+
+// ParseImageReference splits an image reference into the repository, the tag and the digest. Images without
+// tag and digest refer to the latest tag.
+func ParseImageReference(image string) (repository, tag, digest string) {
+	repository, digest, _ = strings.Cut(image, "@")
+	// a colon before the last slash separates the port of the registry
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	if tag == "" && digest == "" {
+		tag = "latest"
+	}
+	return repository, tag, digest
+}
+
+// GetRepositories returns all ECR repositories.
+func (a AWS) GetRepositories() ([]ecrTypes.Repository, error) {
+	repositories := []ecrTypes.Repository{}
+	in := &ecr.DescribeRepositoriesInput{}
+	for {
+		out, err := a.ecr.DescribeRepositories(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, out.Repositories...)
+
+		if out.NextToken == nil {
+			return repositories, nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// GetContainerImages returns all images of the repository.
+func (a AWS) GetContainerImages(repositoryName string) ([]ecrTypes.ImageDetail, error) {
+	images := []ecrTypes.ImageDetail{}
+	in := &ecr.DescribeImagesInput{RepositoryName: &repositoryName}
+	for {
+		out, err := a.ecr.DescribeImages(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, out.ImageDetails...)
+
+		if out.NextToken == nil {
+			return images, nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// GetImageIndexManifests returns the digests of the manifests referenced by the image indexes with the given
+// digests.
+func (a AWS) GetImageIndexManifests(repositoryName string, indexDigests []string) ([]string, error) {
+	digests := []string{}
+	for start := 0; start < len(indexDigests); start += maxImagesPerGet {
+		in := &ecr.BatchGetImageInput{RepositoryName: &repositoryName, AcceptedMediaTypes: ImageIndexMediaTypes}
+		for _, digest := range indexDigests[start:min(start+maxImagesPerGet, len(indexDigests))] {
+			in.ImageIds = append(in.ImageIds, ecrTypes.ImageIdentifier{ImageDigest: aws.String(digest)})
+		}
+
+		out, err := a.ecr.BatchGetImage(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		// without the manifest of an index it's unknown which images it references
+		if len(out.Failures) > 0 {
+			failure := out.Failures[0]
+			digest := ""
+			if failure.ImageId != nil {
+				digest = aws.ToString(failure.ImageId.ImageDigest)
+			}
+			return nil, fmt.Errorf("could not get image %s: %s %s", digest, failure.FailureCode, aws.ToString(failure.FailureReason))
+		}
+		for _, image := range out.Images {
+			index := imageIndex{}
+			if err := json.Unmarshal([]byte(aws.ToString(image.ImageManifest)), &index); err != nil {
+				return nil, fmt.Errorf("could not parse manifest of image %s: %w", aws.ToString(image.ImageId.ImageDigest), err)
+			}
+			for _, manifest := range index.Manifests {
+				digests = append(digests, manifest.Digest)
+			}
+		}
+	}
+	return digests, nil
+}
+
+// DeleteContainerImages deletes the images with the given digests including all their tags. ECR has no dry run,
+// so nothing is called if dryrun is set. The digests of the images which could not be deleted are returned
+// together with the reasons as error.
+func (a AWS) DeleteContainerImages(repositoryName string, digests []string, dryrun bool) (failed []string, err error) {
+	eslog.Logger.Debugf("DeleteContainerImages(%s, %v), dryrun: %t", repositoryName, digests, dryrun)
+	failed = []string{}
+	if dryrun {
+		return failed, nil
+	}
+
+	errs := []error{}
+	for start := 0; start < len(digests); start += maxImagesPerDelete {
+		opts := &ecr.BatchDeleteImageInput{RepositoryName: &repositoryName}
+		for _, digest := range digests[start:min(start+maxImagesPerDelete, len(digests))] {
+			opts.ImageIds = append(opts.ImageIds, ecrTypes.ImageIdentifier{ImageDigest: aws.String(digest)})
+		}
+
+		out, err := a.ecr.BatchDeleteImage(context.TODO(), opts)
+		if err != nil {
+			// neither this nor the following batches were deleted
+			failed = append(failed, digests[start:]...)
+			return failed, errors.Join(append(errs, err)...)
+		}
+		for _, failure := range out.Failures {
+			digest := ""
+			if failure.ImageId != nil {
+				digest = aws.ToString(failure.ImageId.ImageDigest)
+			}
+			failed = append(failed, digest)
+			errs = append(errs, fmt.Errorf("could not delete image %s: %s %s", digest, failure.FailureCode, aws.ToString(failure.FailureReason)))
+		}
+	}
+	return failed, errors.Join(errs...)
+}
@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupECRSUT(t *testing.T) (*AWS, *mocks.MockECR) {
+	ecrMock := mocks.NewMockECR(t)
+	SUT := NewFromInterface(mocks.NewMockEc2client(t), mocks.NewMockCloudTrail(t), WithECR(ecrMock))
+	return SUT, ecrMock
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image      string
+		repository string
+		tag        string
+		digest     string
+	}{
+		{image: "nginx", repository: "nginx", tag: "latest"},
+		{image: "123456789012.dkr.ecr.eu-central-1.amazonaws.com/app:1.0", repository: "123456789012.dkr.ecr.eu-central-1.amazonaws.com/app", tag: "1.0"},
+		{image: "123456789012.dkr.ecr.eu-central-1.amazonaws.com/app@sha256:abc", repository: "123456789012.dkr.ecr.eu-central-1.amazonaws.com/app", digest: "sha256:abc"},
+		{image: "registry:5000/team/app:1.0@sha256:abc", repository: "registry:5000/team/app", tag: "1.0", digest: "sha256:abc"},
+		{image: "registry:5000/team/app", repository: "registry:5000/team/app", tag: "latest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			repository, tag, digest := ParseImageReference(tt.image)
+			assert.Equal(t, tt.repository, repository)
+			assert.Equal(t, tt.tag, tag)
+			assert.Equal(t, tt.digest, digest)
+		})
+	}
+}
+
+func TestGetRepositories(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, ecrMock := setupECRSUT(t)
+		ecrMock.EXPECT().DescribeRepositories(context.TODO(), &ecr.DescribeRepositoriesInput{}).Return(&ecr.DescribeRepositoriesOutput{
+			Repositories: []types.Repository{{RepositoryName: aws.String("app")}},
+			NextToken:    aws.String("next"),
+		}, nil).Once()
+		ecrMock.EXPECT().DescribeRepositories(context.TODO(), &ecr.DescribeRepositoriesInput{NextToken: aws.String("next")}).Return(&ecr.DescribeRepositoriesOutput{
+			Repositories: []types.Repository{{RepositoryName: aws.String("web")}},
+		}, nil).Once()
+
+		repositories, err := SUT.GetRepositories()
+		require.NoError(t, err)
+		require.Len(t, repositories, 2)
+		assert.Equal(t, "web", *repositories[1].RepositoryName)
+	})
+
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, ecrMock := setupECRSUT(t)
+		ecrMock.EXPECT().DescribeRepositories(context.TODO(), &ecr.DescribeRepositoriesInput{}).Return(nil, errors.New("Something went wrong")).Once()
+
+		_, err := SUT.GetRepositories()
+		require.EqualError(t, err, "Something went wrong")
+	})
+}
+
+func TestGetContainerImages(t *testing.T) {
+	SUT, ecrMock := setupECRSUT(t)
+	ecrMock.EXPECT().DescribeImages(context.TODO(), &ecr.DescribeImagesInput{RepositoryName: aws.String("app")}).Return(&ecr.DescribeImagesOutput{
+		ImageDetails: []types.ImageDetail{{ImageDigest: aws.String("sha256:1")}},
+		NextToken:    aws.String("next"),
+	}, nil).Once()
+	ecrMock.EXPECT().DescribeImages(context.TODO(), &ecr.DescribeImagesInput{RepositoryName: aws.String("app"), NextToken: aws.String("next")}).Return(&ecr.DescribeImagesOutput{
+		ImageDetails: []types.ImageDetail{{ImageDigest: aws.String("sha256:2")}},
+	}, nil).Once()
+
+	images, err := SUT.GetContainerImages("app")
+	require.NoError(t, err)
+	assert.Len(t, images, 2)
+}
+
+func TestGetImageIndexManifests(t *testing.T) {
+	expectedInput := &ecr.BatchGetImageInput{
+		RepositoryName:     aws.String("app"),
+		ImageIds:           []types.ImageIdentifier{{ImageDigest: aws.String("sha256:index")}},
+		AcceptedMediaTypes: ImageIndexMediaTypes,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		SUT, ecrMock := setupECRSUT(t)
+		ecrMock.EXPECT().BatchGetImage(context.TODO(), expectedInput).Return(&ecr.BatchGetImageOutput{
+			Images: []types.Image{{
+				ImageId:       &types.ImageIdentifier{ImageDigest: aws.String("sha256:index")},
+				ImageManifest: aws.String(`{"schemaVersion":2,"manifests":[{"digest":"sha256:amd64"},{"digest":"sha256:arm64"}]}`),
+			}},
+		}, nil).Once()
+
+		digests, err := SUT.GetImageIndexManifests("app", []string{"sha256:index"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"sha256:amd64", "sha256:arm64"}, digests)
+	})
+
+	t.Run("Failures", func(t *testing.T) {
+		SUT, ecrMock := setupECRSUT(t)
+		ecrMock.EXPECT().BatchGetImage(context.TODO(), expectedInput).Return(&ecr.BatchGetImageOutput{
+			Failures: []types.ImageFailure{{ImageId: &types.ImageIdentifier{ImageDigest: aws.String("sha256:index")}, FailureCode: types.ImageFailureCodeImageNotFound, FailureReason: aws.String("not found")}},
+		}, nil).Once()
+
+		_, err := SUT.GetImageIndexManifests("app", []string{"sha256:index"})
+		require.EqualError(t, err, fmt.Sprintf("could not get image sha256:index: %s not found", types.ImageFailureCodeImageNotFound))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		SUT, ecrMock := setupECRSUT(t)
+		ecrMock.EXPECT().BatchGetImage(context.TODO(), expectedInput).Return(nil, errors.New("some error")).Once()
+
+		_, err := SUT.GetImageIndexManifests("app", []string{"sha256:index"})
+		require.EqualError(t, err, "some error")
+	})
+}
+
+func TestDeleteContainerImages(t *testing.T) {
+	t.Run("In Batches", func(t *testing.T) {
+		SUT, ecrMock := setupECRSUT(t)
+
+		digests := []string{}
+		for i := range 150 {
+			digests = append(digests, fmt.Sprintf("sha256:%d", i))
+		}
+		ecrMock.EXPECT().BatchDeleteImage(context.TODO(), mock.MatchedBy(func(in *ecr.BatchDeleteImageInput) bool {
+			return len(in.ImageIds) == 100 && *in.ImageIds[0].ImageDigest == "sha256:0"
+		})).Return(&ecr.BatchDeleteImageOutput{}, nil).Once()
+		ecrMock.EXPECT().BatchDeleteImage(context.TODO(), mock.MatchedBy(func(in *ecr.BatchDeleteImageInput) bool {
+			return len(in.ImageIds) == 50 && *in.ImageIds[0].ImageDigest == "sha256:100"
+		})).Return(&ecr.BatchDeleteImageOutput{}, nil).Once()
+
+		failed, err := SUT.DeleteContainerImages("app", digests, false)
+		require.NoError(t, err)
+		assert.Empty(t, failed)
+	})
+
+	t.Run("Failures", func(t *testing.T) {
+		SUT, ecrMock := setupECRSUT(t)
+		ecrMock.EXPECT().BatchDeleteImage(context.TODO(), &ecr.BatchDeleteImageInput{
+			RepositoryName: aws.String("app"),
+			ImageIds:       []types.ImageIdentifier{{ImageDigest: aws.String("sha256:1")}},
+		}).Return(&ecr.BatchDeleteImageOutput{
+			Failures: []types.ImageFailure{{ImageId: &types.ImageIdentifier{ImageDigest: aws.String("sha256:1")}, FailureCode: types.ImageFailureCodeImageReferencedByManifestList, FailureReason: aws.String("referenced")}},
+		}, nil).Once()
+
+		failed, err := SUT.DeleteContainerImages("app", []string{"sha256:1"}, false)
+		assert.Equal(t, []string{"sha256:1"}, failed)
+		require.EqualError(t, err, fmt.Sprintf("could not delete image sha256:1: %s referenced", types.ImageFailureCodeImageReferencedByManifestList))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		SUT, ecrMock := setupECRSUT(t)
+
+		digests := []string{}
+		for i := range 150 {
+			digests = append(digests, fmt.Sprintf("sha256:%d", i))
+		}
+		ecrMock.EXPECT().BatchDeleteImage(context.TODO(), mock.MatchedBy(func(in *ecr.BatchDeleteImageInput) bool {
+			return *in.ImageIds[0].ImageDigest == "sha256:0"
+		})).Return(&ecr.BatchDeleteImageOutput{}, nil).Once()
+		ecrMock.EXPECT().BatchDeleteImage(context.TODO(), mock.MatchedBy(func(in *ecr.BatchDeleteImageInput) bool {
+			return *in.ImageIds[0].ImageDigest == "sha256:100"
+		})).Return(nil, errors.New("some error")).Once()
+
+		failed, err := SUT.DeleteContainerImages("app", digests, false)
+		require.EqualError(t, err, "some error")
+		assert.Equal(t, digests[100:], failed)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		SUT, ecrMock := setupECRSUT(t)
+
+		failed, err := SUT.DeleteContainerImages("app", []string{"sha256:1"}, true)
+		require.NoError(t, err)
+		assert.Empty(t, failed)
+		ecrMock.AssertNotCalled(t, "BatchDeleteImage", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,180 @@
+package ecrclean
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/steffakasid/awsclean/internal"
+	eslog "github.com/steffakasid/eslog"
+)
+
+// Image adds the repository URI and the reason why an image is kept.
+type Image struct {
+	types.ImageDetail
+	RepositoryUri string
+	UsedBy        string
+}
+
+type ECRClean struct {
+	awsClient    *internal.AWS
+	olderthen    time.Duration
+	keepNewest   int
+	dryrun       bool
+	onlyUnused   bool
+	usedImages   []Image
+	unusedImages []Image
+}
+
+func NewInstance(awsClient *internal.AWS, olderthen time.Duration, keepNewest int, dryrun bool, onlyUnused bool) *ECRClean {
+	return &ECRClean{
+		awsClient:    awsClient,
+		olderthen:    olderthen,
+		keepNewest:   keepNewest,
+		dryrun:       dryrun,
+		onlyUnused:   onlyUnused,
+		usedImages:   []Image{},
+		unusedImages: []Image{},
+	}
+}
+
+// GetImages fetches the images of all repositories and sorts them into used and unused ones. An image is used
+// if its tag or digest is referenced by an ECS service or running task or if it's one of the keepNewest newest
+// tagged images of its repository. Untagged images are also used if they are referenced by an image index
+// which is used or pushed after olderthen, as deleting them would break the multi-arch image.
+func (e *ECRClean) GetImages() error {
+	repositories, err := e.awsClient.GetRepositories()
+	if err != nil {
+		return fmt.Errorf("could not get repositories: %w", err)
+	}
+	usedImages, err := e.awsClient.GetContainerImagesOfECS()
+	if err != nil {
+		return fmt.Errorf("could not get images used by ECS: %w", err)
+	}
+
+	references := map[string]string{}
+	for image, usedBy := range usedImages {
+		repository, tag, digest := internal.ParseImageReference(image)
+		if digest != "" {
+			references[repository+"@"+digest] = usedBy
+		} else {
+			references[repository+":"+tag] = usedBy
+		}
+	}
+
+	olderThenDate := time.Now().Add(e.olderthen * -1)
+	for _, repository := range repositories {
+		repositoryUri := aws.ToString(repository.RepositoryUri)
+		images, err := e.awsClient.GetContainerImages(aws.ToString(repository.RepositoryName))
+		if err != nil {
+			return fmt.Errorf("could not get images of %s: %w", aws.ToString(repository.RepositoryName), err)
+		}
+
+		// newest images first
+		slices.SortFunc(images, func(a, b types.ImageDetail) int {
+			return aws.ToTime(b.ImagePushedAt).Compare(aws.ToTime(a.ImagePushedAt))
+		})
+
+		tagged := 0
+		repositoryImages := []Image{}
+		retainedIndexes := []string{}
+		for _, image := range images {
+			img := Image{ImageDetail: image, RepositoryUri: repositoryUri}
+			img.UsedBy = references[repositoryUri+"@"+aws.ToString(image.ImageDigest)]
+			for _, tag := range image.ImageTags {
+				if img.UsedBy == "" {
+					img.UsedBy = references[repositoryUri+":"+tag]
+				}
+			}
+
+			if len(image.ImageTags) > 0 {
+				tagged++
+				if img.UsedBy == "" && tagged <= e.keepNewest {
+					img.UsedBy = fmt.Sprintf("one of the %d newest tagged images", e.keepNewest)
+				}
+			}
+
+			isImageIndex := slices.Contains(internal.ImageIndexMediaTypes, aws.ToString(image.ImageManifestMediaType))
+			if isImageIndex && (img.UsedBy != "" || image.ImagePushedAt == nil || !image.ImagePushedAt.Before(olderThenDate)) {
+				retainedIndexes = append(retainedIndexes, aws.ToString(image.ImageDigest))
+			}
+			repositoryImages = append(repositoryImages, img)
+		}
+
+		manifests := []string{}
+		if len(retainedIndexes) > 0 {
+			manifests, err = e.awsClient.GetImageIndexManifests(aws.ToString(repository.RepositoryName), retainedIndexes)
+			if err != nil {
+				return fmt.Errorf("could not get image indexes of %s: %w", aws.ToString(repository.RepositoryName), err)
+			}
+		}
+
+		for _, img := range repositoryImages {
+			if img.UsedBy == "" && slices.Contains(manifests, aws.ToString(img.ImageDigest)) {
+				img.UsedBy = "part of a multi-arch image"
+			}
+
+			if img.UsedBy != "" {
+				e.usedImages = append(e.usedImages, img)
+			} else {
+				e.unusedImages = append(e.unusedImages, img)
+			}
+		}
+	}
+	return nil
+}
+
+func (e ECRClean) GetAllImages() []Image {
+	all := []Image{}
+
+	all = append(all, e.unusedImages...)
+	if !e.onlyUnused {
+		all = append(all, e.usedImages...)
+	}
+
+	return all
+}
+
+// DeleteUnusedImages deletes the unused images which were pushed before olderthen.
+func (e *ECRClean) DeleteUnusedImages() error {
+	err := e.GetImages()
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	skipped := 0
+
+	olderThenDate := time.Now().Add(e.olderthen * -1)
+	eslog.Logger.Debugf("OlderThenDate %v", olderThenDate)
+
+	// repository name to the digests to delete, BatchDeleteImage deletes many images per call
+	digests := map[string][]string{}
+	for _, image := range e.unusedImages {
+		if image.ImagePushedAt == nil || !image.ImagePushedAt.Before(olderThenDate) {
+			skipped++
+			continue
+		}
+		eslog.Logger.Infof("Delete image %s@%s %v (dry run: %t)", aws.ToString(image.RepositoryName), aws.ToString(image.ImageDigest), image.ImageTags, e.dryrun)
+		digests[aws.ToString(image.RepositoryName)] = append(digests[aws.ToString(image.RepositoryName)], aws.ToString(image.ImageDigest))
+	}
+
+	for repositoryName, repositoryDigests := range digests {
+		repositoryDeleted, repositorySkipped := e.deleteImages(repositoryName, repositoryDigests)
+		deleted += repositoryDeleted
+		skipped += repositorySkipped
+	}
+
+	eslog.Logger.Infof("Deleted %d, Skipped %d images", deleted, skipped)
+	return nil
+}
+
+// deleteImages deletes the images with the given digests of one repository. Images which could not be
+// deleted are counted as skipped.
+func (e ECRClean) deleteImages(repositoryName string, digests []string) (deleted int, skipped int) {
+	failed, err := e.awsClient.DeleteContainerImages(repositoryName, digests, e.dryrun)
+	eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeleteContainerImages(): %s")
+	return len(digests) - len(failed), len(failed)
+}
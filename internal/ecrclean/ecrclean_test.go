@@ -0,0 +1,207 @@
+package ecrclean
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+const repositoryUri = "123456789012.dkr.ecr.eu-central-1.amazonaws.com/app"
+
+func setupSUT(t *testing.T, olderthen string, keepNewest int, dryrun, onlyUnused bool) (*ECRClean, *mocks.MockECR, *mocks.MockECS) {
+	olderthenDuration, err := str2duration.ParseDuration(olderthen)
+	require.NoError(t, err)
+
+	ecrMock := mocks.NewMockECR(t)
+	ecsMock := mocks.NewMockECS(t)
+	awsClient := internal.NewFromInterface(mocks.NewMockEc2client(t), mocks.NewMockCloudTrail(t), internal.WithECR(ecrMock), internal.WithECS(ecsMock))
+	return NewInstance(awsClient, olderthenDuration, keepNewest, dryrun, onlyUnused), ecrMock, ecsMock
+}
+
+func image(digest string, pushedDaysAgo int, tags ...string) types.ImageDetail {
+	return types.ImageDetail{
+		RepositoryName:         aws.String("app"),
+		ImageDigest:            aws.String(digest),
+		ImageTags:              tags,
+		ImagePushedAt:          aws.Time(time.Now().Add(time.Duration(-pushedDaysAgo) * 24 * time.Hour)),
+		ImageManifestMediaType: aws.String("application/vnd.docker.distribution.manifest.v2+json"),
+	}
+}
+
+func imageIndex(digest string, pushedDaysAgo int, tags ...string) types.ImageDetail {
+	index := image(digest, pushedDaysAgo, tags...)
+	index.ImageManifestMediaType = aws.String("application/vnd.oci.image.index.v1+json")
+	return index
+}
+
+func mockImages(ecrMock *mocks.MockECR, images ...types.ImageDetail) {
+	ecrMock.EXPECT().DescribeRepositories(context.TODO(), &ecr.DescribeRepositoriesInput{}).Return(&ecr.DescribeRepositoriesOutput{
+		Repositories: []types.Repository{{RepositoryName: aws.String("app"), RepositoryUri: aws.String(repositoryUri)}},
+	}, nil).Once()
+	ecrMock.EXPECT().DescribeImages(context.TODO(), &ecr.DescribeImagesInput{RepositoryName: aws.String("app")}).Return(&ecr.DescribeImagesOutput{
+		ImageDetails: images,
+	}, nil).Once()
+}
+
+// mockECS expects one cluster with one service using the given images and one running task started with the
+// given digest.
+func mockECS(ecsMock *mocks.MockECS, taskDigest string, taskDefImages ...string) {
+	containerDefs := []ecsTypes.ContainerDefinition{}
+	for _, image := range taskDefImages {
+		containerDefs = append(containerDefs, ecsTypes.ContainerDefinition{Image: aws.String(image)})
+	}
+
+	ecsMock.EXPECT().ListClusters(context.TODO(), &ecs.ListClustersInput{}).Return(&ecs.ListClustersOutput{ClusterArns: []string{"cluster/prod"}}, nil).Once()
+	ecsMock.EXPECT().ListServices(context.TODO(), mock.Anything).Return(&ecs.ListServicesOutput{ServiceArns: []string{"service/prod/web"}}, nil).Once()
+	ecsMock.EXPECT().DescribeServices(context.TODO(), mock.Anything).Return(&ecs.DescribeServicesOutput{
+		Services: []ecsTypes.Service{{ServiceName: aws.String("web"), TaskDefinition: aws.String("task-definition/web:1")}},
+	}, nil).Once()
+	ecsMock.EXPECT().ListTasks(context.TODO(), mock.Anything).Return(&ecs.ListTasksOutput{TaskArns: []string{"task/prod/abc"}}, nil).Once()
+	ecsMock.EXPECT().DescribeTasks(context.TODO(), mock.Anything).Return(&ecs.DescribeTasksOutput{
+		Tasks: []ecsTypes.Task{{
+			TaskArn:           aws.String("task/prod/abc"),
+			TaskDefinitionArn: aws.String("task-definition/web:1"),
+			Containers:        []ecsTypes.Container{{Image: aws.String(repositoryUri + ":moved"), ImageDigest: aws.String(taskDigest)}},
+		}},
+	}, nil).Once()
+	ecsMock.EXPECT().DescribeTaskDefinition(context.TODO(), mock.Anything).Return(&ecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &ecsTypes.TaskDefinition{ContainerDefinitions: containerDefs},
+	}, nil).Once()
+}
+
+func TestGetImages(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, ecrMock, ecsMock := setupSUT(t, "7d", 1, false, false)
+
+		mockImages(ecrMock,
+			image("sha256:newest", 1, "3.0"),
+			image("sha256:service", 20, "2.0"),
+			image("sha256:task", 30),
+			image("sha256:old", 40, "1.0"),
+			image("sha256:untagged", 50),
+		)
+		mockECS(ecsMock, "sha256:task", repositoryUri+":2.0")
+
+		err := SUT.GetImages()
+		require.NoError(t, err)
+
+		require.Len(t, SUT.usedImages, 3)
+		assert.Equal(t, "one of the 1 newest tagged images", SUT.usedImages[0].UsedBy)
+		assert.Equal(t, "task definition web:1 of service web in cluster prod", SUT.usedImages[1].UsedBy)
+		assert.Equal(t, "task abc in cluster prod", SUT.usedImages[2].UsedBy)
+		require.Len(t, SUT.unusedImages, 2)
+		assert.Equal(t, "sha256:old", *SUT.unusedImages[0].ImageDigest)
+		assert.Equal(t, repositoryUri, SUT.unusedImages[0].RepositoryUri)
+		assert.Equal(t, "sha256:untagged", *SUT.unusedImages[1].ImageDigest)
+		assert.Len(t, SUT.GetAllImages(), 5)
+	})
+
+	t.Run("Multi-Arch Image", func(t *testing.T) {
+		SUT, ecrMock, ecsMock := setupSUT(t, "7d", 1, false, false)
+
+		index := imageIndex("sha256:index", 10, "2.0")
+		oldIndex := imageIndex("sha256:oldindex", 40, "1.0")
+		mockImages(ecrMock, index, image("sha256:amd64", 11), image("sha256:arm64", 12), oldIndex, image("sha256:oldamd64", 41), image("sha256:untagged", 50))
+		mockECS(ecsMock, "sha256:other")
+		ecrMock.EXPECT().BatchGetImage(context.TODO(), &ecr.BatchGetImageInput{
+			RepositoryName:     aws.String("app"),
+			ImageIds:           []types.ImageIdentifier{{ImageDigest: aws.String("sha256:index")}},
+			AcceptedMediaTypes: internal.ImageIndexMediaTypes,
+		}).Return(&ecr.BatchGetImageOutput{
+			Images: []types.Image{{
+				ImageId:       &types.ImageIdentifier{ImageDigest: aws.String("sha256:index")},
+				ImageManifest: aws.String(`{"manifests":[{"digest":"sha256:amd64"},{"digest":"sha256:arm64"}]}`),
+			}},
+		}, nil).Once()
+
+		err := SUT.GetImages()
+		require.NoError(t, err)
+		require.Len(t, SUT.usedImages, 3)
+		assert.Equal(t, "sha256:amd64", *SUT.usedImages[1].ImageDigest)
+		assert.Equal(t, "part of a multi-arch image", SUT.usedImages[1].UsedBy)
+		require.Len(t, SUT.unusedImages, 3)
+		assert.Equal(t, "sha256:oldindex", *SUT.unusedImages[0].ImageDigest)
+		assert.Equal(t, "sha256:oldamd64", *SUT.unusedImages[1].ImageDigest)
+		assert.Equal(t, "sha256:untagged", *SUT.unusedImages[2].ImageDigest)
+	})
+
+	t.Run("Error BatchGetImage", func(t *testing.T) {
+		SUT, ecrMock, ecsMock := setupSUT(t, "7d", 1, false, false)
+
+		mockImages(ecrMock, imageIndex("sha256:index", 1, "latest"), image("sha256:amd64", 1))
+		mockECS(ecsMock, "sha256:other")
+		ecrMock.EXPECT().BatchGetImage(context.TODO(), mock.Anything).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.GetImages()
+		require.EqualError(t, err, "could not get image indexes of app: some error")
+	})
+
+	t.Run("Error DescribeRepositories", func(t *testing.T) {
+		SUT, ecrMock, _ := setupSUT(t, "7d", 1, false, false)
+
+		ecrMock.EXPECT().DescribeRepositories(context.TODO(), &ecr.DescribeRepositoriesInput{}).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.GetImages()
+		require.EqualError(t, err, "could not get repositories: some error")
+	})
+}
+
+func TestDeleteUnusedImages(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, ecrMock, ecsMock := setupSUT(t, "7d", 1, false, true)
+
+		mockImages(ecrMock,
+			image("sha256:newest", 10, "2.0"),
+			image("sha256:recent", 3),
+			image("sha256:old", 40, "1.0"),
+			image("sha256:untagged", 50),
+		)
+		mockECS(ecsMock, "sha256:other")
+		ecrMock.EXPECT().BatchDeleteImage(context.TODO(), &ecr.BatchDeleteImageInput{
+			RepositoryName: aws.String("app"),
+			ImageIds:       []types.ImageIdentifier{{ImageDigest: aws.String("sha256:old")}, {ImageDigest: aws.String("sha256:untagged")}},
+		}).Return(&ecr.BatchDeleteImageOutput{}, nil).Once()
+
+		err := SUT.DeleteUnusedImages()
+		require.NoError(t, err)
+	})
+
+	t.Run("Partial Failure", func(t *testing.T) {
+		SUT, ecrMock, _ := setupSUT(t, "7d", 0, false, true)
+
+		ecrMock.EXPECT().BatchDeleteImage(context.TODO(), &ecr.BatchDeleteImageInput{
+			RepositoryName: aws.String("app"),
+			ImageIds:       []types.ImageIdentifier{{ImageDigest: aws.String("sha256:1")}, {ImageDigest: aws.String("sha256:2")}, {ImageDigest: aws.String("sha256:3")}},
+		}).Return(&ecr.BatchDeleteImageOutput{
+			Failures: []types.ImageFailure{{ImageId: &types.ImageIdentifier{ImageDigest: aws.String("sha256:2")}, FailureCode: types.ImageFailureCodeImageReferencedByManifestList}},
+		}, nil).Once()
+
+		deleted, skipped := SUT.deleteImages("app", []string{"sha256:1", "sha256:2", "sha256:3"})
+		assert.Equal(t, 2, deleted)
+		assert.Equal(t, 1, skipped)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		SUT, ecrMock, ecsMock := setupSUT(t, "7d", 0, true, true)
+
+		mockImages(ecrMock, image("sha256:old", 40, "1.0"))
+		mockECS(ecsMock, "sha256:other")
+
+		err := SUT.DeleteUnusedImages()
+		require.NoError(t, err)
+		ecrMock.AssertNotCalled(t, "BatchDeleteImage", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+)
+
+// DescribeServices accepts at most 10 and DescribeTasks at most 100 ARNs per call.
+const (
+	maxServicesPerDescribe = 10
+	maxTasksPerDescribe    = 100
+)
+
+// GetContainerImagesOfECS returns the images used by ECS. These are the images of the task definitions of all
+// services and running tasks and the digests the running tasks were started with.
+func (a AWS) GetContainerImagesOfECS() (ContainerImageUsage, error) {
+	usedImages := ContainerImageUsage{}
+	// task definition ARN to the service or task which uses it
+	taskDefs := map[string]string{}
+
+	clusterArns, err := a.listClusters()
+	if err != nil {
+		return nil, err
+	}
+	for _, clusterArn := range clusterArns {
+		err := a.addTaskDefsOfServices(taskDefs, clusterArn)
+		if err != nil {
+			return nil, err
+		}
+		err = a.addImagesOfTasks(usedImages, taskDefs, clusterArn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for taskDefArn, usedBy := range taskDefs {
+		out, err := a.ecs.DescribeTaskDefinition(context.TODO(), &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(taskDefArn)})
+		if err != nil {
+			return nil, fmt.Errorf("could not get task definition %s: %w", taskDefArn, err)
+		}
+		for _, container := range out.TaskDefinition.ContainerDefinitions {
+			if container.Image != nil {
+				usedImages.Add(*container.Image, fmt.Sprintf("task definition %s of %s", arnName(taskDefArn), usedBy))
+			}
+		}
+	}
+	return usedImages, nil
+}
+
+func (a AWS) listClusters() ([]string, error) {
+	clusterArns := []string{}
+	in := &ecs.ListClustersInput{}
+	for {
+		out, err := a.ecs.ListClusters(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		clusterArns = append(clusterArns, out.ClusterArns...)
+
+		if out.NextToken == nil {
+			return clusterArns, nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// addTaskDefsOfServices adds the task definitions of all deployments of the services in the cluster. During
+// a deployment the old and the new task definition are in use.
+func (a AWS) addTaskDefsOfServices(taskDefs map[string]string, clusterArn string) error {
+	serviceArns := []string{}
+	in := &ecs.ListServicesInput{Cluster: &clusterArn}
+	for {
+		out, err := a.ecs.ListServices(context.TODO(), in)
+		if err != nil {
+			return err
+		}
+		serviceArns = append(serviceArns, out.ServiceArns...)
+
+		if out.NextToken == nil {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+
+	for start := 0; start < len(serviceArns); start += maxServicesPerDescribe {
+		out, err := a.ecs.DescribeServices(context.TODO(), &ecs.DescribeServicesInput{
+			Cluster:  &clusterArn,
+			Services: serviceArns[start:min(start+maxServicesPerDescribe, len(serviceArns))],
+		})
+		if err != nil {
+			return err
+		}
+		for _, service := range out.Services {
+			usedBy := fmt.Sprintf("service %s in cluster %s", aws.ToString(service.ServiceName), arnName(clusterArn))
+			if service.TaskDefinition != nil {
+				taskDefs[*service.TaskDefinition] = usedBy
+			}
+			for _, deployment := range service.Deployments {
+				if deployment.TaskDefinition != nil {
+					taskDefs[*deployment.TaskDefinition] = usedBy
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addImagesOfTasks adds the task definitions of the running tasks in the cluster and the digests of the images
+// the containers were started with. The digest is used even if the tag was moved to another image since.
+func (a AWS) addImagesOfTasks(usedImages ContainerImageUsage, taskDefs map[string]string, clusterArn string) error {
+	taskArns := []string{}
+	in := &ecs.ListTasksInput{Cluster: &clusterArn}
+	for {
+		out, err := a.ecs.ListTasks(context.TODO(), in)
+		if err != nil {
+			return err
+		}
+		taskArns = append(taskArns, out.TaskArns...)
+
+		if out.NextToken == nil {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+
+	for start := 0; start < len(taskArns); start += maxTasksPerDescribe {
+		out, err := a.ecs.DescribeTasks(context.TODO(), &ecs.DescribeTasksInput{
+			Cluster: &clusterArn,
+			Tasks:   taskArns[start:min(start+maxTasksPerDescribe, len(taskArns))],
+		})
+		if err != nil {
+			return err
+		}
+		for _, task := range out.Tasks {
+			usedBy := fmt.Sprintf("task %s in cluster %s", arnName(aws.ToString(task.TaskArn)), arnName(clusterArn))
+			if _, exists := taskDefs[aws.ToString(task.TaskDefinitionArn)]; !exists && task.TaskDefinitionArn != nil {
+				taskDefs[*task.TaskDefinitionArn] = usedBy
+			}
+			for _, container := range task.Containers {
+				if container.Image != nil && container.ImageDigest != nil {
+					repository, _, _ := ParseImageReference(*container.Image)
+					usedImages.Add(fmt.Sprintf("%s@%s", repository, *container.ImageDigest), usedBy)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// arnName returns the last part of the ARN, e.g. the family and revision of a task definition or the ID of a task.
+func arnName(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupECSSUT(t *testing.T) (*AWS, *mocks.MockECS) {
+	ecsMock := mocks.NewMockECS(t)
+	SUT := NewFromInterface(mocks.NewMockEc2client(t), mocks.NewMockCloudTrail(t), WithECS(ecsMock))
+	return SUT, ecsMock
+}
+
+func TestGetContainerImagesOfECS(t *testing.T) {
+	const (
+		clusterArn = "arn:aws:ecs:eu-central-1:123456789012:cluster/prod"
+		repository = "123456789012.dkr.ecr.eu-central-1.amazonaws.com/app"
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		SUT, ecsMock := setupECSSUT(t)
+		ecsMock.EXPECT().ListClusters(context.TODO(), &ecs.ListClustersInput{}).Return(&ecs.ListClustersOutput{ClusterArns: []string{clusterArn}}, nil).Once()
+		ecsMock.EXPECT().ListServices(context.TODO(), &ecs.ListServicesInput{Cluster: aws.String(clusterArn)}).Return(&ecs.ListServicesOutput{
+			ServiceArns: []string{"arn:aws:ecs:eu-central-1:123456789012:service/prod/web"},
+		}, nil).Once()
+		ecsMock.EXPECT().DescribeServices(context.TODO(), &ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterArn),
+			Services: []string{"arn:aws:ecs:eu-central-1:123456789012:service/prod/web"},
+		}).Return(&ecs.DescribeServicesOutput{
+			Services: []types.Service{{
+				ServiceName:    aws.String("web"),
+				TaskDefinition: aws.String("arn:aws:ecs:eu-central-1:123456789012:task-definition/web:2"),
+				Deployments: []types.Deployment{
+					{TaskDefinition: aws.String("arn:aws:ecs:eu-central-1:123456789012:task-definition/web:2")},
+					{TaskDefinition: aws.String("arn:aws:ecs:eu-central-1:123456789012:task-definition/web:1")},
+				},
+			}},
+		}, nil).Once()
+		ecsMock.EXPECT().ListTasks(context.TODO(), &ecs.ListTasksInput{Cluster: aws.String(clusterArn)}).Return(&ecs.ListTasksOutput{
+			TaskArns: []string{"arn:aws:ecs:eu-central-1:123456789012:task/prod/abc"},
+		}, nil).Once()
+		ecsMock.EXPECT().DescribeTasks(context.TODO(), &ecs.DescribeTasksInput{
+			Cluster: aws.String(clusterArn),
+			Tasks:   []string{"arn:aws:ecs:eu-central-1:123456789012:task/prod/abc"},
+		}).Return(&ecs.DescribeTasksOutput{
+			Tasks: []types.Task{{
+				TaskArn:           aws.String("arn:aws:ecs:eu-central-1:123456789012:task/prod/abc"),
+				TaskDefinitionArn: aws.String("arn:aws:ecs:eu-central-1:123456789012:task-definition/web:2"),
+				Containers:        []types.Container{{Image: aws.String(repository + ":2.0"), ImageDigest: aws.String("sha256:2")}},
+			}},
+		}, nil).Once()
+		ecsMock.EXPECT().DescribeTaskDefinition(context.TODO(), &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("arn:aws:ecs:eu-central-1:123456789012:task-definition/web:2")}).Return(&ecs.DescribeTaskDefinitionOutput{
+			TaskDefinition: &types.TaskDefinition{ContainerDefinitions: []types.ContainerDefinition{{Image: aws.String(repository + ":2.0")}}},
+		}, nil).Once()
+		ecsMock.EXPECT().DescribeTaskDefinition(context.TODO(), &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("arn:aws:ecs:eu-central-1:123456789012:task-definition/web:1")}).Return(&ecs.DescribeTaskDefinitionOutput{
+			TaskDefinition: &types.TaskDefinition{ContainerDefinitions: []types.ContainerDefinition{{Image: aws.String(repository + ":1.0")}}},
+		}, nil).Once()
+
+		usedImages, err := SUT.GetContainerImagesOfECS()
+		require.NoError(t, err)
+		assert.Equal(t, ContainerImageUsage{
+			repository + ":2.0":       "task definition web:2 of service web in cluster prod",
+			repository + ":1.0":       "task definition web:1 of service web in cluster prod",
+			repository + "@sha256:2": "task abc in cluster prod",
+		}, usedImages)
+	})
+
+	t.Run("Error ListClusters", func(t *testing.T) {
+		SUT, ecsMock := setupECSSUT(t)
+		ecsMock.EXPECT().ListClusters(context.TODO(), &ecs.ListClustersInput{}).Return(nil, errors.New("Something went wrong")).Once()
+
+		_, err := SUT.GetContainerImagesOfECS()
+		require.EqualError(t, err, "Something went wrong")
+	})
+}
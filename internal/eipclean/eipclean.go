@@ -0,0 +1,127 @@
+package eipclean
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/steffakasid/awsclean/internal"
+	eslog "github.com/steffakasid/eslog"
+)
+
+// Address adds the allocation information from CloudTrail as AWS doesn't return when an address was allocated.
+type Address struct {
+	types.Address
+	AllocationTime *time.Time
+	Allocator      string
+}
+
+type EIPClean struct {
+	awsClient       *internal.AWS
+	olderthen       time.Duration
+	dryrun          bool
+	onlyUnused      bool
+	lookupStart     time.Time
+	usedAddresses   []Address
+	unusedAddresses []Address
+}
+
+func NewInstance(awsClient *internal.AWS, olderthen time.Duration, dryrun bool, onlyUnused bool) *EIPClean {
+	return &EIPClean{
+		awsClient:       awsClient,
+		olderthen:       olderthen,
+		dryrun:          dryrun,
+		onlyUnused:      onlyUnused,
+		usedAddresses:   []Address{},
+		unusedAddresses: []Address{},
+	}
+}
+
+// GetAddresses fetches all Elastic IP addresses and sorts them into used and unused ones. An address is unused
+// if it's not associated with an instance or network interface. The allocation time is looked up in CloudTrail
+// for the past olderthen, at most 90 days.
+func (e *EIPClean) GetAddresses() error {
+	addresses, err := e.awsClient.DescribeAddresses()
+	if err != nil {
+		return fmt.Errorf("could not get Elastic IPs: %w", err)
+	}
+
+	now := time.Now()
+	e.lookupStart = now.Add(-min(e.olderthen, internal.CLOUDTRAIL_RETENTION))
+	allocationEvents, err := e.awsClient.GetAddressAllocationEvents(e.lookupStart, now)
+	if err != nil {
+		return fmt.Errorf("could not get CloudTrail events of Elastic IPs: %w", err)
+	}
+
+	for _, address := range addresses {
+		eip := Address{Address: address}
+		if ev, exists := allocationEvents[aws.ToString(address.AllocationId)]; exists {
+			eip.AllocationTime = ev.EventTime
+			eip.Allocator = ev.Username
+		}
+
+		if address.AssociationId != nil || address.InstanceId != nil || address.NetworkInterfaceId != nil {
+			e.usedAddresses = append(e.usedAddresses, eip)
+		} else {
+			e.unusedAddresses = append(e.unusedAddresses, eip)
+		}
+	}
+	return nil
+}
+
+func (e EIPClean) GetAllAddresses() []Address {
+	all := []Address{}
+
+	all = append(all, e.unusedAddresses...)
+	if !e.onlyUnused {
+		all = append(all, e.usedAddresses...)
+	}
+
+	return all
+}
+
+// ReleaseUnassociatedAddresses releases the unused addresses which were allocated before olderthen.
+func (e *EIPClean) ReleaseUnassociatedAddresses() error {
+	err := e.GetAddresses()
+	if err != nil {
+		return err
+	}
+
+	released := 0
+	skipped := 0
+
+	olderThenDate := time.Now().Add(e.olderthen * -1)
+	eslog.Logger.Debugf("OlderThenDate %v", olderThenDate)
+
+	for _, address := range e.unusedAddresses {
+		if e.isOldEnough(address, olderThenDate) {
+			eslog.Logger.Infof("Release %s (%s)", aws.ToString(address.PublicIp), aws.ToString(address.AllocationId))
+			err := e.awsClient.ReleaseAddress(address.Address, e.dryrun)
+			if err != nil && !internal.IsDryRunOperation(err) {
+				eslog.LogIfErrorf(err, eslog.Errorf, "Error on ReleaseAddress(): %s")
+				skipped++
+				continue
+			}
+			released++
+		} else {
+			skipped++
+		}
+	}
+
+	eslog.Logger.Infof("Released %d, Skipped %d Elastic IPs", released, skipped)
+	return nil
+}
+
+// isOldEnough returns true if the address was allocated before olderThenDate.
+func (e EIPClean) isOldEnough(address Address, olderThenDate time.Time) bool {
+	if internal.EventBefore(address.AllocationTime, e.lookupStart, olderThenDate) {
+		return true
+	}
+	if address.AllocationTime != nil {
+		eslog.Logger.Infof("Keeping %s as it's allocation time %s is newer then %s", aws.ToString(address.PublicIp), address.AllocationTime.Format(time.RFC3339), olderThenDate.Format(time.RFC3339))
+	} else {
+		eslog.Logger.Infof("Keeping %s as it's allocation time is unknown, CloudTrail only covers the past %s", aws.ToString(address.PublicIp), internal.CLOUDTRAIL_RETENTION)
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package eipclean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cloudtrailTypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+func setupSUT(t *testing.T, olderthen string, dryrun, onlyUnused bool) (*EIPClean, *mocks.MockEc2client, *mocks.MockCloudTrail) {
+	olderthenDuration, err := str2duration.ParseDuration(olderthen)
+	require.NoError(t, err)
+
+	ec2ClientMock := mocks.NewMockEc2client(t)
+	cloudTrailMock := mocks.NewMockCloudTrail(t)
+	awsClient := internal.NewFromInterface(ec2ClientMock, cloudTrailMock)
+	return NewInstance(awsClient, olderthenDuration, dryrun, onlyUnused), ec2ClientMock, cloudTrailMock
+}
+
+var (
+	associated   = types.Address{AllocationId: aws.String("eipalloc-associated"), PublicIp: aws.String("198.51.100.1"), AssociationId: aws.String("eipassoc-1"), InstanceId: aws.String("i-1")}
+	unassociated = types.Address{AllocationId: aws.String("eipalloc-unassociated"), PublicIp: aws.String("198.51.100.2")}
+	recent       = types.Address{AllocationId: aws.String("eipalloc-recent"), PublicIp: aws.String("198.51.100.3")}
+)
+
+func mockDescribeAddresses(ec2Mock *mocks.MockEc2client, addresses ...types.Address) {
+	ec2Mock.EXPECT().DescribeAddresses(context.TODO(), &ec2.DescribeAddressesInput{}).Return(&ec2.DescribeAddressesOutput{
+		Addresses: addresses,
+	}, nil).Once()
+}
+
+// mockAllocationEvents expects a lookup of the AllocateAddress events which started olderthen ago.
+func mockAllocationEvents(cloudTrailMock *mocks.MockCloudTrail, olderthen time.Duration, allocationTimes map[string]time.Time) {
+	events := []cloudtrailTypes.Event{}
+	for allocationID, allocationTime := range allocationTimes {
+		events = append(events, cloudtrailTypes.Event{
+			EventName:       aws.String("AllocateAddress"),
+			EventTime:       aws.Time(allocationTime),
+			Username:        aws.String("allocator"),
+			CloudTrailEvent: aws.String(fmt.Sprintf(`{"responseElements":{"allocationId":%q}}`, allocationID)),
+		})
+	}
+
+	cloudTrailMock.EXPECT().LookupEvents(context.TODO(), mock.MatchedBy(func(in *cloudtrail.LookupEventsInput) bool {
+		return aws.ToString(in.LookupAttributes[0].AttributeValue) == "AllocateAddress" &&
+			in.EndTime.Sub(*in.StartTime) == olderthen
+	})).Return(&cloudtrail.LookupEventsOutput{Events: events}, nil).Once()
+}
+
+func TestGetAddresses(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, ec2Mock, cloudTrailMock := setupSUT(t, "7d", false, false)
+
+		allocatedAt := time.Now().Add(-time.Hour)
+		mockDescribeAddresses(ec2Mock, associated, unassociated)
+		mockAllocationEvents(cloudTrailMock, 7*24*time.Hour, map[string]time.Time{"eipalloc-unassociated": allocatedAt})
+
+		err := SUT.GetAddresses()
+		require.NoError(t, err)
+		assert.Len(t, SUT.usedAddresses, 1)
+		require.Len(t, SUT.unusedAddresses, 1)
+		assert.Equal(t, "eipalloc-unassociated", *SUT.unusedAddresses[0].AllocationId)
+		assert.Equal(t, allocatedAt, *SUT.unusedAddresses[0].AllocationTime)
+		assert.Equal(t, "allocator", SUT.unusedAddresses[0].Allocator)
+		assert.Len(t, SUT.GetAllAddresses(), 2)
+	})
+
+	t.Run("Only Unused", func(t *testing.T) {
+		SUT, ec2Mock, cloudTrailMock := setupSUT(t, "7d", false, true)
+
+		mockDescribeAddresses(ec2Mock, associated, unassociated)
+		mockAllocationEvents(cloudTrailMock, 7*24*time.Hour, nil)
+
+		err := SUT.GetAddresses()
+		require.NoError(t, err)
+		assert.Len(t, SUT.GetAllAddresses(), 1)
+	})
+
+	t.Run("Error DescribeAddresses", func(t *testing.T) {
+		SUT, ec2Mock, _ := setupSUT(t, "7d", false, false)
+
+		ec2Mock.EXPECT().DescribeAddresses(context.TODO(), &ec2.DescribeAddressesInput{}).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.GetAddresses()
+		require.EqualError(t, err, "could not get Elastic IPs: some error")
+	})
+}
+
+func TestReleaseUnassociatedAddresses(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dryrun := true
+		SUT, ec2Mock, cloudTrailMock := setupSUT(t, "7d", dryrun, true)
+
+		mockDescribeAddresses(ec2Mock, associated, unassociated, recent)
+		mockAllocationEvents(cloudTrailMock, 7*24*time.Hour, map[string]time.Time{"eipalloc-recent": time.Now().Add(-time.Hour)})
+		ec2Mock.EXPECT().ReleaseAddress(context.TODO(), &ec2.ReleaseAddressInput{
+			AllocationId: aws.String("eipalloc-unassociated"),
+			DryRun:       aws.Bool(dryrun),
+		}).Return(nil, &smithy.GenericAPIError{Code: "DryRunOperation"}).Once()
+
+		err := SUT.ReleaseUnassociatedAddresses()
+		require.NoError(t, err)
+	})
+
+	t.Run("Unknown Allocation Time Beyond CloudTrail", func(t *testing.T) {
+		SUT, ec2Mock, cloudTrailMock := setupSUT(t, "120d", false, true)
+
+		mockDescribeAddresses(ec2Mock, unassociated)
+		mockAllocationEvents(cloudTrailMock, internal.CLOUDTRAIL_RETENTION, nil)
+
+		err := SUT.ReleaseUnassociatedAddresses()
+		require.NoError(t, err)
+		ec2Mock.AssertNotCalled(t, "ReleaseAddress", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cloudtrailTypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/steffakasid/eslog"
+)
+
+// DescribeAddresses returns all Elastic IP addresses.
+func (a AWS) DescribeAddresses() ([]ec2Types.Address, error) {
+	out, err := a.ec2.DescribeAddresses(context.TODO(), &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, err
+	}
+	return out.Addresses, nil
+}
+
+// GetAddressAllocationEvents returns the AllocateAddress event of each address allocated between startTime and
+// endTime by the allocation ID.
+func (a AWS) GetAddressAllocationEvents(startTime, endTime time.Time) (map[string]LifecycleEvent, error) {
+	return a.lookupLatestEvents(ADDRESS_ALLOCATED, allocationIDOfEvent, startTime, endTime)
+}
+
+// allocationIDOfEvent returns the allocation ID from the response of an AllocateAddress event. CloudTrail
+// doesn't list the address as resource of the event.
+func allocationIDOfEvent(ev cloudtrailTypes.Event) []string {
+	details := struct {
+		ResponseElements struct {
+			AllocationId string `json:"allocationId"`
+		} `json:"responseElements"`
+	}{}
+	if !parseCloudTrailEvent(ev, &details) || details.ResponseElements.AllocationId == "" {
+		return nil
+	}
+	return []string{details.ResponseElements.AllocationId}
+}
+
+// ReleaseAddress releases the address by its allocation ID. Addresses without allocation ID are released by
+// their public IP.
+func (a AWS) ReleaseAddress(address ec2Types.Address, dryrun bool) error {
+	eslog.Logger.Debugf("ReleaseAddress(%s, %s), dryrun: %t", aws.ToString(address.AllocationId), aws.ToString(address.PublicIp), dryrun)
+
+	opts := &ec2.ReleaseAddressInput{
+		DryRun:             &dryrun,
+		NetworkBorderGroup: address.NetworkBorderGroup,
+	}
+	if address.AllocationId != nil {
+		opts.AllocationId = address.AllocationId
+	} else {
+		opts.PublicIp = address.PublicIp
+	}
+	_, err := a.ec2.ReleaseAddress(context.TODO(), opts)
+	return err
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cloudtrailTypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeAddresses(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+		mock.EXPECT().DescribeAddresses(context.TODO(), &ec2.DescribeAddressesInput{}).Return(&ec2.DescribeAddressesOutput{
+			Addresses: []types.Address{{AllocationId: aws.String("eipalloc-1")}},
+		}, nil).Once()
+
+		addresses, err := SUT.DescribeAddresses()
+		require.NoError(t, err)
+		require.Len(t, addresses, 1)
+		assert.Equal(t, "eipalloc-1", *addresses[0].AllocationId)
+	})
+
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+		mock.EXPECT().DescribeAddresses(context.TODO(), &ec2.DescribeAddressesInput{}).Return(nil, errors.New("Something went wrong")).Once()
+
+		_, err := SUT.DescribeAddresses()
+		require.EqualError(t, err, "Something went wrong")
+	})
+}
+
+func TestGetAddressAllocationEvents(t *testing.T) {
+	starttime, err := time.Parse(time.DateTime, "2006-01-02 15:04:05")
+	require.NoError(t, err)
+	endtime := starttime.Add(24 * time.Hour)
+
+	SUT, _, cloudTrailMock := setupSUT(t)
+	cloudTrailMock.EXPECT().LookupEvents(context.TODO(), &cloudtrail.LookupEventsInput{
+		StartTime: &starttime,
+		EndTime:   &endtime,
+		LookupAttributes: []cloudtrailTypes.LookupAttribute{
+			{
+				AttributeKey:   cloudtrailTypes.LookupAttributeKeyEventName,
+				AttributeValue: aws.String("AllocateAddress"),
+			},
+		},
+	}).Return(&cloudtrail.LookupEventsOutput{
+		Events: []cloudtrailTypes.Event{
+			{EventName: aws.String("AllocateAddress"), EventTime: aws.Time(starttime.Add(time.Hour)), Username: aws.String("alice"),
+				CloudTrailEvent: aws.String(`{"responseElements":{"publicIp":"198.51.100.1","allocationId":"eipalloc-1"}}`)},
+			{EventName: aws.String("AllocateAddress"), EventTime: aws.Time(starttime.Add(2 * time.Hour)), Username: aws.String("bob"),
+				CloudTrailEvent: aws.String(`{"errorCode":"Client.AddressLimitExceeded","responseElements":null}`)},
+		},
+	}, nil).Once()
+
+	events, err := SUT.GetAddressAllocationEvents(starttime, endtime)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "alice", events["eipalloc-1"].Username)
+}
+
+func TestReleaseAddress(t *testing.T) {
+	t.Run("By Allocation ID", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+		mock.EXPECT().ReleaseAddress(context.TODO(), &ec2.ReleaseAddressInput{
+			AllocationId:       aws.String("eipalloc-1"),
+			NetworkBorderGroup: aws.String("eu-central-1"),
+			DryRun:             aws.Bool(false),
+		}).Return(&ec2.ReleaseAddressOutput{}, nil).Once()
+
+		err := SUT.ReleaseAddress(types.Address{AllocationId: aws.String("eipalloc-1"), PublicIp: aws.String("198.51.100.1"), NetworkBorderGroup: aws.String("eu-central-1")}, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("By Public IP", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+		mock.EXPECT().ReleaseAddress(context.TODO(), &ec2.ReleaseAddressInput{
+			PublicIp: aws.String("198.51.100.1"),
+			DryRun:   aws.Bool(true),
+		}).Return(nil, errors.New("Something went wrong")).Once()
+
+		err := SUT.ReleaseAddress(types.Address{PublicIp: aws.String("198.51.100.1")}, true)
+		require.EqualError(t, err, "Something went wrong")
+	})
+}
@@ -0,0 +1,262 @@
+package elbclean
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/steffakasid/awsclean/internal"
+	eslog "github.com/steffakasid/eslog"
+)
+
+// LoadBalancer adds the targets of all target groups of the load balancer.
+type LoadBalancer struct {
+	types.LoadBalancer
+	TargetGroups      []string
+	RegisteredTargets int
+	HealthyTargets    int
+	// listeners whose default actions don't forward to a target group, e.g. redirects or fixed responses
+	NonForwardListeners int
+	// the latest DeregisterTargets event of the target groups in CloudTrail
+	LastDeregistration *time.Time
+}
+
+// TargetGroup adds the registered targets and the creation information from CloudTrail as AWS doesn't
+// return when a target group was created.
+type TargetGroup struct {
+	types.TargetGroup
+	RegisteredTargets int
+	CreationTime      *time.Time
+	Creator           string
+}
+
+type ELBClean struct {
+	awsClient           *internal.AWS
+	olderthen           time.Duration
+	dryrun              bool
+	onlyUnused          bool
+	lookupStart         time.Time
+	usedLoadBalancers   []LoadBalancer
+	unusedLoadBalancers []LoadBalancer
+	usedTargetGroups    []TargetGroup
+	unusedTargetGroups  []TargetGroup
+}
+
+func NewInstance(awsClient *internal.AWS, olderthen time.Duration, dryrun bool, onlyUnused bool) *ELBClean {
+	return &ELBClean{
+		awsClient:           awsClient,
+		olderthen:           olderthen,
+		dryrun:              dryrun,
+		onlyUnused:          onlyUnused,
+		usedLoadBalancers:   []LoadBalancer{},
+		unusedLoadBalancers: []LoadBalancer{},
+		usedTargetGroups:    []TargetGroup{},
+		unusedTargetGroups:  []TargetGroup{},
+	}
+}
+
+// GetLoadBalancers fetches all load balancers and target groups and sorts them into used and unused ones. A
+// load balancer is unused if none of its target groups has a registered target and none of its listeners
+// answers without forwarding. Unhealthy targets still count as the load balancer may be in use but failing,
+// e.g. during an outage or for Lambda targets without health checks. A target group is unused if it's not
+// attached to any load balancer. The CloudTrail events are looked up for the past olderthen, at most 90 days.
+func (e *ELBClean) GetLoadBalancers() error {
+	loadBalancers, err := e.awsClient.GetLoadBalancers()
+	if err != nil {
+		return fmt.Errorf("could not get load balancers: %w", err)
+	}
+	targetGroups, err := e.awsClient.GetTargetGroups()
+	if err != nil {
+		return fmt.Errorf("could not get target groups: %w", err)
+	}
+
+	now := time.Now()
+	e.lookupStart = now.Add(-min(e.olderthen, internal.CLOUDTRAIL_RETENTION))
+	creationEvents, err := e.awsClient.GetTargetGroupCreationEvents(e.lookupStart, now)
+	if err != nil {
+		return fmt.Errorf("could not get CloudTrail events of target groups: %w", err)
+	}
+	deregistrationEvents, err := e.awsClient.GetTargetDeregistrationEvents(e.lookupStart, now)
+	if err != nil {
+		return fmt.Errorf("could not get CloudTrail events of targets: %w", err)
+	}
+
+	lbs := map[string]*LoadBalancer{}
+	for _, loadBalancer := range loadBalancers {
+		lbs[aws.ToString(loadBalancer.LoadBalancerArn)] = &LoadBalancer{LoadBalancer: loadBalancer}
+	}
+
+	for _, targetGroup := range targetGroups {
+		targetGroupArn := aws.ToString(targetGroup.TargetGroupArn)
+		targets, err := e.awsClient.GetTargetHealth(targetGroupArn)
+		if err != nil {
+			return fmt.Errorf("could not get targets of %s: %w", aws.ToString(targetGroup.TargetGroupName), err)
+		}
+
+		tg := TargetGroup{TargetGroup: targetGroup, RegisteredTargets: len(targets)}
+		if ev, exists := creationEvents[targetGroupArn]; exists {
+			tg.CreationTime = ev.EventTime
+			tg.Creator = ev.Username
+		}
+		if len(targetGroup.LoadBalancerArns) == 0 {
+			e.unusedTargetGroups = append(e.unusedTargetGroups, tg)
+		} else {
+			e.usedTargetGroups = append(e.usedTargetGroups, tg)
+		}
+
+		for _, loadBalancerArn := range targetGroup.LoadBalancerArns {
+			lb, exists := lbs[loadBalancerArn]
+			if !exists {
+				continue
+			}
+			lb.TargetGroups = append(lb.TargetGroups, aws.ToString(targetGroup.TargetGroupName))
+			lb.RegisteredTargets += len(targets)
+			for _, target := range targets {
+				if target.TargetHealth != nil && target.TargetHealth.State == types.TargetHealthStateEnumHealthy {
+					lb.HealthyTargets++
+				}
+			}
+			if ev, exists := deregistrationEvents[targetGroupArn]; exists &&
+				(lb.LastDeregistration == nil || ev.EventTime.After(*lb.LastDeregistration)) {
+				lb.LastDeregistration = ev.EventTime
+			}
+		}
+	}
+
+	for _, loadBalancer := range loadBalancers {
+		lb := lbs[aws.ToString(loadBalancer.LoadBalancerArn)]
+		// the listeners are only needed if the load balancer has no targets
+		if lb.RegisteredTargets == 0 {
+			lb.NonForwardListeners, err = e.countNonForwardListeners(aws.ToString(loadBalancer.LoadBalancerArn))
+			if err != nil {
+				return fmt.Errorf("could not get listeners of %s: %w", aws.ToString(loadBalancer.LoadBalancerName), err)
+			}
+		}
+
+		if lb.RegisteredTargets > 0 || lb.NonForwardListeners > 0 {
+			e.usedLoadBalancers = append(e.usedLoadBalancers, *lb)
+		} else {
+			e.unusedLoadBalancers = append(e.unusedLoadBalancers, *lb)
+		}
+	}
+	return nil
+}
+
+// countNonForwardListeners returns the number of listeners of the load balancer without a forward default
+// action. These answer requests themselves, e.g. by a redirect or a fixed response.
+func (e ELBClean) countNonForwardListeners(loadBalancerArn string) (int, error) {
+	listeners, err := e.awsClient.GetListeners(loadBalancerArn)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, listener := range listeners {
+		forwards := slices.ContainsFunc(listener.DefaultActions, func(action types.Action) bool {
+			return action.Type == types.ActionTypeEnumForward
+		})
+		if !forwards && len(listener.DefaultActions) > 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (e ELBClean) GetAllLoadBalancers() []LoadBalancer {
+	all := []LoadBalancer{}
+
+	all = append(all, e.unusedLoadBalancers...)
+	if !e.onlyUnused {
+		all = append(all, e.usedLoadBalancers...)
+	}
+
+	return all
+}
+
+func (e ELBClean) GetAllTargetGroups() []TargetGroup {
+	all := []TargetGroup{}
+
+	all = append(all, e.unusedTargetGroups...)
+	if !e.onlyUnused {
+		all = append(all, e.usedTargetGroups...)
+	}
+
+	return all
+}
+
+// DeleteUnusedLoadBalancers deletes the load balancers without registered targets for longer then olderthen and
+// the target groups which are attached to no load balancer and were created before olderthen. Target groups
+// of deleted load balancers are deleted by the next run.
+func (e *ELBClean) DeleteUnusedLoadBalancers() error {
+	err := e.GetLoadBalancers()
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	skipped := 0
+
+	olderThenDate := time.Now().Add(e.olderthen * -1)
+	eslog.Logger.Debugf("OlderThenDate %v", olderThenDate)
+
+	for _, lb := range e.unusedLoadBalancers {
+		name := aws.ToString(lb.LoadBalancerName)
+		if !e.isUnusedLongEnough(lb, olderThenDate) {
+			skipped++
+			continue
+		}
+		eslog.Logger.Infof("Delete load balancer %s (dry run: %t)", name, e.dryrun)
+		err := e.awsClient.DeleteLoadBalancer(aws.ToString(lb.LoadBalancerArn), e.dryrun)
+		if err != nil {
+			eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeleteLoadBalancer(): %s")
+			skipped++
+			continue
+		}
+		deleted++
+	}
+
+	for _, tg := range e.unusedTargetGroups {
+		name := aws.ToString(tg.TargetGroupName)
+		if !e.isOldEnough(name, tg.CreationTime, olderThenDate) {
+			skipped++
+			continue
+		}
+		eslog.Logger.Infof("Delete target group %s (dry run: %t)", name, e.dryrun)
+		err := e.awsClient.DeleteTargetGroup(aws.ToString(tg.TargetGroupArn), e.dryrun)
+		if err != nil {
+			eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeleteTargetGroup(): %s")
+			skipped++
+			continue
+		}
+		deleted++
+	}
+
+	eslog.Logger.Infof("Deleted %d, Skipped %d load balancers and target groups", deleted, skipped)
+	return nil
+}
+
+// isUnusedLongEnough returns true if the load balancer was created and its targets were deregistered before
+// olderThenDate.
+func (e ELBClean) isUnusedLongEnough(lb LoadBalancer, olderThenDate time.Time) bool {
+	name := aws.ToString(lb.LoadBalancerName)
+	if lb.CreatedTime != nil && !lb.CreatedTime.Before(olderThenDate) {
+		eslog.Logger.Infof("Keeping %s as it's creation time %s is newer then %s", name, lb.CreatedTime.Format(time.RFC3339), olderThenDate.Format(time.RFC3339))
+		return false
+	}
+	return e.isOldEnough(name, lb.LastDeregistration, olderThenDate)
+}
+
+// isOldEnough returns true if the CloudTrail event at eventTime happened before olderThenDate.
+func (e ELBClean) isOldEnough(name string, eventTime *time.Time, olderThenDate time.Time) bool {
+	if internal.EventBefore(eventTime, e.lookupStart, olderThenDate) {
+		return true
+	}
+	if eventTime != nil {
+		eslog.Logger.Infof("Keeping %s as it changed at %s which is newer then %s", name, eventTime.Format(time.RFC3339), olderThenDate.Format(time.RFC3339))
+	} else {
+		eslog.Logger.Infof("Keeping %s as the last change is unknown, CloudTrail only covers the past %s", name, internal.CLOUDTRAIL_RETENTION)
+	}
+	return false
+}
@@ -0,0 +1,312 @@
+package elbclean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cloudtrailTypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+func setupSUT(t *testing.T, olderthen string, dryrun, onlyUnused bool) (*ELBClean, *mocks.MockELBv2, *mocks.MockCloudTrail) {
+	olderthenDuration, err := str2duration.ParseDuration(olderthen)
+	require.NoError(t, err)
+
+	elbv2Mock := mocks.NewMockELBv2(t)
+	cloudTrailMock := mocks.NewMockCloudTrail(t)
+	awsClient := internal.NewFromInterface(mocks.NewMockEc2client(t), cloudTrailMock, internal.WithELBv2(elbv2Mock))
+	return NewInstance(awsClient, olderthenDuration, dryrun, onlyUnused), elbv2Mock, cloudTrailMock
+}
+
+var (
+	longAgo = time.Now().Add(-365 * 24 * time.Hour)
+
+	healthyLB = types.LoadBalancer{LoadBalancerArn: aws.String("arn:lb-healthy"), LoadBalancerName: aws.String("healthy"), CreatedTime: &longAgo}
+	emptyLB   = types.LoadBalancer{LoadBalancerArn: aws.String("arn:lb-empty"), LoadBalancerName: aws.String("empty"), CreatedTime: &longAgo}
+
+	healthyTG  = types.TargetGroup{TargetGroupArn: aws.String("arn:tg-healthy"), TargetGroupName: aws.String("healthy"), LoadBalancerArns: []string{"arn:lb-healthy"}}
+	emptyTG    = types.TargetGroup{TargetGroupArn: aws.String("arn:tg-empty"), TargetGroupName: aws.String("empty"), LoadBalancerArns: []string{"arn:lb-empty"}}
+	orphanedTG = types.TargetGroup{TargetGroupArn: aws.String("arn:tg-orphaned"), TargetGroupName: aws.String("orphaned")}
+)
+
+func mockLoadBalancers(elbv2Mock *mocks.MockELBv2, loadBalancers []types.LoadBalancer, targetGroups []types.TargetGroup, targets map[string][]types.TargetHealthDescription, listeners map[string][]types.Listener) {
+	elbv2Mock.EXPECT().DescribeLoadBalancers(context.TODO(), &elasticloadbalancingv2.DescribeLoadBalancersInput{}).Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+		LoadBalancers: loadBalancers,
+	}, nil).Once()
+	elbv2Mock.EXPECT().DescribeTargetGroups(context.TODO(), &elasticloadbalancingv2.DescribeTargetGroupsInput{}).Return(&elasticloadbalancingv2.DescribeTargetGroupsOutput{
+		TargetGroups: targetGroups,
+	}, nil).Once()
+	registered := map[string]bool{}
+	for _, targetGroup := range targetGroups {
+		elbv2Mock.EXPECT().DescribeTargetHealth(context.TODO(), &elasticloadbalancingv2.DescribeTargetHealthInput{TargetGroupArn: targetGroup.TargetGroupArn}).Return(&elasticloadbalancingv2.DescribeTargetHealthOutput{
+			TargetHealthDescriptions: targets[*targetGroup.TargetGroupArn],
+		}, nil).Once()
+		for _, loadBalancerArn := range targetGroup.LoadBalancerArns {
+			registered[loadBalancerArn] = registered[loadBalancerArn] || len(targets[*targetGroup.TargetGroupArn]) > 0
+		}
+	}
+	// the listeners are only looked up for load balancers without targets
+	for _, loadBalancer := range loadBalancers {
+		if !registered[*loadBalancer.LoadBalancerArn] {
+			elbv2Mock.EXPECT().DescribeListeners(context.TODO(), &elasticloadbalancingv2.DescribeListenersInput{LoadBalancerArn: loadBalancer.LoadBalancerArn}).Return(&elasticloadbalancingv2.DescribeListenersOutput{
+				Listeners: listeners[*loadBalancer.LoadBalancerArn],
+			}, nil).Once()
+		}
+	}
+}
+
+// mockEvents expects a lookup of the given events which started olderthen ago. The event times are given by
+// target group ARN.
+func mockEvents(cloudTrailMock *mocks.MockCloudTrail, eventName string, olderthen time.Duration, eventTimes map[string]time.Time) {
+	events := []cloudtrailTypes.Event{}
+	for targetGroupArn, eventTime := range eventTimes {
+		cloudTrailEvent := fmt.Sprintf(`{"requestParameters":{"targetGroupArn":%q}}`, targetGroupArn)
+		if eventName == "CreateTargetGroup" {
+			cloudTrailEvent = fmt.Sprintf(`{"responseElements":{"targetGroups":[{"targetGroupArn":%q}]}}`, targetGroupArn)
+		}
+		events = append(events, cloudtrailTypes.Event{
+			EventName:       aws.String(eventName),
+			EventTime:       aws.Time(eventTime),
+			Username:        aws.String("creator"),
+			CloudTrailEvent: aws.String(cloudTrailEvent),
+		})
+	}
+
+	cloudTrailMock.EXPECT().LookupEvents(context.TODO(), mock.MatchedBy(func(in *cloudtrail.LookupEventsInput) bool {
+		return aws.ToString(in.LookupAttributes[0].AttributeValue) == eventName &&
+			in.EndTime.Sub(*in.StartTime) == olderthen
+	})).Return(&cloudtrail.LookupEventsOutput{Events: events}, nil).Once()
+}
+
+var healthyTargets = map[string][]types.TargetHealthDescription{
+	"arn:tg-healthy": {
+		{Target: &types.TargetDescription{Id: aws.String("i-1")}, TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumHealthy}},
+		{Target: &types.TargetDescription{Id: aws.String("i-2")}, TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumUnhealthy}},
+	},
+}
+
+func TestGetLoadBalancers(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", false, false)
+
+		createdAt := time.Now().Add(-time.Hour)
+		mockLoadBalancers(elbv2Mock, []types.LoadBalancer{healthyLB, emptyLB}, []types.TargetGroup{healthyTG, emptyTG, orphanedTG}, healthyTargets, nil)
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, map[string]time.Time{"arn:tg-orphaned": createdAt})
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, map[string]time.Time{"arn:tg-empty": createdAt})
+
+		err := SUT.GetLoadBalancers()
+		require.NoError(t, err)
+
+		require.Len(t, SUT.usedLoadBalancers, 1)
+		assert.Equal(t, "healthy", *SUT.usedLoadBalancers[0].LoadBalancerName)
+		assert.Equal(t, 2, SUT.usedLoadBalancers[0].RegisteredTargets)
+		assert.Equal(t, 1, SUT.usedLoadBalancers[0].HealthyTargets)
+		require.Len(t, SUT.unusedLoadBalancers, 1)
+		assert.Equal(t, []string{"empty"}, SUT.unusedLoadBalancers[0].TargetGroups)
+		assert.Equal(t, createdAt, *SUT.unusedLoadBalancers[0].LastDeregistration)
+
+		assert.Len(t, SUT.usedTargetGroups, 2)
+		require.Len(t, SUT.unusedTargetGroups, 1)
+		assert.Equal(t, "orphaned", *SUT.unusedTargetGroups[0].TargetGroupName)
+		assert.Equal(t, createdAt, *SUT.unusedTargetGroups[0].CreationTime)
+		assert.Equal(t, "creator", SUT.unusedTargetGroups[0].Creator)
+
+		assert.Len(t, SUT.GetAllLoadBalancers(), 2)
+		assert.Len(t, SUT.GetAllTargetGroups(), 3)
+	})
+
+	t.Run("Only Unused", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", false, true)
+
+		mockLoadBalancers(elbv2Mock, []types.LoadBalancer{healthyLB, emptyLB}, []types.TargetGroup{healthyTG, emptyTG, orphanedTG}, healthyTargets, nil)
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, nil)
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, nil)
+
+		err := SUT.GetLoadBalancers()
+		require.NoError(t, err)
+		assert.Len(t, SUT.GetAllLoadBalancers(), 1)
+		assert.Len(t, SUT.GetAllTargetGroups(), 1)
+	})
+
+	t.Run("Unhealthy Targets", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", false, false)
+
+		mockLoadBalancers(elbv2Mock, []types.LoadBalancer{emptyLB}, []types.TargetGroup{emptyTG}, map[string][]types.TargetHealthDescription{
+			"arn:tg-empty": {
+				{Target: &types.TargetDescription{Id: aws.String("i-3")}, TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumUnhealthy}},
+			},
+		}, nil)
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, nil)
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, nil)
+
+		err := SUT.GetLoadBalancers()
+		require.NoError(t, err)
+		require.Len(t, SUT.usedLoadBalancers, 1)
+		assert.Equal(t, 1, SUT.usedLoadBalancers[0].RegisteredTargets)
+		assert.Equal(t, 0, SUT.usedLoadBalancers[0].HealthyTargets)
+		assert.Empty(t, SUT.unusedLoadBalancers)
+	})
+
+	t.Run("Lambda Targets", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", false, false)
+
+		// Lambda targets are unavailable if health checks are disabled, which is the default
+		mockLoadBalancers(elbv2Mock, []types.LoadBalancer{emptyLB}, []types.TargetGroup{emptyTG}, map[string][]types.TargetHealthDescription{
+			"arn:tg-empty": {
+				{Target: &types.TargetDescription{Id: aws.String("arn:aws:lambda:eu-central-1:123456789012:function:api")}, TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumUnavailable}},
+			},
+		}, nil)
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, nil)
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, nil)
+
+		err := SUT.GetLoadBalancers()
+		require.NoError(t, err)
+		assert.Len(t, SUT.usedLoadBalancers, 1)
+		assert.Empty(t, SUT.unusedLoadBalancers)
+	})
+
+	t.Run("Non Forward Listeners", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", false, false)
+
+		redirectLB := types.LoadBalancer{LoadBalancerArn: aws.String("arn:lb-redirect"), LoadBalancerName: aws.String("redirect"), CreatedTime: &longAgo}
+		mockLoadBalancers(elbv2Mock, []types.LoadBalancer{emptyLB, redirectLB}, []types.TargetGroup{emptyTG}, nil, map[string][]types.Listener{
+			"arn:lb-empty": {
+				{DefaultActions: []types.Action{{Type: types.ActionTypeEnumForward}}},
+			},
+			"arn:lb-redirect": {
+				{DefaultActions: []types.Action{{Type: types.ActionTypeEnumRedirect}}},
+				{DefaultActions: []types.Action{{Type: types.ActionTypeEnumFixedResponse}}},
+			},
+		})
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, nil)
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, nil)
+
+		err := SUT.GetLoadBalancers()
+		require.NoError(t, err)
+		require.Len(t, SUT.usedLoadBalancers, 1)
+		assert.Equal(t, "redirect", *SUT.usedLoadBalancers[0].LoadBalancerName)
+		assert.Equal(t, 2, SUT.usedLoadBalancers[0].NonForwardListeners)
+		require.Len(t, SUT.unusedLoadBalancers, 1)
+		assert.Equal(t, "empty", *SUT.unusedLoadBalancers[0].LoadBalancerName)
+	})
+
+	t.Run("Error DescribeListeners", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", false, false)
+
+		elbv2Mock.EXPECT().DescribeLoadBalancers(context.TODO(), &elasticloadbalancingv2.DescribeLoadBalancersInput{}).Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []types.LoadBalancer{emptyLB},
+		}, nil).Once()
+		elbv2Mock.EXPECT().DescribeTargetGroups(context.TODO(), &elasticloadbalancingv2.DescribeTargetGroupsInput{}).Return(&elasticloadbalancingv2.DescribeTargetGroupsOutput{}, nil).Once()
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, nil)
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, nil)
+		elbv2Mock.EXPECT().DescribeListeners(context.TODO(), mock.Anything).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.GetLoadBalancers()
+		require.EqualError(t, err, "could not get listeners of empty: some error")
+	})
+
+	t.Run("Error DescribeLoadBalancers", func(t *testing.T) {
+		SUT, elbv2Mock, _ := setupSUT(t, "7d", false, false)
+
+		elbv2Mock.EXPECT().DescribeLoadBalancers(context.TODO(), &elasticloadbalancingv2.DescribeLoadBalancersInput{}).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.GetLoadBalancers()
+		require.EqualError(t, err, "could not get load balancers: some error")
+	})
+
+	t.Run("Error DescribeTargetHealth", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", false, false)
+
+		elbv2Mock.EXPECT().DescribeLoadBalancers(context.TODO(), &elasticloadbalancingv2.DescribeLoadBalancersInput{}).Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{}, nil).Once()
+		elbv2Mock.EXPECT().DescribeTargetGroups(context.TODO(), &elasticloadbalancingv2.DescribeTargetGroupsInput{}).Return(&elasticloadbalancingv2.DescribeTargetGroupsOutput{
+			TargetGroups: []types.TargetGroup{orphanedTG},
+		}, nil).Once()
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, nil)
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, nil)
+		elbv2Mock.EXPECT().DescribeTargetHealth(context.TODO(), mock.Anything).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.GetLoadBalancers()
+		require.EqualError(t, err, "could not get targets of orphaned: some error")
+	})
+}
+
+func TestDeleteUnusedLoadBalancers(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", false, true)
+
+		recentTG := types.TargetGroup{TargetGroupArn: aws.String("arn:tg-recent"), TargetGroupName: aws.String("recent")}
+		mockLoadBalancers(elbv2Mock, []types.LoadBalancer{healthyLB, emptyLB}, []types.TargetGroup{healthyTG, emptyTG, orphanedTG, recentTG}, healthyTargets, nil)
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, map[string]time.Time{"arn:tg-recent": time.Now().Add(-time.Hour)})
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, nil)
+		elbv2Mock.EXPECT().DeleteLoadBalancer(context.TODO(), &elasticloadbalancingv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String("arn:lb-empty")}).Return(&elasticloadbalancingv2.DeleteLoadBalancerOutput{}, nil).Once()
+		elbv2Mock.EXPECT().DeleteTargetGroup(context.TODO(), &elasticloadbalancingv2.DeleteTargetGroupInput{TargetGroupArn: aws.String("arn:tg-orphaned")}).Return(&elasticloadbalancingv2.DeleteTargetGroupOutput{}, nil).Once()
+
+		err := SUT.DeleteUnusedLoadBalancers()
+		require.NoError(t, err)
+	})
+
+	t.Run("Recent Deregistration", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", false, true)
+
+		mockLoadBalancers(elbv2Mock, []types.LoadBalancer{emptyLB}, []types.TargetGroup{emptyTG}, healthyTargets, nil)
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, nil)
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, map[string]time.Time{"arn:tg-empty": time.Now().Add(-time.Hour)})
+
+		err := SUT.DeleteUnusedLoadBalancers()
+		require.NoError(t, err)
+		elbv2Mock.AssertNotCalled(t, "DeleteLoadBalancer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unhealthy Targets", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", false, true)
+
+		mockLoadBalancers(elbv2Mock, []types.LoadBalancer{emptyLB}, []types.TargetGroup{emptyTG}, map[string][]types.TargetHealthDescription{
+			"arn:tg-empty": {
+				{Target: &types.TargetDescription{Id: aws.String("i-3")}, TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumUnhealthy}},
+			},
+		}, nil)
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, nil)
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, nil)
+
+		err := SUT.DeleteUnusedLoadBalancers()
+		require.NoError(t, err)
+		elbv2Mock.AssertNotCalled(t, "DeleteLoadBalancer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "7d", true, true)
+
+		mockLoadBalancers(elbv2Mock, []types.LoadBalancer{emptyLB}, []types.TargetGroup{emptyTG, orphanedTG}, healthyTargets, nil)
+		mockEvents(cloudTrailMock, "CreateTargetGroup", 7*24*time.Hour, nil)
+		mockEvents(cloudTrailMock, "DeregisterTargets", 7*24*time.Hour, nil)
+
+		err := SUT.DeleteUnusedLoadBalancers()
+		require.NoError(t, err)
+		elbv2Mock.AssertNotCalled(t, "DeleteLoadBalancer", mock.Anything, mock.Anything)
+		elbv2Mock.AssertNotCalled(t, "DeleteTargetGroup", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown Deregistration Beyond CloudTrail", func(t *testing.T) {
+		SUT, elbv2Mock, cloudTrailMock := setupSUT(t, "120d", false, true)
+
+		mockLoadBalancers(elbv2Mock, []types.LoadBalancer{emptyLB}, []types.TargetGroup{emptyTG}, healthyTargets, nil)
+		mockEvents(cloudTrailMock, "CreateTargetGroup", internal.CLOUDTRAIL_RETENTION, nil)
+		mockEvents(cloudTrailMock, "DeregisterTargets", internal.CLOUDTRAIL_RETENTION, nil)
+
+		err := SUT.DeleteUnusedLoadBalancers()
+		require.NoError(t, err)
+		elbv2Mock.AssertNotCalled(t, "DeleteLoadBalancer", mock.Anything, mock.Anything)
+	})
+}
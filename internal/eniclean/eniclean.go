@@ -0,0 +1,132 @@
+package eniclean
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/steffakasid/awsclean/internal"
+	eslog "github.com/steffakasid/eslog"
+)
+
+// NetworkInterface adds the creation information from CloudTrail as AWS doesn't return when an interface
+// was created.
+type NetworkInterface struct {
+	types.NetworkInterface
+	CreationTime *time.Time
+	Creator      string
+}
+
+type ENIClean struct {
+	awsClient    *internal.AWS
+	olderthen    time.Duration
+	dryrun       bool
+	onlyUnused   bool
+	lookupStart  time.Time
+	usedIfaces   []NetworkInterface
+	unusedIfaces []NetworkInterface
+}
+
+func NewInstance(awsClient *internal.AWS, olderthen time.Duration, dryrun bool, onlyUnused bool) *ENIClean {
+	return &ENIClean{
+		awsClient:    awsClient,
+		olderthen:    olderthen,
+		dryrun:       dryrun,
+		onlyUnused:   onlyUnused,
+		usedIfaces:   []NetworkInterface{},
+		unusedIfaces: []NetworkInterface{},
+	}
+}
+
+// GetNetworkInterfaces fetches all network interfaces and sorts them into used and unused ones. An interface
+// is unused if it's available, i.e. not attached, and not managed by another service. The creation time is
+// looked up in CloudTrail for the past olderthen, at most 90 days.
+func (e *ENIClean) GetNetworkInterfaces() error {
+	ifaces, err := e.awsClient.GetNetworkInterfaces()
+	if err != nil {
+		return fmt.Errorf("could not get network interfaces: %w", err)
+	}
+
+	now := time.Now()
+	e.lookupStart = now.Add(-min(e.olderthen, internal.CLOUDTRAIL_RETENTION))
+	creationEvents, err := e.awsClient.GetNetworkInterfaceCreationEvents(e.lookupStart, now)
+	if err != nil {
+		return fmt.Errorf("could not get CloudTrail events of network interfaces: %w", err)
+	}
+
+	for _, iface := range ifaces {
+		eni := NetworkInterface{NetworkInterface: iface}
+		if ev, exists := creationEvents[aws.ToString(iface.NetworkInterfaceId)]; exists {
+			eni.CreationTime = ev.EventTime
+			eni.Creator = ev.Username
+		}
+
+		switch {
+		case iface.Status != types.NetworkInterfaceStatusAvailable:
+			e.usedIfaces = append(e.usedIfaces, eni)
+		case aws.ToBool(iface.RequesterManaged):
+			eslog.Logger.Infof("Managed by %s: %s", aws.ToString(iface.RequesterId), *iface.NetworkInterfaceId)
+			e.usedIfaces = append(e.usedIfaces, eni)
+		default:
+			e.unusedIfaces = append(e.unusedIfaces, eni)
+		}
+	}
+	return nil
+}
+
+func (e ENIClean) GetAllNetworkInterfaces() []NetworkInterface {
+	all := []NetworkInterface{}
+
+	all = append(all, e.unusedIfaces...)
+	if !e.onlyUnused {
+		all = append(all, e.usedIfaces...)
+	}
+
+	return all
+}
+
+// DeleteOrphanedNetworkInterfaces deletes the unused network interfaces which are older then olderthen.
+func (e *ENIClean) DeleteOrphanedNetworkInterfaces() error {
+	err := e.GetNetworkInterfaces()
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	skipped := 0
+
+	olderThenDate := time.Now().Add(e.olderthen * -1)
+	eslog.Logger.Debugf("OlderThenDate %v", olderThenDate)
+
+	for _, iface := range e.unusedIfaces {
+		if e.isOldEnough(iface, olderThenDate) {
+			eslog.Logger.Infof("Delete %s", *iface.NetworkInterfaceId)
+			err := e.awsClient.DeleteNetworkInterface(*iface.NetworkInterfaceId, e.dryrun)
+			if err != nil && !internal.IsDryRunOperation(err) {
+				eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeleteNetworkInterface(): %s")
+				skipped++
+				continue
+			}
+			deleted++
+		} else {
+			skipped++
+		}
+	}
+
+	eslog.Logger.Infof("Deleted %d, Skipped %d network interfaces", deleted, skipped)
+	return nil
+}
+
+// isOldEnough returns true if the interface was created before olderThenDate.
+func (e ENIClean) isOldEnough(iface NetworkInterface, olderThenDate time.Time) bool {
+	if internal.EventBefore(iface.CreationTime, e.lookupStart, olderThenDate) {
+		return true
+	}
+	if iface.CreationTime != nil {
+		eslog.Logger.Infof("Keeping %s as it's creation time %s is newer then %s", *iface.NetworkInterfaceId, iface.CreationTime.Format(time.RFC3339), olderThenDate.Format(time.RFC3339))
+	} else {
+		eslog.Logger.Infof("Keeping %s as it's creation time is unknown, CloudTrail only covers the past %s", *iface.NetworkInterfaceId, internal.CLOUDTRAIL_RETENTION)
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package eniclean
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cloudtrailTypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+func setupSUT(t *testing.T, olderthen string, dryrun, onlyUnused bool) (*ENIClean, *mocks.MockEc2client, *mocks.MockCloudTrail) {
+	olderthenDuration, err := str2duration.ParseDuration(olderthen)
+	require.NoError(t, err)
+
+	ec2ClientMock := mocks.NewMockEc2client(t)
+	cloudTrailMock := mocks.NewMockCloudTrail(t)
+	awsClient := internal.NewFromInterface(ec2ClientMock, cloudTrailMock)
+	return NewInstance(awsClient, olderthenDuration, dryrun, onlyUnused), ec2ClientMock, cloudTrailMock
+}
+
+var (
+	attached = types.NetworkInterface{NetworkInterfaceId: aws.String("eni-attached"), Status: types.NetworkInterfaceStatusInUse}
+	managed  = types.NetworkInterface{NetworkInterfaceId: aws.String("eni-managed"), Status: types.NetworkInterfaceStatusAvailable,
+		RequesterManaged: aws.Bool(true), RequesterId: aws.String("amazon-elb")}
+	orphaned = types.NetworkInterface{NetworkInterfaceId: aws.String("eni-orphaned"), Status: types.NetworkInterfaceStatusAvailable}
+	recent   = types.NetworkInterface{NetworkInterfaceId: aws.String("eni-recent"), Status: types.NetworkInterfaceStatusAvailable}
+)
+
+func mockDescribeNetworkInterfaces(ec2Mock *mocks.MockEc2client, ifaces ...types.NetworkInterface) {
+	ec2Mock.EXPECT().DescribeNetworkInterfaces(context.TODO(), &ec2.DescribeNetworkInterfacesInput{}).Return(&ec2.DescribeNetworkInterfacesOutput{
+		NetworkInterfaces: ifaces,
+	}, nil).Once()
+}
+
+// mockCreationEvents expects a lookup of the CreateNetworkInterface events which started olderthen ago.
+func mockCreationEvents(cloudTrailMock *mocks.MockCloudTrail, olderthen time.Duration, creationTimes map[string]time.Time) {
+	events := []cloudtrailTypes.Event{}
+	for ifaceID, creationTime := range creationTimes {
+		events = append(events, cloudtrailTypes.Event{
+			EventName: aws.String("CreateNetworkInterface"),
+			EventTime: aws.Time(creationTime),
+			Username:  aws.String("creator"),
+			Resources: []cloudtrailTypes.Resource{{ResourceName: aws.String(ifaceID), ResourceType: aws.String(internal.NETWORKINTERFACE_RESOURCE_TYPE)}},
+		})
+	}
+
+	cloudTrailMock.EXPECT().LookupEvents(context.TODO(), mock.MatchedBy(func(in *cloudtrail.LookupEventsInput) bool {
+		return aws.ToString(in.LookupAttributes[0].AttributeValue) == "CreateNetworkInterface" &&
+			in.EndTime.Sub(*in.StartTime) == olderthen
+	})).Return(&cloudtrail.LookupEventsOutput{Events: events}, nil).Once()
+}
+
+func TestGetNetworkInterfaces(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, ec2Mock, cloudTrailMock := setupSUT(t, "7d", false, false)
+
+		createdAt := time.Now().Add(-time.Hour)
+		mockDescribeNetworkInterfaces(ec2Mock, attached, managed, orphaned)
+		mockCreationEvents(cloudTrailMock, 7*24*time.Hour, map[string]time.Time{"eni-orphaned": createdAt})
+
+		err := SUT.GetNetworkInterfaces()
+		require.NoError(t, err)
+		assert.Len(t, SUT.usedIfaces, 2)
+		require.Len(t, SUT.unusedIfaces, 1)
+		assert.Equal(t, "eni-orphaned", *SUT.unusedIfaces[0].NetworkInterfaceId)
+		assert.Equal(t, createdAt, *SUT.unusedIfaces[0].CreationTime)
+		assert.Equal(t, "creator", SUT.unusedIfaces[0].Creator)
+		assert.Len(t, SUT.GetAllNetworkInterfaces(), 3)
+	})
+
+	t.Run("Only Unused", func(t *testing.T) {
+		SUT, ec2Mock, cloudTrailMock := setupSUT(t, "7d", false, true)
+
+		mockDescribeNetworkInterfaces(ec2Mock, attached, orphaned)
+		mockCreationEvents(cloudTrailMock, 7*24*time.Hour, nil)
+
+		err := SUT.GetNetworkInterfaces()
+		require.NoError(t, err)
+		assert.Len(t, SUT.GetAllNetworkInterfaces(), 1)
+	})
+
+	t.Run("Error DescribeNetworkInterfaces", func(t *testing.T) {
+		SUT, ec2Mock, _ := setupSUT(t, "7d", false, false)
+
+		ec2Mock.EXPECT().DescribeNetworkInterfaces(context.TODO(), &ec2.DescribeNetworkInterfacesInput{}).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.GetNetworkInterfaces()
+		require.EqualError(t, err, "could not get network interfaces: some error")
+	})
+}
+
+func TestDeleteOrphanedNetworkInterfaces(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dryrun := true
+		SUT, ec2Mock, cloudTrailMock := setupSUT(t, "7d", dryrun, true)
+
+		mockDescribeNetworkInterfaces(ec2Mock, attached, managed, orphaned, recent)
+		mockCreationEvents(cloudTrailMock, 7*24*time.Hour, map[string]time.Time{"eni-recent": time.Now().Add(-time.Hour)})
+		ec2Mock.EXPECT().DeleteNetworkInterface(context.TODO(), &ec2.DeleteNetworkInterfaceInput{
+			NetworkInterfaceId: aws.String("eni-orphaned"),
+			DryRun:             aws.Bool(dryrun),
+		}).Return(nil, &smithy.GenericAPIError{Code: "DryRunOperation"}).Once()
+
+		err := SUT.DeleteOrphanedNetworkInterfaces()
+		require.NoError(t, err)
+	})
+
+	t.Run("Unknown Creation Time Beyond CloudTrail", func(t *testing.T) {
+		SUT, ec2Mock, cloudTrailMock := setupSUT(t, "120d", false, true)
+
+		mockDescribeNetworkInterfaces(ec2Mock, orphaned)
+		mockCreationEvents(cloudTrailMock, internal.CLOUDTRAIL_RETENTION, nil)
+
+		err := SUT.DeleteOrphanedNetworkInterfaces()
+		require.NoError(t, err)
+		ec2Mock.AssertNotCalled(t, "DeleteNetworkInterface", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error CloudTrail", func(t *testing.T) {
+		SUT, ec2Mock, cloudTrailMock := setupSUT(t, "7d", false, true)
+
+		mockDescribeNetworkInterfaces(ec2Mock, orphaned)
+		cloudTrailMock.EXPECT().LookupEvents(context.TODO(), mock.Anything).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.DeleteOrphanedNetworkInterfaces()
+		require.ErrorContains(t, err, "could not get CloudTrail events of network interfaces")
+		ec2Mock.AssertNotCalled(t, "DeleteNetworkInterface", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,255 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	autoscalingTypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/steffakasid/eslog"
+)
+
+// LaunchTplVersions defines which launch template versions are scanned for used AMIs.
+type LaunchTplVersions string
+
+const (
+	LAUNCH_TPL_LATEST  LaunchTplVersions = "latest"
+	LAUNCH_TPL_DEFAULT LaunchTplVersions = "default"
+	LAUNCH_TPL_ALL     LaunchTplVersions = "all"
+	// LAUNCH_TPL_REFERENCED scans the versions which are actually launched: the $Default version of each
+	// launch template and the versions instances were launched from. The versions pinned by Auto Scaling
+	// groups are scanned in every mode.
+	LAUNCH_TPL_REFERENCED LaunchTplVersions = "referenced"
+)
+
+var launchTplVersionModes = []LaunchTplVersions{LAUNCH_TPL_LATEST, LAUNCH_TPL_DEFAULT, LAUNCH_TPL_ALL, LAUNCH_TPL_REFERENCED}
+
+// ParseLaunchTplVersions parses the mode given by the user. An empty string or false disables the scan of
+// launch templates. For backward compatibility true selects LAUNCH_TPL_LATEST.
+func ParseLaunchTplVersions(mode string) (versions LaunchTplVersions, enabled bool, err error) {
+	switch strings.ToLower(mode) {
+	case "", "false":
+		return "", false, nil
+	case "true":
+		return LAUNCH_TPL_LATEST, true, nil
+	}
+
+	for _, versions := range launchTplVersionModes {
+		if strings.EqualFold(mode, string(versions)) {
+			return versions, true, nil
+		}
+	}
+	return "", false, fmt.Errorf("unknown launch template versions %q, must be one of %v", mode, launchTplVersionModes)
+}
+
+// AMIUsage maps the ID of an AMI to a description of the resource which uses it.
+type AMIUsage map[string]string
+
+// Add records that the AMI is used by usedBy. The first recorded usage of an AMI is kept.
+func (u AMIUsage) Add(imageId, usedBy string) {
+	if _, exists := u[imageId]; !exists {
+		u[imageId] = usedBy
+	}
+}
+
+// Merge adds all usages of other which are not already recorded.
+func (u AMIUsage) Merge(other AMIUsage) {
+	for imageId, usedBy := range other {
+		u.Add(imageId, usedBy)
+	}
+}
+
+// LAUNCH_TPL_ID_TAG and LAUNCH_TPL_VERSION_TAG are set by AWS on instances launched from a launch template.
+const (
+	LAUNCH_TPL_ID_TAG      = "aws:ec2launchtemplate:id"
+	LAUNCH_TPL_VERSION_TAG = "aws:ec2launchtemplate:version"
+)
+
+// DeleteLaunchTemplateVersions accepts at most 200 versions per call.
+const maxLaunchTplVersionsPerDelete = 200
+
+// GetLaunchTemplates returns all launch templates.
+func (a AWS) GetLaunchTemplates() ([]ec2Types.LaunchTemplate, error) {
+	launchTpls := []ec2Types.LaunchTemplate{}
+	in := &ec2.DescribeLaunchTemplatesInput{}
+	for {
+		out, err := a.ec2.DescribeLaunchTemplates(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		launchTpls = append(launchTpls, out.LaunchTemplates...)
+
+		if out.NextToken == nil {
+			return launchTpls, nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// GetLaunchTemplateVersions returns all versions of the launch template.
+func (a AWS) GetLaunchTemplateVersions(launchTplId string) ([]ec2Types.LaunchTemplateVersion, error) {
+	versions := []ec2Types.LaunchTemplateVersion{}
+	in := &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: &launchTplId}
+	for {
+		out, err := a.ec2.DescribeLaunchTemplateVersions(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, out.LaunchTemplateVersions...)
+
+		if out.NextToken == nil {
+			return versions, nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// GetLaunchTplsOfAutoScalingGroups returns the launch templates referenced by each Auto Scaling group by the
+// group name. It's empty if no Auto Scaling client is set.
+func (a AWS) GetLaunchTplsOfAutoScalingGroups() (map[string][]autoscalingTypes.LaunchTemplateSpecification, error) {
+	launchTpls := map[string][]autoscalingTypes.LaunchTemplateSpecification{}
+	if a.autoscaling == nil {
+		return launchTpls, nil
+	}
+	var nextToken *string
+	for {
+		out, err := a.autoscaling.DescribeAutoScalingGroups(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{NextToken: nextToken})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range out.AutoScalingGroups {
+			if groupLaunchTpls := launchTplsOfAutoScalingGroup(group); len(groupLaunchTpls) > 0 {
+				launchTpls[aws.ToString(group.AutoScalingGroupName)] = groupLaunchTpls
+			}
+		}
+
+		if out.NextToken == nil {
+			return launchTpls, nil
+		}
+		nextToken = out.NextToken
+	}
+}
+
+// LaunchTplInstance is an instance launched from a launch template.
+type LaunchTplInstance struct {
+	InstanceId string
+	// the launch template version the instance was launched from, empty if unknown
+	Version string
+}
+
+// GetInstancesOfLaunchTpls returns all not terminated instances launched from a launch template by the launch
+// template ID.
+func (a AWS) GetInstancesOfLaunchTpls() (map[string][]LaunchTplInstance, error) {
+	instances := map[string][]LaunchTplInstance{}
+	err := a.forEachInstanceOfLaunchTpls(func(instance ec2Types.Instance) {
+		if launchTplId := tagValue(instance.Tags, LAUNCH_TPL_ID_TAG); launchTplId != "" {
+			instances[launchTplId] = append(instances[launchTplId], LaunchTplInstance{
+				InstanceId: aws.ToString(instance.InstanceId),
+				Version:    tagValue(instance.Tags, LAUNCH_TPL_VERSION_TAG),
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
+// GetLaunchTplVersionsOfInstances returns the versions all not terminated instances were launched from by the
+// launch template ID.
+func (a AWS) GetLaunchTplVersionsOfInstances() (map[string][]string, error) {
+	versions := map[string][]string{}
+	err := a.forEachInstanceOfLaunchTpls(func(instance ec2Types.Instance) {
+		launchTplId := tagValue(instance.Tags, LAUNCH_TPL_ID_TAG)
+		version := tagValue(instance.Tags, LAUNCH_TPL_VERSION_TAG)
+		if launchTplId != "" && version != "" {
+			versions[launchTplId] = UniqueAppend(versions[launchTplId], version)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// forEachInstanceOfLaunchTpls calls fn for all not terminated instances launched from a launch template.
+func (a AWS) forEachInstanceOfLaunchTpls(fn func(ec2Types.Instance)) error {
+	in := &ec2.DescribeInstancesInput{
+		Filters: []ec2Types.Filter{
+			{Name: aws.String("tag-key"), Values: []string{LAUNCH_TPL_ID_TAG}},
+			{Name: aws.String("instance-state-name"), Values: []string{"pending", "running", "shutting-down", "stopping", "stopped"}},
+		},
+	}
+	for {
+		out, err := a.ec2.DescribeInstances(context.TODO(), in)
+		if err != nil {
+			return err
+		}
+
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				fn(instance)
+			}
+		}
+
+		if out.NextToken == nil {
+			return nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+func tagValue(tags []ec2Types.Tag, key string) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == key {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
+
+// DeleteLaunchTemplateVersions deletes the given versions of the launch template. The versions which could
+// not be deleted are returned as error.
+func (a AWS) DeleteLaunchTemplateVersions(launchTplId string, versions []int64, dryrun bool) error {
+	eslog.Logger.Debugf("DeleteLaunchTemplateVersions(%s, %v), dryrun: %t", launchTplId, versions, dryrun)
+
+	errs := []error{}
+	for start := 0; start < len(versions); start += maxLaunchTplVersionsPerDelete {
+		opts := &ec2.DeleteLaunchTemplateVersionsInput{
+			LaunchTemplateId: &launchTplId,
+			DryRun:           &dryrun,
+		}
+		for _, version := range versions[start:min(start+maxLaunchTplVersionsPerDelete, len(versions))] {
+			opts.Versions = append(opts.Versions, strconv.FormatInt(version, 10))
+		}
+
+		out, err := a.ec2.DeleteLaunchTemplateVersions(context.TODO(), opts)
+		if err != nil {
+			return err
+		}
+		for _, failed := range out.UnsuccessfullyDeletedLaunchTemplateVersions {
+			if failed.ResponseError != nil {
+				errs = append(errs, fmt.Errorf("could not delete version %d: %s %s", aws.ToInt64(failed.VersionNumber), failed.ResponseError.Code, aws.ToString(failed.ResponseError.Message)))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a AWS) DeleteLaunchTemplate(launchTplId string, dryrun bool) error {
+	eslog.Logger.Debugf("DeleteLaunchTemplate(%s), dryrun: %t", launchTplId, dryrun)
+
+	opts := &ec2.DeleteLaunchTemplateInput{
+		LaunchTemplateId: &launchTplId,
+		DryRun:           &dryrun,
+	}
+	_, err := a.ec2.DeleteLaunchTemplate(context.TODO(), opts)
+	return err
+}
@@ -0,0 +1,232 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	autoscalingTypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLaunchTplVersions(t *testing.T) {
+	tests := []struct {
+		mode     string
+		versions LaunchTplVersions
+		enabled  bool
+	}{
+		{mode: "", versions: "", enabled: false},
+		{mode: "false", versions: "", enabled: false},
+		{mode: "true", versions: LAUNCH_TPL_LATEST, enabled: true},
+		{mode: "latest", versions: LAUNCH_TPL_LATEST, enabled: true},
+		{mode: "Default", versions: LAUNCH_TPL_DEFAULT, enabled: true},
+		{mode: "all", versions: LAUNCH_TPL_ALL, enabled: true},
+		{mode: "referenced", versions: LAUNCH_TPL_REFERENCED, enabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			versions, enabled, err := ParseLaunchTplVersions(tt.mode)
+			require.NoError(t, err)
+			assert.Equal(t, tt.versions, versions)
+			assert.Equal(t, tt.enabled, enabled)
+		})
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, _, err := ParseLaunchTplVersions("newest")
+		require.Error(t, err)
+	})
+}
+
+func TestAMIUsage(t *testing.T) {
+	usage := AMIUsage{}
+	usage.Add("ami-1", "EC2 instance")
+	usage.Add("ami-1", "launch template")
+	usage.Merge(AMIUsage{"ami-1": "launch configuration", "ami-2": "launch configuration"})
+
+	assert.Equal(t, AMIUsage{"ami-1": "EC2 instance", "ami-2": "launch configuration"}, usage)
+}
+
+func TestGetLaunchTemplates(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, ec2Mock, _ := setupSUT(t)
+		ec2Mock.EXPECT().DescribeLaunchTemplates(context.TODO(), &ec2.DescribeLaunchTemplatesInput{}).Return(&ec2.DescribeLaunchTemplatesOutput{
+			LaunchTemplates: []types.LaunchTemplate{{LaunchTemplateId: aws.String("lt-1")}},
+			NextToken:       aws.String("next"),
+		}, nil).Once()
+		ec2Mock.EXPECT().DescribeLaunchTemplates(context.TODO(), &ec2.DescribeLaunchTemplatesInput{NextToken: aws.String("next")}).Return(&ec2.DescribeLaunchTemplatesOutput{
+			LaunchTemplates: []types.LaunchTemplate{{LaunchTemplateId: aws.String("lt-2")}},
+		}, nil).Once()
+
+		launchTpls, err := SUT.GetLaunchTemplates()
+		require.NoError(t, err)
+		require.Len(t, launchTpls, 2)
+		assert.Equal(t, "lt-2", *launchTpls[1].LaunchTemplateId)
+	})
+
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, ec2Mock, _ := setupSUT(t)
+		ec2Mock.EXPECT().DescribeLaunchTemplates(context.TODO(), &ec2.DescribeLaunchTemplatesInput{}).Return(nil, errors.New("Something went wrong")).Once()
+
+		_, err := SUT.GetLaunchTemplates()
+		require.EqualError(t, err, "Something went wrong")
+	})
+}
+
+func TestGetLaunchTemplateVersions(t *testing.T) {
+	SUT, ec2Mock, _ := setupSUT(t)
+	ec2Mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String("lt-1")}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+		LaunchTemplateVersions: []types.LaunchTemplateVersion{{VersionNumber: aws.Int64(1)}},
+		NextToken:              aws.String("next"),
+	}, nil).Once()
+	ec2Mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String("lt-1"), NextToken: aws.String("next")}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+		LaunchTemplateVersions: []types.LaunchTemplateVersion{{VersionNumber: aws.Int64(2)}},
+	}, nil).Once()
+
+	versions, err := SUT.GetLaunchTemplateVersions("lt-1")
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+}
+
+func TestGetLaunchTplsOfAutoScalingGroups(t *testing.T) {
+	SUT, _, _ := setupSUT(t)
+	autoScalingMock := mocks.NewMockAutoScaling(t)
+	WithAutoScaling(autoScalingMock)(SUT)
+
+	autoScalingMock.EXPECT().DescribeAutoScalingGroups(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{}).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+		AutoScalingGroups: []autoscalingTypes.AutoScalingGroup{
+			{
+				AutoScalingGroupName: aws.String("direct"),
+				LaunchTemplate:       &autoscalingTypes.LaunchTemplateSpecification{LaunchTemplateId: aws.String("lt-1"), Version: aws.String("3")},
+			},
+			{
+				AutoScalingGroupName: aws.String("mixed"),
+				MixedInstancesPolicy: &autoscalingTypes.MixedInstancesPolicy{
+					LaunchTemplate: &autoscalingTypes.LaunchTemplate{
+						LaunchTemplateSpecification: &autoscalingTypes.LaunchTemplateSpecification{LaunchTemplateName: aws.String("tpl-2")},
+						Overrides: []autoscalingTypes.LaunchTemplateOverrides{
+							{LaunchTemplateSpecification: &autoscalingTypes.LaunchTemplateSpecification{LaunchTemplateId: aws.String("lt-3"), Version: aws.String("$Latest")}},
+						},
+					},
+				},
+			},
+			{AutoScalingGroupName: aws.String("launch-config"), LaunchConfigurationName: aws.String("lc-1")},
+		},
+	}, nil).Once()
+
+	launchTpls, err := SUT.GetLaunchTplsOfAutoScalingGroups()
+	require.NoError(t, err)
+	require.Len(t, launchTpls, 2)
+	assert.Equal(t, "3", *launchTpls["direct"][0].Version)
+	require.Len(t, launchTpls["mixed"], 2)
+	assert.Equal(t, "tpl-2", *launchTpls["mixed"][0].LaunchTemplateName)
+	assert.Equal(t, "lt-3", *launchTpls["mixed"][1].LaunchTemplateId)
+}
+
+func TestGetLaunchTplsOfAutoScalingGroupsWithoutClient(t *testing.T) {
+	SUT, _, _ := setupSUT(t)
+
+	launchTpls, err := SUT.GetLaunchTplsOfAutoScalingGroups()
+	require.NoError(t, err)
+	assert.Empty(t, launchTpls)
+}
+
+func TestGetInstancesOfLaunchTpls(t *testing.T) {
+	SUT, ec2Mock, _ := setupSUT(t)
+	ec2Mock.EXPECT().DescribeInstances(context.TODO(), mock.MatchedBy(func(in *ec2.DescribeInstancesInput) bool {
+		return aws.ToString(in.Filters[0].Name) == "tag-key" && in.Filters[0].Values[0] == LAUNCH_TPL_ID_TAG
+	})).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []types.Reservation{{
+			Instances: []types.Instance{
+				{InstanceId: aws.String("i-1"), Tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String("web")}, {Key: aws.String(LAUNCH_TPL_ID_TAG), Value: aws.String("lt-1")}}},
+				{InstanceId: aws.String("i-2"), Tags: []types.Tag{{Key: aws.String(LAUNCH_TPL_ID_TAG), Value: aws.String("lt-1")}, {Key: aws.String(LAUNCH_TPL_VERSION_TAG), Value: aws.String("2")}}},
+			},
+		}},
+	}, nil).Once()
+
+	instances, err := SUT.GetInstancesOfLaunchTpls()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]LaunchTplInstance{"lt-1": {{InstanceId: "i-1"}, {InstanceId: "i-2", Version: "2"}}}, instances)
+}
+
+func TestGetLaunchTplVersionsOfInstances(t *testing.T) {
+	SUT, ec2Mock, _ := setupSUT(t)
+	ec2Mock.EXPECT().DescribeInstances(context.TODO(), mock.Anything).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []types.Reservation{{
+			Instances: []types.Instance{
+				{InstanceId: aws.String("i-1"), Tags: []types.Tag{{Key: aws.String(LAUNCH_TPL_ID_TAG), Value: aws.String("lt-1")}, {Key: aws.String(LAUNCH_TPL_VERSION_TAG), Value: aws.String("3")}}},
+				{InstanceId: aws.String("i-2"), Tags: []types.Tag{{Key: aws.String(LAUNCH_TPL_ID_TAG), Value: aws.String("lt-1")}, {Key: aws.String(LAUNCH_TPL_VERSION_TAG), Value: aws.String("3")}}},
+				{InstanceId: aws.String("i-3"), Tags: []types.Tag{{Key: aws.String(LAUNCH_TPL_ID_TAG), Value: aws.String("lt-1")}, {Key: aws.String(LAUNCH_TPL_VERSION_TAG), Value: aws.String("4")}}},
+			},
+		}},
+		NextToken: aws.String("next"),
+	}, nil).Once()
+	ec2Mock.EXPECT().DescribeInstances(context.TODO(), mock.MatchedBy(func(in *ec2.DescribeInstancesInput) bool {
+		return aws.ToString(in.NextToken) == "next"
+	})).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []types.Reservation{{
+			Instances: []types.Instance{
+				{InstanceId: aws.String("i-4"), Tags: []types.Tag{{Key: aws.String(LAUNCH_TPL_ID_TAG), Value: aws.String("lt-2")}, {Key: aws.String(LAUNCH_TPL_VERSION_TAG), Value: aws.String("1")}}},
+			},
+		}},
+	}, nil).Once()
+
+	versions, err := SUT.GetLaunchTplVersionsOfInstances()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"lt-1": {"3", "4"}, "lt-2": {"1"}}, versions)
+}
+
+func TestDeleteLaunchTemplateVersions(t *testing.T) {
+	t.Run("In Batches", func(t *testing.T) {
+		SUT, ec2Mock, _ := setupSUT(t)
+
+		versions := []int64{}
+		for version := int64(1); version <= 250; version++ {
+			versions = append(versions, version)
+		}
+		ec2Mock.EXPECT().DeleteLaunchTemplateVersions(context.TODO(), mock.MatchedBy(func(in *ec2.DeleteLaunchTemplateVersionsInput) bool {
+			return len(in.Versions) == 200 && in.Versions[0] == "1" && !*in.DryRun
+		})).Return(&ec2.DeleteLaunchTemplateVersionsOutput{}, nil).Once()
+		ec2Mock.EXPECT().DeleteLaunchTemplateVersions(context.TODO(), mock.MatchedBy(func(in *ec2.DeleteLaunchTemplateVersionsInput) bool {
+			return len(in.Versions) == 50 && in.Versions[0] == "201"
+		})).Return(&ec2.DeleteLaunchTemplateVersionsOutput{}, nil).Once()
+
+		err := SUT.DeleteLaunchTemplateVersions("lt-1", versions, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("Unsuccessfully Deleted", func(t *testing.T) {
+		SUT, ec2Mock, _ := setupSUT(t)
+		ec2Mock.EXPECT().DeleteLaunchTemplateVersions(context.TODO(), &ec2.DeleteLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("lt-1"),
+			Versions:         []string{"1", "2"},
+			DryRun:           aws.Bool(false),
+		}).Return(&ec2.DeleteLaunchTemplateVersionsOutput{
+			UnsuccessfullyDeletedLaunchTemplateVersions: []types.DeleteLaunchTemplateVersionsResponseErrorItem{
+				{VersionNumber: aws.Int64(2), ResponseError: &types.ResponseError{Code: types.LaunchTemplateErrorCodeLaunchTemplateVersionDoesNotExist, Message: aws.String("not found")}},
+			},
+		}, nil).Once()
+
+		err := SUT.DeleteLaunchTemplateVersions("lt-1", []int64{1, 2}, false)
+		require.EqualError(t, err, fmt.Sprintf("could not delete version 2: %s not found", types.LaunchTemplateErrorCodeLaunchTemplateVersionDoesNotExist))
+	})
+}
+
+func TestDeleteLaunchTemplate(t *testing.T) {
+	SUT, ec2Mock, _ := setupSUT(t)
+	ec2Mock.EXPECT().DeleteLaunchTemplate(context.TODO(), &ec2.DeleteLaunchTemplateInput{
+		LaunchTemplateId: aws.String("lt-1"),
+		DryRun:           aws.Bool(true),
+	}).Return(nil, errors.New("Something went wrong")).Once()
+
+	err := SUT.DeleteLaunchTemplate("lt-1", true)
+	require.EqualError(t, err, "Something went wrong")
+}
@@ -0,0 +1,191 @@
+package launchtplclean
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/steffakasid/awsclean/internal"
+	eslog "github.com/steffakasid/eslog"
+)
+
+// LaunchTemplate adds the versions and the users of a launch template.
+type LaunchTemplate struct {
+	types.LaunchTemplate
+	// the Auto Scaling groups and instances which use the launch template
+	UsedBy []string
+	// $Default, $Latest, the newest versions, the versions referenced by Auto Scaling groups or instances were
+	// launched from and the versions created after olderthen
+	KeptVersions []int64
+	// all other versions
+	PrunableVersions []int64
+	// the creation time of the newest version
+	LastVersionTime *time.Time
+}
+
+type LaunchTplClean struct {
+	awsClient       *internal.AWS
+	olderthen       time.Duration
+	keepNewest      int
+	dryrun          bool
+	launchTemplates []LaunchTemplate
+}
+
+func NewInstance(awsClient *internal.AWS, olderthen time.Duration, keepNewest int, dryrun bool) *LaunchTplClean {
+	return &LaunchTplClean{
+		awsClient:       awsClient,
+		olderthen:       olderthen,
+		keepNewest:      keepNewest,
+		dryrun:          dryrun,
+		launchTemplates: []LaunchTemplate{},
+	}
+}
+
+// GetLaunchTemplates fetches all launch templates with their versions and sorts the versions into kept and
+// prunable ones. A launch template is used if it's referenced by an Auto Scaling group or an instance was
+// launched from it.
+func (l *LaunchTplClean) GetLaunchTemplates() error {
+	launchTpls, err := l.awsClient.GetLaunchTemplates()
+	if err != nil {
+		return fmt.Errorf("could not get launch templates: %w", err)
+	}
+	groupLaunchTpls, err := l.awsClient.GetLaunchTplsOfAutoScalingGroups()
+	if err != nil {
+		return fmt.Errorf("could not get launch templates of Auto Scaling groups: %w", err)
+	}
+	instances, err := l.awsClient.GetInstancesOfLaunchTpls()
+	if err != nil {
+		return fmt.Errorf("could not get instances of launch templates: %w", err)
+	}
+
+	launchTplIds := map[string]string{}
+	for _, launchTpl := range launchTpls {
+		launchTplIds[aws.ToString(launchTpl.LaunchTemplateName)] = aws.ToString(launchTpl.LaunchTemplateId)
+	}
+
+	usedBy := map[string][]string{}
+	referencedVersions := map[string][]string{}
+	for groupName, specs := range groupLaunchTpls {
+		for _, spec := range specs {
+			launchTplId := aws.ToString(spec.LaunchTemplateId)
+			if launchTplId == "" {
+				launchTplId = launchTplIds[aws.ToString(spec.LaunchTemplateName)]
+			}
+			usedBy[launchTplId] = internal.UniqueAppend(usedBy[launchTplId], fmt.Sprintf("Auto Scaling group %s", groupName))
+			referencedVersions[launchTplId] = internal.UniqueAppend(referencedVersions[launchTplId], aws.ToString(spec.Version))
+		}
+	}
+	for launchTplId, launchTplInstances := range instances {
+		for _, instance := range launchTplInstances {
+			usedBy[launchTplId] = append(usedBy[launchTplId], fmt.Sprintf("instance %s", instance.InstanceId))
+			referencedVersions[launchTplId] = internal.UniqueAppend(referencedVersions[launchTplId], instance.Version)
+		}
+	}
+
+	olderThenDate := time.Now().Add(l.olderthen * -1)
+	for _, launchTpl := range launchTpls {
+		launchTplId := aws.ToString(launchTpl.LaunchTemplateId)
+		versions, err := l.awsClient.GetLaunchTemplateVersions(launchTplId)
+		if err != nil {
+			return fmt.Errorf("could not get versions of launch template %s: %w", aws.ToString(launchTpl.LaunchTemplateName), err)
+		}
+
+		lt := LaunchTemplate{LaunchTemplate: launchTpl, UsedBy: usedBy[launchTplId], KeptVersions: []int64{}, PrunableVersions: []int64{}}
+		keep := l.versionsToKeep(launchTpl, referencedVersions[launchTplId])
+
+		// newest versions first
+		slices.SortFunc(versions, func(a, b types.LaunchTemplateVersion) int {
+			return int(aws.ToInt64(b.VersionNumber) - aws.ToInt64(a.VersionNumber))
+		})
+		for i, version := range versions {
+			versionNumber := aws.ToInt64(version.VersionNumber)
+			if lt.LastVersionTime == nil || (version.CreateTime != nil && version.CreateTime.After(*lt.LastVersionTime)) {
+				lt.LastVersionTime = version.CreateTime
+			}
+
+			if i < l.keepNewest || slices.Contains(keep, versionNumber) ||
+				version.CreateTime == nil || !version.CreateTime.Before(olderThenDate) {
+				lt.KeptVersions = append(lt.KeptVersions, versionNumber)
+			} else {
+				lt.PrunableVersions = append(lt.PrunableVersions, versionNumber)
+			}
+		}
+		l.launchTemplates = append(l.launchTemplates, lt)
+	}
+	return nil
+}
+
+// versionsToKeep returns the $Default and $Latest version and the versions referenced by Auto Scaling groups or
+// instances were launched from. Groups without version use the $Default version.
+func (l LaunchTplClean) versionsToKeep(launchTpl types.LaunchTemplate, referenced []string) []int64 {
+	keep := []int64{aws.ToInt64(launchTpl.DefaultVersionNumber), aws.ToInt64(launchTpl.LatestVersionNumber)}
+	for _, version := range referenced {
+		switch version {
+		case "", "$Default", "$Latest":
+			continue
+		}
+		versionNumber, err := strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			eslog.Logger.Warnf("Unknown version %s of launch template %s", version, aws.ToString(launchTpl.LaunchTemplateName))
+			continue
+		}
+		keep = append(keep, versionNumber)
+	}
+	return keep
+}
+
+func (l LaunchTplClean) GetAllLaunchTemplates() []LaunchTemplate {
+	return l.launchTemplates
+}
+
+// Prune deletes the prunable versions of all used launch templates. Launch templates which are not used
+// by any Auto Scaling group or instance are deleted as a whole if their newest version was created before
+// olderthen.
+func (l *LaunchTplClean) Prune() error {
+	err := l.GetLaunchTemplates()
+	if err != nil {
+		return err
+	}
+
+	deletedTpls := 0
+	deletedVersions := 0
+	skipped := 0
+
+	olderThenDate := time.Now().Add(l.olderthen * -1)
+	eslog.Logger.Debugf("OlderThenDate %v", olderThenDate)
+
+	for _, launchTpl := range l.launchTemplates {
+		name := aws.ToString(launchTpl.LaunchTemplateName)
+		launchTplId := aws.ToString(launchTpl.LaunchTemplateId)
+
+		if len(launchTpl.UsedBy) == 0 && launchTpl.LastVersionTime != nil && launchTpl.LastVersionTime.Before(olderThenDate) {
+			eslog.Logger.Infof("Delete unused launch template %s (%s)", name, launchTplId)
+			err := l.awsClient.DeleteLaunchTemplate(launchTplId, l.dryrun)
+			if err != nil && !internal.IsDryRunOperation(err) {
+				eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeleteLaunchTemplate(): %s")
+				skipped++
+				continue
+			}
+			deletedTpls++
+			continue
+		}
+
+		if len(launchTpl.PrunableVersions) == 0 {
+			continue
+		}
+		eslog.Logger.Infof("Delete %d versions of launch template %s (%s), keeping %v", len(launchTpl.PrunableVersions), name, launchTplId, launchTpl.KeptVersions)
+		err := l.awsClient.DeleteLaunchTemplateVersions(launchTplId, launchTpl.PrunableVersions, l.dryrun)
+		if err != nil && !internal.IsDryRunOperation(err) {
+			eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeleteLaunchTemplateVersions(): %s")
+			skipped++
+			continue
+		}
+		deletedVersions += len(launchTpl.PrunableVersions)
+	}
+
+	eslog.Logger.Infof("Deleted %d launch templates and %d versions, Skipped %d launch templates", deletedTpls, deletedVersions, skipped)
+	return nil
+}
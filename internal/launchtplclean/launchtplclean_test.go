@@ -0,0 +1,179 @@
+package launchtplclean
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	autoscalingTypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+func setupSUT(t *testing.T, olderthen string, keepNewest int, dryrun bool) (*LaunchTplClean, *mocks.MockEc2client, *mocks.MockAutoScaling) {
+	olderthenDuration, err := str2duration.ParseDuration(olderthen)
+	require.NoError(t, err)
+
+	ec2ClientMock := mocks.NewMockEc2client(t)
+	autoScalingMock := mocks.NewMockAutoScaling(t)
+	awsClient := internal.NewFromInterface(ec2ClientMock, mocks.NewMockCloudTrail(t), internal.WithAutoScaling(autoScalingMock))
+	return NewInstance(awsClient, olderthenDuration, keepNewest, dryrun), ec2ClientMock, autoScalingMock
+}
+
+type launchTplWithVersions struct {
+	types.LaunchTemplate
+	versions []types.LaunchTemplateVersion
+}
+
+// launchTpl returns a launch template with the given number of versions. The versions were created one day
+// after another, the latest one today.
+func launchTpl(id, name string, versions, defaultVersion int64) launchTplWithVersions {
+	launchTplVersions := []types.LaunchTemplateVersion{}
+	for version := int64(1); version <= versions; version++ {
+		launchTplVersions = append(launchTplVersions, types.LaunchTemplateVersion{
+			LaunchTemplateId: aws.String(id),
+			VersionNumber:    aws.Int64(version),
+			CreateTime:       aws.Time(time.Now().Add(time.Duration(version-versions) * 24 * time.Hour)),
+		})
+	}
+	return launchTplWithVersions{
+		LaunchTemplate: types.LaunchTemplate{
+			LaunchTemplateId:     aws.String(id),
+			LaunchTemplateName:   aws.String(name),
+			DefaultVersionNumber: aws.Int64(defaultVersion),
+			LatestVersionNumber:  aws.Int64(versions),
+		},
+		versions: launchTplVersions,
+	}
+}
+
+func mockLaunchTemplates(ec2Mock *mocks.MockEc2client, autoScalingMock *mocks.MockAutoScaling, groups []autoscalingTypes.AutoScalingGroup, instances []types.Instance, launchTpls ...launchTplWithVersions) {
+	tpls := []types.LaunchTemplate{}
+	for _, launchTpl := range launchTpls {
+		tpls = append(tpls, launchTpl.LaunchTemplate)
+		ec2Mock.EXPECT().DescribeLaunchTemplateVersions(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: launchTpl.LaunchTemplateId}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: launchTpl.versions,
+		}, nil).Once()
+	}
+	ec2Mock.EXPECT().DescribeLaunchTemplates(context.TODO(), &ec2.DescribeLaunchTemplatesInput{}).Return(&ec2.DescribeLaunchTemplatesOutput{
+		LaunchTemplates: tpls,
+	}, nil).Once()
+	autoScalingMock.EXPECT().DescribeAutoScalingGroups(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{}).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+		AutoScalingGroups: groups,
+	}, nil).Once()
+	ec2Mock.EXPECT().DescribeInstances(context.TODO(), mock.Anything).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []types.Reservation{{Instances: instances}},
+	}, nil).Once()
+}
+
+func TestGetLaunchTemplates(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, ec2Mock, autoScalingMock := setupSUT(t, "60h", 2, false)
+
+		tpl := launchTpl("lt-1", "web", 10, 1)
+		groups := []autoscalingTypes.AutoScalingGroup{
+			{AutoScalingGroupName: aws.String("web"), LaunchTemplate: &autoscalingTypes.LaunchTemplateSpecification{LaunchTemplateName: aws.String("web"), Version: aws.String("4")}},
+		}
+		mockLaunchTemplates(ec2Mock, autoScalingMock, groups, nil, tpl)
+
+		err := SUT.GetLaunchTemplates()
+		require.NoError(t, err)
+		launchTpls := SUT.GetAllLaunchTemplates()
+		require.Len(t, launchTpls, 1)
+		assert.Equal(t, []string{"Auto Scaling group web"}, launchTpls[0].UsedBy)
+		// 10 and 9 are the newest, 8 was created within 60h, 4 is referenced and 1 is the default
+		assert.Equal(t, []int64{10, 9, 8, 4, 1}, launchTpls[0].KeptVersions)
+		assert.Equal(t, []int64{7, 6, 5, 3, 2}, launchTpls[0].PrunableVersions)
+		assert.Equal(t, tpl.versions[9].CreateTime, launchTpls[0].LastVersionTime)
+	})
+
+	t.Run("Used By Instance", func(t *testing.T) {
+		SUT, ec2Mock, autoScalingMock := setupSUT(t, "60h", 2, false)
+
+		tpl := launchTpl("lt-1", "web", 1, 1)
+		instances := []types.Instance{{InstanceId: aws.String("i-1"), Tags: []types.Tag{{Key: aws.String(internal.LAUNCH_TPL_ID_TAG), Value: aws.String("lt-1")}}}}
+		mockLaunchTemplates(ec2Mock, autoScalingMock, nil, instances, tpl)
+
+		err := SUT.GetLaunchTemplates()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"instance i-1"}, SUT.GetAllLaunchTemplates()[0].UsedBy)
+	})
+
+	t.Run("Version Of Instance", func(t *testing.T) {
+		SUT, ec2Mock, autoScalingMock := setupSUT(t, "60h", 2, false)
+
+		tpl := launchTpl("lt-1", "web", 10, 1)
+		instances := []types.Instance{{InstanceId: aws.String("i-1"), Tags: []types.Tag{
+			{Key: aws.String(internal.LAUNCH_TPL_ID_TAG), Value: aws.String("lt-1")},
+			{Key: aws.String(internal.LAUNCH_TPL_VERSION_TAG), Value: aws.String("5")},
+		}}}
+		mockLaunchTemplates(ec2Mock, autoScalingMock, nil, instances, tpl)
+
+		err := SUT.GetLaunchTemplates()
+		require.NoError(t, err)
+		// 5 is the version i-1 was launched from
+		assert.Equal(t, []int64{10, 9, 8, 5, 1}, SUT.GetAllLaunchTemplates()[0].KeptVersions)
+	})
+
+	t.Run("Error DescribeLaunchTemplates", func(t *testing.T) {
+		SUT, ec2Mock, _ := setupSUT(t, "60h", 2, false)
+
+		ec2Mock.EXPECT().DescribeLaunchTemplates(context.TODO(), &ec2.DescribeLaunchTemplatesInput{}).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.GetLaunchTemplates()
+		require.EqualError(t, err, "could not get launch templates: some error")
+	})
+}
+
+func TestPrune(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, ec2Mock, autoScalingMock := setupSUT(t, "60h", 2, false)
+
+		used := launchTpl("lt-used", "used", 8, 8)
+		unused := launchTpl("lt-unused", "unused", 1, 1)
+		unused.versions[0].CreateTime = aws.Time(time.Now().Add(-10 * 24 * time.Hour))
+		recent := launchTpl("lt-recent", "recent", 1, 1)
+		groups := []autoscalingTypes.AutoScalingGroup{
+			{AutoScalingGroupName: aws.String("used"), LaunchTemplate: &autoscalingTypes.LaunchTemplateSpecification{LaunchTemplateId: aws.String("lt-used"), Version: aws.String("$Latest")}},
+		}
+		mockLaunchTemplates(ec2Mock, autoScalingMock, groups, nil, used, unused, recent)
+		ec2Mock.EXPECT().DeleteLaunchTemplateVersions(context.TODO(), &ec2.DeleteLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("lt-used"),
+			Versions:         []string{"5", "4", "3", "2", "1"},
+			DryRun:           aws.Bool(false),
+		}).Return(&ec2.DeleteLaunchTemplateVersionsOutput{}, nil).Once()
+		ec2Mock.EXPECT().DeleteLaunchTemplate(context.TODO(), &ec2.DeleteLaunchTemplateInput{
+			LaunchTemplateId: aws.String("lt-unused"),
+			DryRun:           aws.Bool(false),
+		}).Return(&ec2.DeleteLaunchTemplateOutput{}, nil).Once()
+
+		err := SUT.Prune()
+		require.NoError(t, err)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		SUT, ec2Mock, autoScalingMock := setupSUT(t, "60h", 0, true)
+
+		tpl := launchTpl("lt-1", "web", 5, 5)
+		instances := []types.Instance{{InstanceId: aws.String("i-1"), Tags: []types.Tag{{Key: aws.String(internal.LAUNCH_TPL_ID_TAG), Value: aws.String("lt-1")}}}}
+		mockLaunchTemplates(ec2Mock, autoScalingMock, nil, instances, tpl)
+		ec2Mock.EXPECT().DeleteLaunchTemplateVersions(context.TODO(), &ec2.DeleteLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("lt-1"),
+			Versions:         []string{"2", "1"},
+			DryRun:           aws.Bool(true),
+		}).Return(nil, &smithy.GenericAPIError{Code: "DryRunOperation"}).Once()
+
+		err := SUT.Prune()
+		require.NoError(t, err)
+	})
+}
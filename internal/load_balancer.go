@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	cloudtrailTypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbTypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/steffakasid/eslog"
+)
+
+// GetLoadBalancers returns all application, network and gateway load balancers.
+func (a AWS) GetLoadBalancers() ([]elbTypes.LoadBalancer, error) {
+	loadBalancers := []elbTypes.LoadBalancer{}
+	in := &elasticloadbalancingv2.DescribeLoadBalancersInput{}
+	for {
+		out, err := a.elbv2.DescribeLoadBalancers(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		loadBalancers = append(loadBalancers, out.LoadBalancers...)
+
+		if out.NextMarker == nil {
+			return loadBalancers, nil
+		}
+		in.Marker = out.NextMarker
+	}
+}
+
+// GetTargetGroups returns all target groups.
+func (a AWS) GetTargetGroups() ([]elbTypes.TargetGroup, error) {
+	targetGroups := []elbTypes.TargetGroup{}
+	in := &elasticloadbalancingv2.DescribeTargetGroupsInput{}
+	for {
+		out, err := a.elbv2.DescribeTargetGroups(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		targetGroups = append(targetGroups, out.TargetGroups...)
+
+		if out.NextMarker == nil {
+			return targetGroups, nil
+		}
+		in.Marker = out.NextMarker
+	}
+}
+
+// GetTargetHealth returns the registered targets of the target group with their health.
+func (a AWS) GetTargetHealth(targetGroupArn string) ([]elbTypes.TargetHealthDescription, error) {
+	out, err := a.elbv2.DescribeTargetHealth(context.TODO(), &elasticloadbalancingv2.DescribeTargetHealthInput{
+		TargetGroupArn: &targetGroupArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.TargetHealthDescriptions, nil
+}
+
+// GetListeners returns the listeners of the load balancer.
+func (a AWS) GetListeners(loadBalancerArn string) ([]elbTypes.Listener, error) {
+	listeners := []elbTypes.Listener{}
+	in := &elasticloadbalancingv2.DescribeListenersInput{LoadBalancerArn: &loadBalancerArn}
+	for {
+		out, err := a.elbv2.DescribeListeners(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, out.Listeners...)
+
+		if out.NextMarker == nil {
+			return listeners, nil
+		}
+		in.Marker = out.NextMarker
+	}
+}
+
+// GetTargetGroupCreationEvents returns the CreateTargetGroup event of each target group created between
+// startTime and endTime by the target group ARN.
+func (a AWS) GetTargetGroupCreationEvents(startTime, endTime time.Time) (map[string]LifecycleEvent, error) {
+	return a.lookupLatestEvents(TARGETGROUP_CREATED, func(ev cloudtrailTypes.Event) []string {
+		details := struct {
+			ResponseElements struct {
+				TargetGroups []struct {
+					TargetGroupArn string `json:"targetGroupArn"`
+				} `json:"targetGroups"`
+			} `json:"responseElements"`
+		}{}
+		if !parseCloudTrailEvent(ev, &details) {
+			return nil
+		}
+		arns := []string{}
+		for _, targetGroup := range details.ResponseElements.TargetGroups {
+			arns = append(arns, targetGroup.TargetGroupArn)
+		}
+		return arns
+	}, startTime, endTime)
+}
+
+// GetTargetDeregistrationEvents returns the latest DeregisterTargets event of each target group between
+// startTime and endTime by the target group ARN.
+func (a AWS) GetTargetDeregistrationEvents(startTime, endTime time.Time) (map[string]LifecycleEvent, error) {
+	return a.lookupLatestEvents(TARGETS_DEREGISTERED, func(ev cloudtrailTypes.Event) []string {
+		details := struct {
+			RequestParameters struct {
+				TargetGroupArn string `json:"targetGroupArn"`
+			} `json:"requestParameters"`
+		}{}
+		if !parseCloudTrailEvent(ev, &details) || details.RequestParameters.TargetGroupArn == "" {
+			return nil
+		}
+		return []string{details.RequestParameters.TargetGroupArn}
+	}, startTime, endTime)
+}
+
+// DeleteLoadBalancer deletes the load balancer. ELBv2 has no dry run, so nothing is called if dryrun is set.
+func (a AWS) DeleteLoadBalancer(loadBalancerArn string, dryrun bool) error {
+	eslog.Logger.Debugf("DeleteLoadBalancer(%s), dryrun: %t", loadBalancerArn, dryrun)
+	if dryrun {
+		return nil
+	}
+
+	_, err := a.elbv2.DeleteLoadBalancer(context.TODO(), &elasticloadbalancingv2.DeleteLoadBalancerInput{
+		LoadBalancerArn: &loadBalancerArn,
+	})
+	return err
+}
+
+// DeleteTargetGroup deletes the target group. ELBv2 has no dry run, so nothing is called if dryrun is set.
+func (a AWS) DeleteTargetGroup(targetGroupArn string, dryrun bool) error {
+	eslog.Logger.Debugf("DeleteTargetGroup(%s), dryrun: %t", targetGroupArn, dryrun)
+	if dryrun {
+		return nil
+	}
+
+	_, err := a.elbv2.DeleteTargetGroup(context.TODO(), &elasticloadbalancingv2.DeleteTargetGroupInput{
+		TargetGroupArn: &targetGroupArn,
+	})
+	return err
+}
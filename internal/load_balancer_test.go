@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cloudtrailTypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupELBv2SUT(t *testing.T) (*AWS, *mocks.MockELBv2, *mocks.MockCloudTrail) {
+	elbv2Mock := mocks.NewMockELBv2(t)
+	cloudTrailMock := mocks.NewMockCloudTrail(t)
+	SUT := NewFromInterface(mocks.NewMockEc2client(t), cloudTrailMock, WithELBv2(elbv2Mock))
+	return SUT, elbv2Mock, cloudTrailMock
+}
+
+func TestGetLoadBalancers(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, elbv2Mock, _ := setupELBv2SUT(t)
+		elbv2Mock.EXPECT().DescribeLoadBalancers(context.TODO(), &elasticloadbalancingv2.DescribeLoadBalancersInput{}).Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []types.LoadBalancer{{LoadBalancerName: aws.String("lb-1")}},
+			NextMarker:    aws.String("next"),
+		}, nil).Once()
+		elbv2Mock.EXPECT().DescribeLoadBalancers(context.TODO(), &elasticloadbalancingv2.DescribeLoadBalancersInput{Marker: aws.String("next")}).Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []types.LoadBalancer{{LoadBalancerName: aws.String("lb-2")}},
+		}, nil).Once()
+
+		loadBalancers, err := SUT.GetLoadBalancers()
+		require.NoError(t, err)
+		require.Len(t, loadBalancers, 2)
+		assert.Equal(t, "lb-2", *loadBalancers[1].LoadBalancerName)
+	})
+
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, elbv2Mock, _ := setupELBv2SUT(t)
+		elbv2Mock.EXPECT().DescribeLoadBalancers(context.TODO(), &elasticloadbalancingv2.DescribeLoadBalancersInput{}).Return(nil, errors.New("Something went wrong")).Once()
+
+		_, err := SUT.GetLoadBalancers()
+		require.EqualError(t, err, "Something went wrong")
+	})
+}
+
+func TestGetTargetGroups(t *testing.T) {
+	SUT, elbv2Mock, _ := setupELBv2SUT(t)
+	elbv2Mock.EXPECT().DescribeTargetGroups(context.TODO(), &elasticloadbalancingv2.DescribeTargetGroupsInput{}).Return(&elasticloadbalancingv2.DescribeTargetGroupsOutput{
+		TargetGroups: []types.TargetGroup{{TargetGroupName: aws.String("tg-1")}},
+		NextMarker:   aws.String("next"),
+	}, nil).Once()
+	elbv2Mock.EXPECT().DescribeTargetGroups(context.TODO(), &elasticloadbalancingv2.DescribeTargetGroupsInput{Marker: aws.String("next")}).Return(&elasticloadbalancingv2.DescribeTargetGroupsOutput{
+		TargetGroups: []types.TargetGroup{{TargetGroupName: aws.String("tg-2")}},
+	}, nil).Once()
+
+	targetGroups, err := SUT.GetTargetGroups()
+	require.NoError(t, err)
+	assert.Len(t, targetGroups, 2)
+}
+
+func TestGetListeners(t *testing.T) {
+	SUT, elbv2Mock, _ := setupELBv2SUT(t)
+	elbv2Mock.EXPECT().DescribeListeners(context.TODO(), &elasticloadbalancingv2.DescribeListenersInput{LoadBalancerArn: aws.String("arn:lb-1")}).Return(&elasticloadbalancingv2.DescribeListenersOutput{
+		Listeners:  []types.Listener{{ListenerArn: aws.String("arn:listener-1")}},
+		NextMarker: aws.String("next"),
+	}, nil).Once()
+	elbv2Mock.EXPECT().DescribeListeners(context.TODO(), &elasticloadbalancingv2.DescribeListenersInput{LoadBalancerArn: aws.String("arn:lb-1"), Marker: aws.String("next")}).Return(&elasticloadbalancingv2.DescribeListenersOutput{
+		Listeners: []types.Listener{{ListenerArn: aws.String("arn:listener-2")}},
+	}, nil).Once()
+
+	listeners, err := SUT.GetListeners("arn:lb-1")
+	require.NoError(t, err)
+	require.Len(t, listeners, 2)
+	assert.Equal(t, "arn:listener-2", *listeners[1].ListenerArn)
+}
+
+func TestGetTargetHealth(t *testing.T) {
+	SUT, elbv2Mock, _ := setupELBv2SUT(t)
+	elbv2Mock.EXPECT().DescribeTargetHealth(context.TODO(), &elasticloadbalancingv2.DescribeTargetHealthInput{TargetGroupArn: aws.String("arn:tg-1")}).Return(&elasticloadbalancingv2.DescribeTargetHealthOutput{
+		TargetHealthDescriptions: []types.TargetHealthDescription{
+			{Target: &types.TargetDescription{Id: aws.String("i-1")}, TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumHealthy}},
+		},
+	}, nil).Once()
+
+	targets, err := SUT.GetTargetHealth("arn:tg-1")
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, "i-1", *targets[0].Target.Id)
+}
+
+func TestGetTargetGroupCreationEvents(t *testing.T) {
+	starttime, err := time.Parse(time.DateTime, "2006-01-02 15:04:05")
+	require.NoError(t, err)
+	endtime := starttime.Add(24 * time.Hour)
+
+	SUT, _, cloudTrailMock := setupELBv2SUT(t)
+	cloudTrailMock.EXPECT().LookupEvents(context.TODO(), &cloudtrail.LookupEventsInput{
+		StartTime: &starttime,
+		EndTime:   &endtime,
+		LookupAttributes: []cloudtrailTypes.LookupAttribute{
+			{
+				AttributeKey:   cloudtrailTypes.LookupAttributeKeyEventName,
+				AttributeValue: aws.String("CreateTargetGroup"),
+			},
+		},
+	}).Return(&cloudtrail.LookupEventsOutput{
+		Events: []cloudtrailTypes.Event{
+			{EventName: aws.String("CreateTargetGroup"), EventTime: aws.Time(starttime.Add(time.Hour)), Username: aws.String("alice"),
+				CloudTrailEvent: aws.String(`{"responseElements":{"targetGroups":[{"targetGroupArn":"arn:tg-1","targetGroupName":"tg-1"}]}}`)},
+			{EventName: aws.String("CreateTargetGroup"), EventTime: aws.Time(starttime.Add(2 * time.Hour)), Username: aws.String("bob"),
+				CloudTrailEvent: aws.String(`{"errorCode":"DuplicateTargetGroupName","responseElements":null}`)},
+		},
+	}, nil).Once()
+
+	events, err := SUT.GetTargetGroupCreationEvents(starttime, endtime)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "alice", events["arn:tg-1"].Username)
+}
+
+func TestGetTargetDeregistrationEvents(t *testing.T) {
+	starttime, err := time.Parse(time.DateTime, "2006-01-02 15:04:05")
+	require.NoError(t, err)
+	endtime := starttime.Add(24 * time.Hour)
+
+	SUT, _, cloudTrailMock := setupELBv2SUT(t)
+	cloudTrailMock.EXPECT().LookupEvents(context.TODO(), mock.MatchedBy(func(in *cloudtrail.LookupEventsInput) bool {
+		return aws.ToString(in.LookupAttributes[0].AttributeValue) == "DeregisterTargets"
+	})).Return(&cloudtrail.LookupEventsOutput{
+		Events: []cloudtrailTypes.Event{
+			{EventName: aws.String("DeregisterTargets"), EventTime: aws.Time(starttime.Add(time.Hour)), Username: aws.String("alice"),
+				CloudTrailEvent: aws.String(`{"requestParameters":{"targetGroupArn":"arn:tg-1","targets":[{"id":"i-1"}]}}`)},
+			{EventName: aws.String("DeregisterTargets"), EventTime: aws.Time(starttime.Add(2 * time.Hour)), Username: aws.String("bob"),
+				CloudTrailEvent: aws.String(`{"requestParameters":{"targetGroupArn":"arn:tg-1","targets":[{"id":"i-2"}]}}`)},
+		},
+	}, nil).Once()
+
+	events, err := SUT.GetTargetDeregistrationEvents(starttime, endtime)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "bob", events["arn:tg-1"].Username)
+}
+
+func TestDeleteLoadBalancer(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, elbv2Mock, _ := setupELBv2SUT(t)
+		elbv2Mock.EXPECT().DeleteLoadBalancer(context.TODO(), &elasticloadbalancingv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String("arn:lb-1")}).Return(&elasticloadbalancingv2.DeleteLoadBalancerOutput{}, nil).Once()
+
+		err := SUT.DeleteLoadBalancer("arn:lb-1", false)
+		require.NoError(t, err)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		SUT, elbv2Mock, _ := setupELBv2SUT(t)
+
+		err := SUT.DeleteLoadBalancer("arn:lb-1", true)
+		require.NoError(t, err)
+		elbv2Mock.AssertNotCalled(t, "DeleteLoadBalancer", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteTargetGroup(t *testing.T) {
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, elbv2Mock, _ := setupELBv2SUT(t)
+		elbv2Mock.EXPECT().DeleteTargetGroup(context.TODO(), &elasticloadbalancingv2.DeleteTargetGroupInput{TargetGroupArn: aws.String("arn:tg-1")}).Return(nil, errors.New("Something went wrong")).Once()
+
+		err := SUT.DeleteTargetGroup("arn:tg-1", false)
+		require.EqualError(t, err, "Something went wrong")
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		SUT, elbv2Mock, _ := setupELBv2SUT(t)
+
+		err := SUT.DeleteTargetGroup("arn:tg-1", true)
+		require.NoError(t, err)
+		elbv2Mock.AssertNotCalled(t, "DeleteTargetGroup", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/steffakasid/eslog"
+)
+
+// RETENTION_DAYS are the values accepted by PutRetentionPolicy.
+var RETENTION_DAYS = []int32{1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653}
+
+// GetLogGroups returns all CloudWatch log groups.
+func (a AWS) GetLogGroups() ([]logsTypes.LogGroup, error) {
+	logGroups := []logsTypes.LogGroup{}
+	in := &cloudwatchlogs.DescribeLogGroupsInput{}
+	for {
+		out, err := a.logs.DescribeLogGroups(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		logGroups = append(logGroups, out.LogGroups...)
+
+		if out.NextToken == nil {
+			return logGroups, nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// GetLastIngestionTime returns when the last event was ingested into the log group. If no event was ingested
+// into the latest log stream its creation time is returned instead. It's nil only if the log group has no log
+// streams. DescribeLogStreams has a low rate limit, so the calls are
+// spaced and throttled calls are retried with exponential backoff.
+func (a AWS) GetLastIngestionTime(logGroupName string) (*time.Time, error) {
+	out, err := callThrottled("DescribeLogStreams", a.logStreamsLimiter, a.logStreamsBackoff, func() (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+		return a.logs.DescribeLogStreams(context.TODO(), &cloudwatchlogs.DescribeLogStreamsInput{
+			LogGroupName: &logGroupName,
+			OrderBy:      logsTypes.OrderByLastEventTime,
+			Descending:   aws.Bool(true),
+			Limit:        aws.Int32(1),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.LogStreams) == 0 {
+		return nil, nil
+	}
+	logStream := out.LogStreams[0]
+	if logStream.LastIngestionTime == nil {
+		logStream.LastIngestionTime = logStream.CreationTime
+	}
+	lastIngestionTime := time.UnixMilli(aws.ToInt64(logStream.LastIngestionTime))
+	return &lastIngestionTime, nil
+}
+
+// DeleteLogGroup deletes the log group including all its events. CloudWatch Logs has no dry run, so nothing is
+// called if dryrun is set.
+func (a AWS) DeleteLogGroup(logGroupName string, dryrun bool) error {
+	eslog.Logger.Debugf("DeleteLogGroup(%s), dryrun: %t", logGroupName, dryrun)
+	if dryrun {
+		return nil
+	}
+
+	_, err := a.logs.DeleteLogGroup(context.TODO(), &cloudwatchlogs.DeleteLogGroupInput{
+		LogGroupName: &logGroupName,
+	})
+	return err
+}
+
+// PutRetentionPolicy sets after how many days the events of the log group expire. CloudWatch Logs has no dry
+// run, so nothing is called if dryrun is set.
+func (a AWS) PutRetentionPolicy(logGroupName string, retentionInDays int32, dryrun bool) error {
+	eslog.Logger.Debugf("PutRetentionPolicy(%s, %d), dryrun: %t", logGroupName, retentionInDays, dryrun)
+	if dryrun {
+		return nil
+	}
+
+	_, err := a.logs.PutRetentionPolicy(context.TODO(), &cloudwatchlogs.PutRetentionPolicyInput{
+		LogGroupName:    &logGroupName,
+		RetentionInDays: &retentionInDays,
+	})
+	return err
+}
@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/smithy-go"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLogsSUT(t *testing.T) (*AWS, *mocks.MockCloudWatchLogs) {
+	logsMock := mocks.NewMockCloudWatchLogs(t)
+	SUT := NewFromInterface(mocks.NewMockEc2client(t), mocks.NewMockCloudTrail(t), WithCloudWatchLogs(logsMock))
+	return SUT, logsMock
+}
+
+func TestGetLogGroups(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+		logsMock.EXPECT().DescribeLogGroups(context.TODO(), &cloudwatchlogs.DescribeLogGroupsInput{}).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []types.LogGroup{{LogGroupName: aws.String("/aws/lambda/one")}},
+			NextToken: aws.String("next"),
+		}, nil).Once()
+		logsMock.EXPECT().DescribeLogGroups(context.TODO(), &cloudwatchlogs.DescribeLogGroupsInput{NextToken: aws.String("next")}).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []types.LogGroup{{LogGroupName: aws.String("/aws/lambda/two")}},
+		}, nil).Once()
+
+		logGroups, err := SUT.GetLogGroups()
+		require.NoError(t, err)
+		require.Len(t, logGroups, 2)
+		assert.Equal(t, "/aws/lambda/two", *logGroups[1].LogGroupName)
+	})
+
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+		logsMock.EXPECT().DescribeLogGroups(context.TODO(), &cloudwatchlogs.DescribeLogGroupsInput{}).Return(nil, errors.New("Something went wrong")).Once()
+
+		_, err := SUT.GetLogGroups()
+		require.EqualError(t, err, "Something went wrong")
+	})
+}
+
+func TestGetLastIngestionTime(t *testing.T) {
+	expectedInput := &cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName: aws.String("/aws/lambda/one"),
+		OrderBy:      types.OrderByLastEventTime,
+		Descending:   aws.Bool(true),
+		Limit:        aws.Int32(1),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+		lastIngestionTime := time.UnixMilli(time.Now().UnixMilli())
+		logsMock.EXPECT().DescribeLogStreams(context.TODO(), expectedInput).Return(&cloudwatchlogs.DescribeLogStreamsOutput{
+			LogStreams: []types.LogStream{{LastIngestionTime: aws.Int64(lastIngestionTime.UnixMilli())}},
+		}, nil).Once()
+
+		ingestionTime, err := SUT.GetLastIngestionTime("/aws/lambda/one")
+		require.NoError(t, err)
+		assert.True(t, lastIngestionTime.Equal(*ingestionTime))
+	})
+
+	t.Run("Without Ingestion", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+		creationTime := time.UnixMilli(time.Now().UnixMilli())
+		logsMock.EXPECT().DescribeLogStreams(context.TODO(), expectedInput).Return(&cloudwatchlogs.DescribeLogStreamsOutput{
+			LogStreams: []types.LogStream{{CreationTime: aws.Int64(creationTime.UnixMilli())}},
+		}, nil).Once()
+
+		ingestionTime, err := SUT.GetLastIngestionTime("/aws/lambda/one")
+		require.NoError(t, err)
+		assert.True(t, creationTime.Equal(*ingestionTime))
+	})
+
+	t.Run("No Log Streams", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+		logsMock.EXPECT().DescribeLogStreams(context.TODO(), expectedInput).Return(&cloudwatchlogs.DescribeLogStreamsOutput{}, nil).Once()
+
+		ingestionTime, err := SUT.GetLastIngestionTime("/aws/lambda/one")
+		require.NoError(t, err)
+		assert.Nil(t, ingestionTime)
+	})
+
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+
+	t.Run("Retry Throttled", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+		SUT.logStreamsBackoff = backoff{base: time.Millisecond, max: 5 * time.Millisecond, retries: 2}
+		logsMock.EXPECT().DescribeLogStreams(context.TODO(), expectedInput).Return(nil, throttled).Twice()
+		logsMock.EXPECT().DescribeLogStreams(context.TODO(), expectedInput).Return(&cloudwatchlogs.DescribeLogStreamsOutput{}, nil).Once()
+
+		ingestionTime, err := SUT.GetLastIngestionTime("/aws/lambda/one")
+		require.NoError(t, err)
+		assert.Nil(t, ingestionTime)
+	})
+
+	t.Run("Still Throttled", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+		SUT.logStreamsBackoff = backoff{base: time.Millisecond, max: 5 * time.Millisecond, retries: 2}
+		logsMock.EXPECT().DescribeLogStreams(context.TODO(), expectedInput).Return(nil, throttled).Times(3)
+
+		_, err := SUT.GetLastIngestionTime("/aws/lambda/one")
+		assert.ErrorIs(t, err, throttled)
+	})
+}
+
+func TestDeleteLogGroup(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+		logsMock.EXPECT().DeleteLogGroup(context.TODO(), &cloudwatchlogs.DeleteLogGroupInput{LogGroupName: aws.String("/aws/lambda/one")}).Return(&cloudwatchlogs.DeleteLogGroupOutput{}, nil).Once()
+
+		err := SUT.DeleteLogGroup("/aws/lambda/one", false)
+		require.NoError(t, err)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+
+		err := SUT.DeleteLogGroup("/aws/lambda/one", true)
+		require.NoError(t, err)
+		logsMock.AssertNotCalled(t, "DeleteLogGroup", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPutRetentionPolicy(t *testing.T) {
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+		logsMock.EXPECT().PutRetentionPolicy(context.TODO(), &cloudwatchlogs.PutRetentionPolicyInput{
+			LogGroupName:    aws.String("/aws/lambda/one"),
+			RetentionInDays: aws.Int32(30),
+		}).Return(nil, errors.New("Something went wrong")).Once()
+
+		err := SUT.PutRetentionPolicy("/aws/lambda/one", 30, false)
+		require.EqualError(t, err, "Something went wrong")
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		SUT, logsMock := setupLogsSUT(t)
+
+		err := SUT.PutRetentionPolicy("/aws/lambda/one", 30, true)
+		require.NoError(t, err)
+		logsMock.AssertNotCalled(t, "PutRetentionPolicy", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,179 @@
+package loggroupclean
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/steffakasid/awsclean/internal"
+	eslog "github.com/steffakasid/eslog"
+)
+
+// LogGroup adds the time of the last ingested event and the reason why the log group is kept or cleaned up.
+type LogGroup struct {
+	types.LogGroup
+	LastIngestionTime *time.Time
+	Reason            string
+}
+
+type LogGroupClean struct {
+	awsClient      *internal.AWS
+	olderthen      time.Duration
+	dryrun         bool
+	onlyUnused     bool
+	ignorePatterns []string
+	// if set, groups without retention get this retention instead of being deleted
+	retentionInDays int32
+	usedLogGroups   []LogGroup
+	unusedLogGroups []LogGroup
+}
+
+// Option is used to set optional settings in NewInstance.
+type Option func(*LogGroupClean)
+
+// WithRetention sets a retention policy on stale log groups without one instead of deleting them. Stale log
+// groups which already have a retention policy are kept as their events expire anyway.
+func WithRetention(retentionInDays int32) Option {
+	return func(l *LogGroupClean) {
+		l.retentionInDays = retentionInDays
+	}
+}
+
+func NewInstance(
+	awsClient *internal.AWS,
+	olderthen time.Duration,
+	dryrun bool,
+	onlyUnused bool,
+	ignorePatterns []string,
+	opts ...Option) *LogGroupClean {
+
+	loggroupclean := &LogGroupClean{
+		awsClient:       awsClient,
+		olderthen:       olderthen,
+		dryrun:          dryrun,
+		onlyUnused:      onlyUnused,
+		ignorePatterns:  ignorePatterns,
+		usedLogGroups:   []LogGroup{},
+		unusedLogGroups: []LogGroup{},
+	}
+	for _, opt := range opts {
+		opt(loggroupclean)
+	}
+	return loggroupclean
+}
+
+// GetLogGroups fetches all log groups and sorts them into used and unused ones. A log group is unused if its
+// last event was ingested before olderthen or if it has no log streams and was created before olderthen. Log
+// groups matching an ignore pattern or whose last ingestion can't be looked up are always used.
+func (l *LogGroupClean) GetLogGroups() error {
+	logGroups, err := l.awsClient.GetLogGroups()
+	if err != nil {
+		return fmt.Errorf("could not get log groups: %w", err)
+	}
+
+	olderThenDate := time.Now().Add(l.olderthen * -1)
+	for _, logGroup := range logGroups {
+		name := aws.ToString(logGroup.LogGroupName)
+		lg := LogGroup{LogGroup: logGroup}
+
+		ignored, err := internal.MatchAny(name, l.ignorePatterns)
+		if err != nil {
+			return err
+		}
+		if ignored {
+			lg.Reason = "matches ignore pattern"
+			l.usedLogGroups = append(l.usedLogGroups, lg)
+			continue
+		}
+
+		// StoredBytes is also 0 if all events expired by the retention, so it doesn't tell if the log group is in use
+		lg.LastIngestionTime, err = l.awsClient.GetLastIngestionTime(name)
+		if err != nil {
+			eslog.Logger.Warnf("Could not get last ingestion time of %s: %s", name, err)
+			lg.Reason = "last ingestion unknown"
+			l.usedLogGroups = append(l.usedLogGroups, lg)
+			continue
+		}
+
+		switch {
+		case lg.LastIngestionTime == nil:
+			creationTime := time.UnixMilli(aws.ToInt64(logGroup.CreationTime))
+			if creationTime.Before(olderThenDate) {
+				lg.Reason = "empty"
+				l.unusedLogGroups = append(l.unusedLogGroups, lg)
+			} else {
+				lg.Reason = fmt.Sprintf("empty but created %s which is newer then %s", creationTime.Format(time.RFC3339), olderThenDate.Format(time.RFC3339))
+				l.usedLogGroups = append(l.usedLogGroups, lg)
+			}
+		case lg.LastIngestionTime.Before(olderThenDate):
+			lg.Reason = fmt.Sprintf("last ingestion %s is older then %s", lg.LastIngestionTime.Format(time.RFC3339), olderThenDate.Format(time.RFC3339))
+			l.unusedLogGroups = append(l.unusedLogGroups, lg)
+		default:
+			l.usedLogGroups = append(l.usedLogGroups, lg)
+		}
+	}
+	return nil
+}
+
+func (l LogGroupClean) GetAllLogGroups() []LogGroup {
+	all := []LogGroup{}
+
+	all = append(all, l.unusedLogGroups...)
+	if !l.onlyUnused {
+		all = append(all, l.usedLogGroups...)
+	}
+
+	return all
+}
+
+// DeleteStaleLogGroups deletes the unused log groups. If a retention is set, a retention policy is set on the
+// unused log groups without one instead.
+func (l *LogGroupClean) DeleteStaleLogGroups() error {
+	err := l.GetLogGroups()
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	retained := 0
+	skipped := 0
+
+	for _, logGroup := range l.unusedLogGroups {
+		name := aws.ToString(logGroup.LogGroupName)
+
+		if l.retentionInDays > 0 {
+			if logGroup.RetentionInDays != nil {
+				eslog.Logger.Infof("Keeping %s as it already has a retention of %d days", name, *logGroup.RetentionInDays)
+				skipped++
+				continue
+			}
+			eslog.Logger.Infof("Set retention of %s to %d days as it's %s (dry run: %t)", name, l.retentionInDays, logGroup.Reason, l.dryrun)
+			err := l.awsClient.PutRetentionPolicy(name, l.retentionInDays, l.dryrun)
+			if err != nil {
+				eslog.LogIfErrorf(err, eslog.Errorf, "Error on PutRetentionPolicy(): %s")
+				skipped++
+				continue
+			}
+			retained++
+			continue
+		}
+
+		if aws.ToBool(logGroup.DeletionProtectionEnabled) {
+			eslog.Logger.Infof("Keeping %s as deletion protection is enabled", name)
+			skipped++
+			continue
+		}
+		eslog.Logger.Infof("Delete %s as it's %s (dry run: %t)", name, logGroup.Reason, l.dryrun)
+		err := l.awsClient.DeleteLogGroup(name, l.dryrun)
+		if err != nil {
+			eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeleteLogGroup(): %s")
+			skipped++
+			continue
+		}
+		deleted++
+	}
+
+	eslog.Logger.Infof("Deleted %d, Set retention of %d, Skipped %d log groups", deleted, retained, skipped)
+	return nil
+}
@@ -0,0 +1,189 @@
+package loggroupclean
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+func setupSUT(t *testing.T, olderthen string, dryrun, onlyUnused bool, ignorePatterns []string, opts ...Option) (*LogGroupClean, *mocks.MockCloudWatchLogs) {
+	olderthenDuration, err := str2duration.ParseDuration(olderthen)
+	require.NoError(t, err)
+
+	logsMock := mocks.NewMockCloudWatchLogs(t)
+	awsClient := internal.NewFromInterface(mocks.NewMockEc2client(t), mocks.NewMockCloudTrail(t), internal.WithCloudWatchLogs(logsMock))
+	return NewInstance(awsClient, olderthenDuration, dryrun, onlyUnused, ignorePatterns, opts...), logsMock
+}
+
+func daysAgo(days int) int64 {
+	return time.Now().Add(time.Duration(-days) * 24 * time.Hour).UnixMilli()
+}
+
+func logGroup(name string, createdDaysAgo int) types.LogGroup {
+	return types.LogGroup{LogGroupName: aws.String(name), CreationTime: aws.Int64(daysAgo(createdDaysAgo))}
+}
+
+func mockLogGroups(logsMock *mocks.MockCloudWatchLogs, logGroups ...types.LogGroup) {
+	logsMock.EXPECT().DescribeLogGroups(context.TODO(), &cloudwatchlogs.DescribeLogGroupsInput{}).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+		LogGroups: logGroups,
+	}, nil).Once()
+}
+
+// mockLastIngestion expects the lookup of the last ingestion of the log group. If ingestedDaysAgo is negative the
+// log group has no log streams.
+func mockLastIngestion(logsMock *mocks.MockCloudWatchLogs, name string, ingestedDaysAgo int) {
+	out := &cloudwatchlogs.DescribeLogStreamsOutput{}
+	if ingestedDaysAgo >= 0 {
+		out.LogStreams = []types.LogStream{{LastIngestionTime: aws.Int64(daysAgo(ingestedDaysAgo))}}
+	}
+	logsMock.EXPECT().DescribeLogStreams(context.TODO(), mock.MatchedBy(func(in *cloudwatchlogs.DescribeLogStreamsInput) bool {
+		return aws.ToString(in.LogGroupName) == name
+	})).Return(out, nil).Once()
+}
+
+func TestGetLogGroups(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		SUT, logsMock := setupSUT(t, "30d", false, false, []string{"^/aws/eks/"})
+
+		mockLogGroups(logsMock,
+			logGroup("/aws/lambda/active", 100),
+			logGroup("/aws/lambda/stale", 100),
+			logGroup("/aws/lambda/empty", 100),
+			logGroup("/aws/lambda/new", 1),
+			logGroup("/aws/eks/cluster", 100),
+		)
+		mockLastIngestion(logsMock, "/aws/lambda/active", 1)
+		mockLastIngestion(logsMock, "/aws/lambda/stale", 40)
+		mockLastIngestion(logsMock, "/aws/lambda/empty", -1)
+		mockLastIngestion(logsMock, "/aws/lambda/new", -1)
+
+		err := SUT.GetLogGroups()
+		require.NoError(t, err)
+
+		require.Len(t, SUT.unusedLogGroups, 2)
+		assert.Equal(t, "/aws/lambda/stale", *SUT.unusedLogGroups[0].LogGroupName)
+		assert.Contains(t, SUT.unusedLogGroups[0].Reason, "last ingestion")
+		assert.Equal(t, "/aws/lambda/empty", *SUT.unusedLogGroups[1].LogGroupName)
+		assert.Equal(t, "empty", SUT.unusedLogGroups[1].Reason)
+		require.Len(t, SUT.usedLogGroups, 3)
+		assert.Equal(t, "matches ignore pattern", SUT.usedLogGroups[2].Reason)
+		assert.Len(t, SUT.GetAllLogGroups(), 5)
+	})
+
+	t.Run("Only Unused", func(t *testing.T) {
+		SUT, logsMock := setupSUT(t, "30d", false, true, nil)
+
+		mockLogGroups(logsMock, logGroup("/aws/lambda/active", 100))
+		mockLastIngestion(logsMock, "/aws/lambda/active", 1)
+
+		err := SUT.GetLogGroups()
+		require.NoError(t, err)
+		assert.Empty(t, SUT.GetAllLogGroups())
+	})
+
+	t.Run("Without Stored Bytes", func(t *testing.T) {
+		SUT, logsMock := setupSUT(t, "30d", false, false, nil)
+
+		expired := logGroup("/aws/lambda/expired", 100)
+		expired.StoredBytes = aws.Int64(0)
+		mockLogGroups(logsMock, expired)
+		mockLastIngestion(logsMock, "/aws/lambda/expired", 1)
+
+		err := SUT.GetLogGroups()
+		require.NoError(t, err)
+		assert.Empty(t, SUT.unusedLogGroups)
+		require.Len(t, SUT.usedLogGroups, 1)
+	})
+
+	t.Run("Error DescribeLogStreams", func(t *testing.T) {
+		SUT, logsMock := setupSUT(t, "30d", false, false, nil)
+
+		mockLogGroups(logsMock, logGroup("/aws/lambda/unknown", 100), logGroup("/aws/lambda/stale", 100))
+		logsMock.EXPECT().DescribeLogStreams(context.TODO(), mock.MatchedBy(func(in *cloudwatchlogs.DescribeLogStreamsInput) bool {
+			return aws.ToString(in.LogGroupName) == "/aws/lambda/unknown"
+		})).Return(nil, errors.New("some error")).Once()
+		mockLastIngestion(logsMock, "/aws/lambda/stale", 40)
+
+		err := SUT.GetLogGroups()
+		require.NoError(t, err)
+		require.Len(t, SUT.usedLogGroups, 1)
+		assert.Equal(t, "last ingestion unknown", SUT.usedLogGroups[0].Reason)
+		require.Len(t, SUT.unusedLogGroups, 1)
+		assert.Equal(t, "/aws/lambda/stale", *SUT.unusedLogGroups[0].LogGroupName)
+	})
+
+	t.Run("Error DescribeLogGroups", func(t *testing.T) {
+		SUT, logsMock := setupSUT(t, "30d", false, false, nil)
+
+		logsMock.EXPECT().DescribeLogGroups(context.TODO(), &cloudwatchlogs.DescribeLogGroupsInput{}).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.GetLogGroups()
+		require.EqualError(t, err, "could not get log groups: some error")
+	})
+
+	t.Run("Invalid Ignore Pattern", func(t *testing.T) {
+		SUT, logsMock := setupSUT(t, "30d", false, false, []string{"("})
+
+		mockLogGroups(logsMock, logGroup("/aws/lambda/active", 100))
+
+		err := SUT.GetLogGroups()
+		require.Error(t, err)
+	})
+}
+
+func TestDeleteStaleLogGroups(t *testing.T) {
+	t.Run("Delete", func(t *testing.T) {
+		SUT, logsMock := setupSUT(t, "30d", false, true, nil)
+
+		protected := logGroup("/aws/lambda/protected", 100)
+		protected.DeletionProtectionEnabled = aws.Bool(true)
+		mockLogGroups(logsMock, logGroup("/aws/lambda/active", 100), logGroup("/aws/lambda/stale", 100), protected)
+		mockLastIngestion(logsMock, "/aws/lambda/active", 1)
+		mockLastIngestion(logsMock, "/aws/lambda/stale", 40)
+		mockLastIngestion(logsMock, "/aws/lambda/protected", -1)
+		logsMock.EXPECT().DeleteLogGroup(context.TODO(), &cloudwatchlogs.DeleteLogGroupInput{LogGroupName: aws.String("/aws/lambda/stale")}).Return(&cloudwatchlogs.DeleteLogGroupOutput{}, nil).Once()
+
+		err := SUT.DeleteStaleLogGroups()
+		require.NoError(t, err)
+	})
+
+	t.Run("Set Retention", func(t *testing.T) {
+		SUT, logsMock := setupSUT(t, "30d", false, true, nil, WithRetention(30))
+
+		retained := logGroup("/aws/lambda/retained", 100)
+		retained.RetentionInDays = aws.Int32(7)
+		mockLogGroups(logsMock, logGroup("/aws/lambda/stale", 100), retained)
+		mockLastIngestion(logsMock, "/aws/lambda/stale", 40)
+		mockLastIngestion(logsMock, "/aws/lambda/retained", 40)
+		logsMock.EXPECT().PutRetentionPolicy(context.TODO(), &cloudwatchlogs.PutRetentionPolicyInput{
+			LogGroupName:    aws.String("/aws/lambda/stale"),
+			RetentionInDays: aws.Int32(30),
+		}).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil).Once()
+
+		err := SUT.DeleteStaleLogGroups()
+		require.NoError(t, err)
+		logsMock.AssertNotCalled(t, "DeleteLogGroup", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		SUT, logsMock := setupSUT(t, "30d", true, true, nil)
+
+		mockLogGroups(logsMock, logGroup("/aws/lambda/empty", 100))
+		mockLastIngestion(logsMock, "/aws/lambda/empty", -1)
+
+		err := SUT.DeleteStaleLogGroups()
+		require.NoError(t, err)
+		logsMock.AssertNotCalled(t, "DeleteLogGroup", mock.Anything, mock.Anything)
+	})
+}
@@ -23,6 +23,302 @@ func (_m *MockEc2client) EXPECT() *MockEc2client_Expecter {
 	return &MockEc2client_Expecter{mock: &_m.Mock}
 }
 
+// CreateTags provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTags")
+	}
+
+	var r0 *ec2.CreateTagsOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.CreateTagsInput, ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.CreateTagsInput, ...func(*ec2.Options)) *ec2.CreateTagsOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.CreateTagsOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.CreateTagsInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_CreateTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTags'
+type MockEc2client_CreateTags_Call struct {
+	*mock.Call
+}
+
+// CreateTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.CreateTagsInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) CreateTags(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_CreateTags_Call {
+	return &MockEc2client_CreateTags_Call{Call: _e.mock.On("CreateTags",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_CreateTags_Call) Run(run func(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options))) *MockEc2client_CreateTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.CreateTagsInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_CreateTags_Call) Return(_a0 *ec2.CreateTagsOutput, _a1 error) *MockEc2client_CreateTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_CreateTags_Call) RunAndReturn(run func(context.Context, *ec2.CreateTagsInput, ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)) *MockEc2client_CreateTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteLaunchTemplate provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DeleteLaunchTemplate(ctx context.Context, params *ec2.DeleteLaunchTemplateInput, optFns ...func(*ec2.Options)) (*ec2.DeleteLaunchTemplateOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLaunchTemplate")
+	}
+
+	var r0 *ec2.DeleteLaunchTemplateOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteLaunchTemplateInput, ...func(*ec2.Options)) (*ec2.DeleteLaunchTemplateOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteLaunchTemplateInput, ...func(*ec2.Options)) *ec2.DeleteLaunchTemplateOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DeleteLaunchTemplateOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DeleteLaunchTemplateInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DeleteLaunchTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteLaunchTemplate'
+type MockEc2client_DeleteLaunchTemplate_Call struct {
+	*mock.Call
+}
+
+// DeleteLaunchTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DeleteLaunchTemplateInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DeleteLaunchTemplate(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DeleteLaunchTemplate_Call {
+	return &MockEc2client_DeleteLaunchTemplate_Call{Call: _e.mock.On("DeleteLaunchTemplate",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DeleteLaunchTemplate_Call) Run(run func(ctx context.Context, params *ec2.DeleteLaunchTemplateInput, optFns ...func(*ec2.Options))) *MockEc2client_DeleteLaunchTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DeleteLaunchTemplateInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DeleteLaunchTemplate_Call) Return(_a0 *ec2.DeleteLaunchTemplateOutput, _a1 error) *MockEc2client_DeleteLaunchTemplate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DeleteLaunchTemplate_Call) RunAndReturn(run func(context.Context, *ec2.DeleteLaunchTemplateInput, ...func(*ec2.Options)) (*ec2.DeleteLaunchTemplateOutput, error)) *MockEc2client_DeleteLaunchTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteLaunchTemplateVersions provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DeleteLaunchTemplateVersions(ctx context.Context, params *ec2.DeleteLaunchTemplateVersionsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteLaunchTemplateVersionsOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLaunchTemplateVersions")
+	}
+
+	var r0 *ec2.DeleteLaunchTemplateVersionsOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteLaunchTemplateVersionsInput, ...func(*ec2.Options)) (*ec2.DeleteLaunchTemplateVersionsOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteLaunchTemplateVersionsInput, ...func(*ec2.Options)) *ec2.DeleteLaunchTemplateVersionsOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DeleteLaunchTemplateVersionsOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DeleteLaunchTemplateVersionsInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DeleteLaunchTemplateVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteLaunchTemplateVersions'
+type MockEc2client_DeleteLaunchTemplateVersions_Call struct {
+	*mock.Call
+}
+
+// DeleteLaunchTemplateVersions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DeleteLaunchTemplateVersionsInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DeleteLaunchTemplateVersions(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DeleteLaunchTemplateVersions_Call {
+	return &MockEc2client_DeleteLaunchTemplateVersions_Call{Call: _e.mock.On("DeleteLaunchTemplateVersions",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DeleteLaunchTemplateVersions_Call) Run(run func(ctx context.Context, params *ec2.DeleteLaunchTemplateVersionsInput, optFns ...func(*ec2.Options))) *MockEc2client_DeleteLaunchTemplateVersions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DeleteLaunchTemplateVersionsInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DeleteLaunchTemplateVersions_Call) Return(_a0 *ec2.DeleteLaunchTemplateVersionsOutput, _a1 error) *MockEc2client_DeleteLaunchTemplateVersions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DeleteLaunchTemplateVersions_Call) RunAndReturn(run func(context.Context, *ec2.DeleteLaunchTemplateVersionsInput, ...func(*ec2.Options)) (*ec2.DeleteLaunchTemplateVersionsOutput, error)) *MockEc2client_DeleteLaunchTemplateVersions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteNetworkInterface provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DeleteNetworkInterface(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteNetworkInterface")
+	}
+
+	var r0 *ec2.DeleteNetworkInterfaceOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteNetworkInterfaceInput, ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteNetworkInterfaceInput, ...func(*ec2.Options)) *ec2.DeleteNetworkInterfaceOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DeleteNetworkInterfaceOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DeleteNetworkInterfaceInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DeleteNetworkInterface_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteNetworkInterface'
+type MockEc2client_DeleteNetworkInterface_Call struct {
+	*mock.Call
+}
+
+// DeleteNetworkInterface is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DeleteNetworkInterfaceInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DeleteNetworkInterface(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DeleteNetworkInterface_Call {
+	return &MockEc2client_DeleteNetworkInterface_Call{Call: _e.mock.On("DeleteNetworkInterface",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DeleteNetworkInterface_Call) Run(run func(ctx context.Context, params *ec2.DeleteNetworkInterfaceInput, optFns ...func(*ec2.Options))) *MockEc2client_DeleteNetworkInterface_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DeleteNetworkInterfaceInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DeleteNetworkInterface_Call) Return(_a0 *ec2.DeleteNetworkInterfaceOutput, _a1 error) *MockEc2client_DeleteNetworkInterface_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DeleteNetworkInterface_Call) RunAndReturn(run func(context.Context, *ec2.DeleteNetworkInterfaceInput, ...func(*ec2.Options)) (*ec2.DeleteNetworkInterfaceOutput, error)) *MockEc2client_DeleteNetworkInterface_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteSecurityGroup provides a mock function with given fields: ctx, params, optFns
 func (_m *MockEc2client) DeleteSecurityGroup(ctx context.Context, params *ec2.DeleteSecurityGroupInput, optFns ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error) {
 	_va := make([]interface{}, len(optFns))
@@ -35,23 +331,911 @@ func (_m *MockEc2client) DeleteSecurityGroup(ctx context.Context, params *ec2.De
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteSecurityGroup")
+		panic("no return value specified for DeleteSecurityGroup")
+	}
+
+	var r0 *ec2.DeleteSecurityGroupOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteSecurityGroupInput, ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteSecurityGroupInput, ...func(*ec2.Options)) *ec2.DeleteSecurityGroupOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DeleteSecurityGroupOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DeleteSecurityGroupInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DeleteSecurityGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSecurityGroup'
+type MockEc2client_DeleteSecurityGroup_Call struct {
+	*mock.Call
+}
+
+// DeleteSecurityGroup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DeleteSecurityGroupInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DeleteSecurityGroup(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DeleteSecurityGroup_Call {
+	return &MockEc2client_DeleteSecurityGroup_Call{Call: _e.mock.On("DeleteSecurityGroup",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DeleteSecurityGroup_Call) Run(run func(ctx context.Context, params *ec2.DeleteSecurityGroupInput, optFns ...func(*ec2.Options))) *MockEc2client_DeleteSecurityGroup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DeleteSecurityGroupInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DeleteSecurityGroup_Call) Return(_a0 *ec2.DeleteSecurityGroupOutput, _a1 error) *MockEc2client_DeleteSecurityGroup_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DeleteSecurityGroup_Call) RunAndReturn(run func(context.Context, *ec2.DeleteSecurityGroupInput, ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error)) *MockEc2client_DeleteSecurityGroup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteSnapshot provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DeleteSnapshot(ctx context.Context, params *ec2.DeleteSnapshotInput, optFns ...func(*ec2.Options)) (*ec2.DeleteSnapshotOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSnapshot")
+	}
+
+	var r0 *ec2.DeleteSnapshotOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteSnapshotInput, ...func(*ec2.Options)) (*ec2.DeleteSnapshotOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteSnapshotInput, ...func(*ec2.Options)) *ec2.DeleteSnapshotOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DeleteSnapshotOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DeleteSnapshotInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DeleteSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSnapshot'
+type MockEc2client_DeleteSnapshot_Call struct {
+	*mock.Call
+}
+
+// DeleteSnapshot is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DeleteSnapshotInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DeleteSnapshot(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DeleteSnapshot_Call {
+	return &MockEc2client_DeleteSnapshot_Call{Call: _e.mock.On("DeleteSnapshot",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DeleteSnapshot_Call) Run(run func(ctx context.Context, params *ec2.DeleteSnapshotInput, optFns ...func(*ec2.Options))) *MockEc2client_DeleteSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DeleteSnapshotInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DeleteSnapshot_Call) Return(_a0 *ec2.DeleteSnapshotOutput, _a1 error) *MockEc2client_DeleteSnapshot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DeleteSnapshot_Call) RunAndReturn(run func(context.Context, *ec2.DeleteSnapshotInput, ...func(*ec2.Options)) (*ec2.DeleteSnapshotOutput, error)) *MockEc2client_DeleteSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteVolume provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteVolume")
+	}
+
+	var r0 *ec2.DeleteVolumeOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteVolumeInput, ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteVolumeInput, ...func(*ec2.Options)) *ec2.DeleteVolumeOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DeleteVolumeOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DeleteVolumeInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DeleteVolume_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteVolume'
+type MockEc2client_DeleteVolume_Call struct {
+	*mock.Call
+}
+
+// DeleteVolume is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DeleteVolumeInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DeleteVolume(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DeleteVolume_Call {
+	return &MockEc2client_DeleteVolume_Call{Call: _e.mock.On("DeleteVolume",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DeleteVolume_Call) Run(run func(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options))) *MockEc2client_DeleteVolume_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DeleteVolumeInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DeleteVolume_Call) Return(_a0 *ec2.DeleteVolumeOutput, _a1 error) *MockEc2client_DeleteVolume_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DeleteVolume_Call) RunAndReturn(run func(context.Context, *ec2.DeleteVolumeInput, ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error)) *MockEc2client_DeleteVolume_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeregisterImage provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DeregisterImage(ctx context.Context, params *ec2.DeregisterImageInput, optFns ...func(*ec2.Options)) (*ec2.DeregisterImageOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeregisterImage")
+	}
+
+	var r0 *ec2.DeregisterImageOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeregisterImageInput, ...func(*ec2.Options)) (*ec2.DeregisterImageOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeregisterImageInput, ...func(*ec2.Options)) *ec2.DeregisterImageOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DeregisterImageOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DeregisterImageInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DeregisterImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeregisterImage'
+type MockEc2client_DeregisterImage_Call struct {
+	*mock.Call
+}
+
+// DeregisterImage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DeregisterImageInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DeregisterImage(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DeregisterImage_Call {
+	return &MockEc2client_DeregisterImage_Call{Call: _e.mock.On("DeregisterImage",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DeregisterImage_Call) Run(run func(ctx context.Context, params *ec2.DeregisterImageInput, optFns ...func(*ec2.Options))) *MockEc2client_DeregisterImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DeregisterImageInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DeregisterImage_Call) Return(_a0 *ec2.DeregisterImageOutput, _a1 error) *MockEc2client_DeregisterImage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DeregisterImage_Call) RunAndReturn(run func(context.Context, *ec2.DeregisterImageInput, ...func(*ec2.Options)) (*ec2.DeregisterImageOutput, error)) *MockEc2client_DeregisterImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeAddresses provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeAddresses")
+	}
+
+	var r0 *ec2.DescribeAddressesOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeAddressesInput, ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeAddressesInput, ...func(*ec2.Options)) *ec2.DescribeAddressesOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DescribeAddressesOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeAddressesInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DescribeAddresses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeAddresses'
+type MockEc2client_DescribeAddresses_Call struct {
+	*mock.Call
+}
+
+// DescribeAddresses is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DescribeAddressesInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DescribeAddresses(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeAddresses_Call {
+	return &MockEc2client_DescribeAddresses_Call{Call: _e.mock.On("DescribeAddresses",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DescribeAddresses_Call) Run(run func(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeAddresses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DescribeAddressesInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DescribeAddresses_Call) Return(_a0 *ec2.DescribeAddressesOutput, _a1 error) *MockEc2client_DescribeAddresses_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DescribeAddresses_Call) RunAndReturn(run func(context.Context, *ec2.DescribeAddressesInput, ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)) *MockEc2client_DescribeAddresses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeImages provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeImages")
+	}
+
+	var r0 *ec2.DescribeImagesOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeImagesInput, ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeImagesInput, ...func(*ec2.Options)) *ec2.DescribeImagesOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DescribeImagesOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeImagesInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DescribeImages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeImages'
+type MockEc2client_DescribeImages_Call struct {
+	*mock.Call
+}
+
+// DescribeImages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DescribeImagesInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DescribeImages(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeImages_Call {
+	return &MockEc2client_DescribeImages_Call{Call: _e.mock.On("DescribeImages",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DescribeImages_Call) Run(run func(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeImages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DescribeImagesInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DescribeImages_Call) Return(_a0 *ec2.DescribeImagesOutput, _a1 error) *MockEc2client_DescribeImages_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DescribeImages_Call) RunAndReturn(run func(context.Context, *ec2.DescribeImagesInput, ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)) *MockEc2client_DescribeImages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeInstances provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeInstances")
+	}
+
+	var r0 *ec2.DescribeInstancesOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeInstancesInput, ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeInstancesInput, ...func(*ec2.Options)) *ec2.DescribeInstancesOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DescribeInstancesOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeInstancesInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DescribeInstances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeInstances'
+type MockEc2client_DescribeInstances_Call struct {
+	*mock.Call
+}
+
+// DescribeInstances is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DescribeInstancesInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DescribeInstances(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeInstances_Call {
+	return &MockEc2client_DescribeInstances_Call{Call: _e.mock.On("DescribeInstances",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DescribeInstances_Call) Run(run func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeInstances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DescribeInstancesInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DescribeInstances_Call) Return(_a0 *ec2.DescribeInstancesOutput, _a1 error) *MockEc2client_DescribeInstances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DescribeInstances_Call) RunAndReturn(run func(context.Context, *ec2.DescribeInstancesInput, ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)) *MockEc2client_DescribeInstances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeLaunchTemplateVersions provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeLaunchTemplateVersions(ctx context.Context, params *ec2.DescribeLaunchTemplateVersionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeLaunchTemplateVersions")
+	}
+
+	var r0 *ec2.DescribeLaunchTemplateVersionsOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeLaunchTemplateVersionsInput, ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplateVersionsOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeLaunchTemplateVersionsInput, ...func(*ec2.Options)) *ec2.DescribeLaunchTemplateVersionsOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DescribeLaunchTemplateVersionsOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeLaunchTemplateVersionsInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DescribeLaunchTemplateVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeLaunchTemplateVersions'
+type MockEc2client_DescribeLaunchTemplateVersions_Call struct {
+	*mock.Call
+}
+
+// DescribeLaunchTemplateVersions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DescribeLaunchTemplateVersionsInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DescribeLaunchTemplateVersions(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeLaunchTemplateVersions_Call {
+	return &MockEc2client_DescribeLaunchTemplateVersions_Call{Call: _e.mock.On("DescribeLaunchTemplateVersions",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DescribeLaunchTemplateVersions_Call) Run(run func(ctx context.Context, params *ec2.DescribeLaunchTemplateVersionsInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeLaunchTemplateVersions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DescribeLaunchTemplateVersionsInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DescribeLaunchTemplateVersions_Call) Return(_a0 *ec2.DescribeLaunchTemplateVersionsOutput, _a1 error) *MockEc2client_DescribeLaunchTemplateVersions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DescribeLaunchTemplateVersions_Call) RunAndReturn(run func(context.Context, *ec2.DescribeLaunchTemplateVersionsInput, ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplateVersionsOutput, error)) *MockEc2client_DescribeLaunchTemplateVersions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeLaunchTemplates provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeLaunchTemplates(ctx context.Context, params *ec2.DescribeLaunchTemplatesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplatesOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeLaunchTemplates")
+	}
+
+	var r0 *ec2.DescribeLaunchTemplatesOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeLaunchTemplatesInput, ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplatesOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeLaunchTemplatesInput, ...func(*ec2.Options)) *ec2.DescribeLaunchTemplatesOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DescribeLaunchTemplatesOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeLaunchTemplatesInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DescribeLaunchTemplates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeLaunchTemplates'
+type MockEc2client_DescribeLaunchTemplates_Call struct {
+	*mock.Call
+}
+
+// DescribeLaunchTemplates is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DescribeLaunchTemplatesInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DescribeLaunchTemplates(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeLaunchTemplates_Call {
+	return &MockEc2client_DescribeLaunchTemplates_Call{Call: _e.mock.On("DescribeLaunchTemplates",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DescribeLaunchTemplates_Call) Run(run func(ctx context.Context, params *ec2.DescribeLaunchTemplatesInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeLaunchTemplates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DescribeLaunchTemplatesInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DescribeLaunchTemplates_Call) Return(_a0 *ec2.DescribeLaunchTemplatesOutput, _a1 error) *MockEc2client_DescribeLaunchTemplates_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DescribeLaunchTemplates_Call) RunAndReturn(run func(context.Context, *ec2.DescribeLaunchTemplatesInput, ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplatesOutput, error)) *MockEc2client_DescribeLaunchTemplates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeManagedPrefixLists provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeManagedPrefixLists(ctx context.Context, params *ec2.DescribeManagedPrefixListsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeManagedPrefixListsOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeManagedPrefixLists")
+	}
+
+	var r0 *ec2.DescribeManagedPrefixListsOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeManagedPrefixListsInput, ...func(*ec2.Options)) (*ec2.DescribeManagedPrefixListsOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeManagedPrefixListsInput, ...func(*ec2.Options)) *ec2.DescribeManagedPrefixListsOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DescribeManagedPrefixListsOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeManagedPrefixListsInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DescribeManagedPrefixLists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeManagedPrefixLists'
+type MockEc2client_DescribeManagedPrefixLists_Call struct {
+	*mock.Call
+}
+
+// DescribeManagedPrefixLists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DescribeManagedPrefixListsInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DescribeManagedPrefixLists(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeManagedPrefixLists_Call {
+	return &MockEc2client_DescribeManagedPrefixLists_Call{Call: _e.mock.On("DescribeManagedPrefixLists",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DescribeManagedPrefixLists_Call) Run(run func(ctx context.Context, params *ec2.DescribeManagedPrefixListsInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeManagedPrefixLists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DescribeManagedPrefixListsInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DescribeManagedPrefixLists_Call) Return(_a0 *ec2.DescribeManagedPrefixListsOutput, _a1 error) *MockEc2client_DescribeManagedPrefixLists_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DescribeManagedPrefixLists_Call) RunAndReturn(run func(context.Context, *ec2.DescribeManagedPrefixListsInput, ...func(*ec2.Options)) (*ec2.DescribeManagedPrefixListsOutput, error)) *MockEc2client_DescribeManagedPrefixLists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeNetworkInterfaces provides a mock function with given fields: ctx, params, opftFns
+func (_m *MockEc2client) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, opftFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	_va := make([]interface{}, len(opftFns))
+	for _i := range opftFns {
+		_va[_i] = opftFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeNetworkInterfaces")
+	}
+
+	var r0 *ec2.DescribeNetworkInterfacesOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeNetworkInterfacesInput, ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error)); ok {
+		return rf(ctx, params, opftFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeNetworkInterfacesInput, ...func(*ec2.Options)) *ec2.DescribeNetworkInterfacesOutput); ok {
+		r0 = rf(ctx, params, opftFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DescribeNetworkInterfacesOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeNetworkInterfacesInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, opftFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DescribeNetworkInterfaces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeNetworkInterfaces'
+type MockEc2client_DescribeNetworkInterfaces_Call struct {
+	*mock.Call
+}
+
+// DescribeNetworkInterfaces is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DescribeNetworkInterfacesInput
+//   - opftFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DescribeNetworkInterfaces(ctx interface{}, params interface{}, opftFns ...interface{}) *MockEc2client_DescribeNetworkInterfaces_Call {
+	return &MockEc2client_DescribeNetworkInterfaces_Call{Call: _e.mock.On("DescribeNetworkInterfaces",
+		append([]interface{}{ctx, params}, opftFns...)...)}
+}
+
+func (_c *MockEc2client_DescribeNetworkInterfaces_Call) Run(run func(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, opftFns ...func(*ec2.Options))) *MockEc2client_DescribeNetworkInterfaces_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DescribeNetworkInterfacesInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DescribeNetworkInterfaces_Call) Return(_a0 *ec2.DescribeNetworkInterfacesOutput, _a1 error) *MockEc2client_DescribeNetworkInterfaces_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DescribeNetworkInterfaces_Call) RunAndReturn(run func(context.Context, *ec2.DescribeNetworkInterfacesInput, ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error)) *MockEc2client_DescribeNetworkInterfaces_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeRegions provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeRegions")
+	}
+
+	var r0 *ec2.DescribeRegionsOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeRegionsInput, ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeRegionsInput, ...func(*ec2.Options)) *ec2.DescribeRegionsOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.DescribeRegionsOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeRegionsInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEc2client_DescribeRegions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeRegions'
+type MockEc2client_DescribeRegions_Call struct {
+	*mock.Call
+}
+
+// DescribeRegions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *ec2.DescribeRegionsInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) DescribeRegions(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeRegions_Call {
+	return &MockEc2client_DescribeRegions_Call{Call: _e.mock.On("DescribeRegions",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MockEc2client_DescribeRegions_Call) Run(run func(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeRegions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*ec2.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*ec2.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*ec2.DescribeRegionsInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockEc2client_DescribeRegions_Call) Return(_a0 *ec2.DescribeRegionsOutput, _a1 error) *MockEc2client_DescribeRegions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEc2client_DescribeRegions_Call) RunAndReturn(run func(context.Context, *ec2.DescribeRegionsInput, ...func(*ec2.Options)) (*ec2.DescribeRegionsOutput, error)) *MockEc2client_DescribeRegions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeSecurityGroups provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeSecurityGroups")
 	}
 
-	var r0 *ec2.DeleteSecurityGroupOutput
+	var r0 *ec2.DescribeSecurityGroupsOutput
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteSecurityGroupInput, ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeSecurityGroupsInput, ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)); ok {
 		return rf(ctx, params, optFns...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteSecurityGroupInput, ...func(*ec2.Options)) *ec2.DeleteSecurityGroupOutput); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeSecurityGroupsInput, ...func(*ec2.Options)) *ec2.DescribeSecurityGroupsOutput); ok {
 		r0 = rf(ctx, params, optFns...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ec2.DeleteSecurityGroupOutput)
+			r0 = ret.Get(0).(*ec2.DescribeSecurityGroupsOutput)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DeleteSecurityGroupInput, ...func(*ec2.Options)) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeSecurityGroupsInput, ...func(*ec2.Options)) error); ok {
 		r1 = rf(ctx, params, optFns...)
 	} else {
 		r1 = ret.Error(1)
@@ -60,21 +1244,21 @@ func (_m *MockEc2client) DeleteSecurityGroup(ctx context.Context, params *ec2.De
 	return r0, r1
 }
 
-// MockEc2client_DeleteSecurityGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSecurityGroup'
-type MockEc2client_DeleteSecurityGroup_Call struct {
+// MockEc2client_DescribeSecurityGroups_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeSecurityGroups'
+type MockEc2client_DescribeSecurityGroups_Call struct {
 	*mock.Call
 }
 
-// DeleteSecurityGroup is a helper method to define mock.On call
+// DescribeSecurityGroups is a helper method to define mock.On call
 //   - ctx context.Context
-//   - params *ec2.DeleteSecurityGroupInput
+//   - params *ec2.DescribeSecurityGroupsInput
 //   - optFns ...func(*ec2.Options)
-func (_e *MockEc2client_Expecter) DeleteSecurityGroup(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DeleteSecurityGroup_Call {
-	return &MockEc2client_DeleteSecurityGroup_Call{Call: _e.mock.On("DeleteSecurityGroup",
+func (_e *MockEc2client_Expecter) DescribeSecurityGroups(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeSecurityGroups_Call {
+	return &MockEc2client_DescribeSecurityGroups_Call{Call: _e.mock.On("DescribeSecurityGroups",
 		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *MockEc2client_DeleteSecurityGroup_Call) Run(run func(ctx context.Context, params *ec2.DeleteSecurityGroupInput, optFns ...func(*ec2.Options))) *MockEc2client_DeleteSecurityGroup_Call {
+func (_c *MockEc2client_DescribeSecurityGroups_Call) Run(run func(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeSecurityGroups_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]func(*ec2.Options), len(args)-2)
 		for i, a := range args[2:] {
@@ -82,23 +1266,23 @@ func (_c *MockEc2client_DeleteSecurityGroup_Call) Run(run func(ctx context.Conte
 				variadicArgs[i] = a.(func(*ec2.Options))
 			}
 		}
-		run(args[0].(context.Context), args[1].(*ec2.DeleteSecurityGroupInput), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*ec2.DescribeSecurityGroupsInput), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockEc2client_DeleteSecurityGroup_Call) Return(_a0 *ec2.DeleteSecurityGroupOutput, _a1 error) *MockEc2client_DeleteSecurityGroup_Call {
+func (_c *MockEc2client_DescribeSecurityGroups_Call) Return(_a0 *ec2.DescribeSecurityGroupsOutput, _a1 error) *MockEc2client_DescribeSecurityGroups_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockEc2client_DeleteSecurityGroup_Call) RunAndReturn(run func(context.Context, *ec2.DeleteSecurityGroupInput, ...func(*ec2.Options)) (*ec2.DeleteSecurityGroupOutput, error)) *MockEc2client_DeleteSecurityGroup_Call {
+func (_c *MockEc2client_DescribeSecurityGroups_Call) RunAndReturn(run func(context.Context, *ec2.DescribeSecurityGroupsInput, ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)) *MockEc2client_DescribeSecurityGroups_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteVolume provides a mock function with given fields: ctx, params, optFns
-func (_m *MockEc2client) DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error) {
+// DescribeSnapshots provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeSnapshots(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
 	_va := make([]interface{}, len(optFns))
 	for _i := range optFns {
 		_va[_i] = optFns[_i]
@@ -109,23 +1293,23 @@ func (_m *MockEc2client) DeleteVolume(ctx context.Context, params *ec2.DeleteVol
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteVolume")
+		panic("no return value specified for DescribeSnapshots")
 	}
 
-	var r0 *ec2.DeleteVolumeOutput
+	var r0 *ec2.DescribeSnapshotsOutput
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteVolumeInput, ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeSnapshotsInput, ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error)); ok {
 		return rf(ctx, params, optFns...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeleteVolumeInput, ...func(*ec2.Options)) *ec2.DeleteVolumeOutput); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeSnapshotsInput, ...func(*ec2.Options)) *ec2.DescribeSnapshotsOutput); ok {
 		r0 = rf(ctx, params, optFns...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ec2.DeleteVolumeOutput)
+			r0 = ret.Get(0).(*ec2.DescribeSnapshotsOutput)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DeleteVolumeInput, ...func(*ec2.Options)) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeSnapshotsInput, ...func(*ec2.Options)) error); ok {
 		r1 = rf(ctx, params, optFns...)
 	} else {
 		r1 = ret.Error(1)
@@ -134,21 +1318,21 @@ func (_m *MockEc2client) DeleteVolume(ctx context.Context, params *ec2.DeleteVol
 	return r0, r1
 }
 
-// MockEc2client_DeleteVolume_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteVolume'
-type MockEc2client_DeleteVolume_Call struct {
+// MockEc2client_DescribeSnapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeSnapshots'
+type MockEc2client_DescribeSnapshots_Call struct {
 	*mock.Call
 }
 
-// DeleteVolume is a helper method to define mock.On call
+// DescribeSnapshots is a helper method to define mock.On call
 //   - ctx context.Context
-//   - params *ec2.DeleteVolumeInput
+//   - params *ec2.DescribeSnapshotsInput
 //   - optFns ...func(*ec2.Options)
-func (_e *MockEc2client_Expecter) DeleteVolume(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DeleteVolume_Call {
-	return &MockEc2client_DeleteVolume_Call{Call: _e.mock.On("DeleteVolume",
+func (_e *MockEc2client_Expecter) DescribeSnapshots(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeSnapshots_Call {
+	return &MockEc2client_DescribeSnapshots_Call{Call: _e.mock.On("DescribeSnapshots",
 		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *MockEc2client_DeleteVolume_Call) Run(run func(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options))) *MockEc2client_DeleteVolume_Call {
+func (_c *MockEc2client_DescribeSnapshots_Call) Run(run func(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeSnapshots_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]func(*ec2.Options), len(args)-2)
 		for i, a := range args[2:] {
@@ -156,23 +1340,23 @@ func (_c *MockEc2client_DeleteVolume_Call) Run(run func(ctx context.Context, par
 				variadicArgs[i] = a.(func(*ec2.Options))
 			}
 		}
-		run(args[0].(context.Context), args[1].(*ec2.DeleteVolumeInput), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*ec2.DescribeSnapshotsInput), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockEc2client_DeleteVolume_Call) Return(_a0 *ec2.DeleteVolumeOutput, _a1 error) *MockEc2client_DeleteVolume_Call {
+func (_c *MockEc2client_DescribeSnapshots_Call) Return(_a0 *ec2.DescribeSnapshotsOutput, _a1 error) *MockEc2client_DescribeSnapshots_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockEc2client_DeleteVolume_Call) RunAndReturn(run func(context.Context, *ec2.DeleteVolumeInput, ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error)) *MockEc2client_DeleteVolume_Call {
+func (_c *MockEc2client_DescribeSnapshots_Call) RunAndReturn(run func(context.Context, *ec2.DescribeSnapshotsInput, ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error)) *MockEc2client_DescribeSnapshots_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeregisterImage provides a mock function with given fields: ctx, params, optFns
-func (_m *MockEc2client) DeregisterImage(ctx context.Context, params *ec2.DeregisterImageInput, optFns ...func(*ec2.Options)) (*ec2.DeregisterImageOutput, error) {
+// DescribeStaleSecurityGroups provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeStaleSecurityGroups(ctx context.Context, params *ec2.DescribeStaleSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeStaleSecurityGroupsOutput, error) {
 	_va := make([]interface{}, len(optFns))
 	for _i := range optFns {
 		_va[_i] = optFns[_i]
@@ -183,23 +1367,23 @@ func (_m *MockEc2client) DeregisterImage(ctx context.Context, params *ec2.Deregi
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeregisterImage")
+		panic("no return value specified for DescribeStaleSecurityGroups")
 	}
 
-	var r0 *ec2.DeregisterImageOutput
+	var r0 *ec2.DescribeStaleSecurityGroupsOutput
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeregisterImageInput, ...func(*ec2.Options)) (*ec2.DeregisterImageOutput, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeStaleSecurityGroupsInput, ...func(*ec2.Options)) (*ec2.DescribeStaleSecurityGroupsOutput, error)); ok {
 		return rf(ctx, params, optFns...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DeregisterImageInput, ...func(*ec2.Options)) *ec2.DeregisterImageOutput); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeStaleSecurityGroupsInput, ...func(*ec2.Options)) *ec2.DescribeStaleSecurityGroupsOutput); ok {
 		r0 = rf(ctx, params, optFns...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ec2.DeregisterImageOutput)
+			r0 = ret.Get(0).(*ec2.DescribeStaleSecurityGroupsOutput)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DeregisterImageInput, ...func(*ec2.Options)) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeStaleSecurityGroupsInput, ...func(*ec2.Options)) error); ok {
 		r1 = rf(ctx, params, optFns...)
 	} else {
 		r1 = ret.Error(1)
@@ -208,21 +1392,21 @@ func (_m *MockEc2client) DeregisterImage(ctx context.Context, params *ec2.Deregi
 	return r0, r1
 }
 
-// MockEc2client_DeregisterImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeregisterImage'
-type MockEc2client_DeregisterImage_Call struct {
+// MockEc2client_DescribeStaleSecurityGroups_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeStaleSecurityGroups'
+type MockEc2client_DescribeStaleSecurityGroups_Call struct {
 	*mock.Call
 }
 
-// DeregisterImage is a helper method to define mock.On call
+// DescribeStaleSecurityGroups is a helper method to define mock.On call
 //   - ctx context.Context
-//   - params *ec2.DeregisterImageInput
+//   - params *ec2.DescribeStaleSecurityGroupsInput
 //   - optFns ...func(*ec2.Options)
-func (_e *MockEc2client_Expecter) DeregisterImage(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DeregisterImage_Call {
-	return &MockEc2client_DeregisterImage_Call{Call: _e.mock.On("DeregisterImage",
+func (_e *MockEc2client_Expecter) DescribeStaleSecurityGroups(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeStaleSecurityGroups_Call {
+	return &MockEc2client_DescribeStaleSecurityGroups_Call{Call: _e.mock.On("DescribeStaleSecurityGroups",
 		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *MockEc2client_DeregisterImage_Call) Run(run func(ctx context.Context, params *ec2.DeregisterImageInput, optFns ...func(*ec2.Options))) *MockEc2client_DeregisterImage_Call {
+func (_c *MockEc2client_DescribeStaleSecurityGroups_Call) Run(run func(ctx context.Context, params *ec2.DescribeStaleSecurityGroupsInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeStaleSecurityGroups_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]func(*ec2.Options), len(args)-2)
 		for i, a := range args[2:] {
@@ -230,23 +1414,23 @@ func (_c *MockEc2client_DeregisterImage_Call) Run(run func(ctx context.Context,
 				variadicArgs[i] = a.(func(*ec2.Options))
 			}
 		}
-		run(args[0].(context.Context), args[1].(*ec2.DeregisterImageInput), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*ec2.DescribeStaleSecurityGroupsInput), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockEc2client_DeregisterImage_Call) Return(_a0 *ec2.DeregisterImageOutput, _a1 error) *MockEc2client_DeregisterImage_Call {
+func (_c *MockEc2client_DescribeStaleSecurityGroups_Call) Return(_a0 *ec2.DescribeStaleSecurityGroupsOutput, _a1 error) *MockEc2client_DescribeStaleSecurityGroups_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockEc2client_DeregisterImage_Call) RunAndReturn(run func(context.Context, *ec2.DeregisterImageInput, ...func(*ec2.Options)) (*ec2.DeregisterImageOutput, error)) *MockEc2client_DeregisterImage_Call {
+func (_c *MockEc2client_DescribeStaleSecurityGroups_Call) RunAndReturn(run func(context.Context, *ec2.DescribeStaleSecurityGroupsInput, ...func(*ec2.Options)) (*ec2.DescribeStaleSecurityGroupsOutput, error)) *MockEc2client_DescribeStaleSecurityGroups_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DescribeImages provides a mock function with given fields: ctx, params, optFns
-func (_m *MockEc2client) DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+// DescribeVolumes provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
 	_va := make([]interface{}, len(optFns))
 	for _i := range optFns {
 		_va[_i] = optFns[_i]
@@ -257,23 +1441,23 @@ func (_m *MockEc2client) DescribeImages(ctx context.Context, params *ec2.Describ
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DescribeImages")
+		panic("no return value specified for DescribeVolumes")
 	}
 
-	var r0 *ec2.DescribeImagesOutput
+	var r0 *ec2.DescribeVolumesOutput
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeImagesInput, ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)); ok {
 		return rf(ctx, params, optFns...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeImagesInput, ...func(*ec2.Options)) *ec2.DescribeImagesOutput); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) *ec2.DescribeVolumesOutput); ok {
 		r0 = rf(ctx, params, optFns...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ec2.DescribeImagesOutput)
+			r0 = ret.Get(0).(*ec2.DescribeVolumesOutput)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeImagesInput, ...func(*ec2.Options)) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) error); ok {
 		r1 = rf(ctx, params, optFns...)
 	} else {
 		r1 = ret.Error(1)
@@ -282,21 +1466,21 @@ func (_m *MockEc2client) DescribeImages(ctx context.Context, params *ec2.Describ
 	return r0, r1
 }
 
-// MockEc2client_DescribeImages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeImages'
-type MockEc2client_DescribeImages_Call struct {
+// MockEc2client_DescribeVolumes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeVolumes'
+type MockEc2client_DescribeVolumes_Call struct {
 	*mock.Call
 }
 
-// DescribeImages is a helper method to define mock.On call
+// DescribeVolumes is a helper method to define mock.On call
 //   - ctx context.Context
-//   - params *ec2.DescribeImagesInput
+//   - params *ec2.DescribeVolumesInput
 //   - optFns ...func(*ec2.Options)
-func (_e *MockEc2client_Expecter) DescribeImages(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeImages_Call {
-	return &MockEc2client_DescribeImages_Call{Call: _e.mock.On("DescribeImages",
+func (_e *MockEc2client_Expecter) DescribeVolumes(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeVolumes_Call {
+	return &MockEc2client_DescribeVolumes_Call{Call: _e.mock.On("DescribeVolumes",
 		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *MockEc2client_DescribeImages_Call) Run(run func(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeImages_Call {
+func (_c *MockEc2client_DescribeVolumes_Call) Run(run func(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeVolumes_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]func(*ec2.Options), len(args)-2)
 		for i, a := range args[2:] {
@@ -304,23 +1488,23 @@ func (_c *MockEc2client_DescribeImages_Call) Run(run func(ctx context.Context, p
 				variadicArgs[i] = a.(func(*ec2.Options))
 			}
 		}
-		run(args[0].(context.Context), args[1].(*ec2.DescribeImagesInput), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*ec2.DescribeVolumesInput), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockEc2client_DescribeImages_Call) Return(_a0 *ec2.DescribeImagesOutput, _a1 error) *MockEc2client_DescribeImages_Call {
+func (_c *MockEc2client_DescribeVolumes_Call) Return(_a0 *ec2.DescribeVolumesOutput, _a1 error) *MockEc2client_DescribeVolumes_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockEc2client_DescribeImages_Call) RunAndReturn(run func(context.Context, *ec2.DescribeImagesInput, ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)) *MockEc2client_DescribeImages_Call {
+func (_c *MockEc2client_DescribeVolumes_Call) RunAndReturn(run func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)) *MockEc2client_DescribeVolumes_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DescribeInstances provides a mock function with given fields: ctx, params, optFns
-func (_m *MockEc2client) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+// DisableImage provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) DisableImage(ctx context.Context, params *ec2.DisableImageInput, optFns ...func(*ec2.Options)) (*ec2.DisableImageOutput, error) {
 	_va := make([]interface{}, len(optFns))
 	for _i := range optFns {
 		_va[_i] = optFns[_i]
@@ -331,23 +1515,23 @@ func (_m *MockEc2client) DescribeInstances(ctx context.Context, params *ec2.Desc
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DescribeInstances")
+		panic("no return value specified for DisableImage")
 	}
 
-	var r0 *ec2.DescribeInstancesOutput
+	var r0 *ec2.DisableImageOutput
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeInstancesInput, ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DisableImageInput, ...func(*ec2.Options)) (*ec2.DisableImageOutput, error)); ok {
 		return rf(ctx, params, optFns...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeInstancesInput, ...func(*ec2.Options)) *ec2.DescribeInstancesOutput); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DisableImageInput, ...func(*ec2.Options)) *ec2.DisableImageOutput); ok {
 		r0 = rf(ctx, params, optFns...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ec2.DescribeInstancesOutput)
+			r0 = ret.Get(0).(*ec2.DisableImageOutput)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeInstancesInput, ...func(*ec2.Options)) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DisableImageInput, ...func(*ec2.Options)) error); ok {
 		r1 = rf(ctx, params, optFns...)
 	} else {
 		r1 = ret.Error(1)
@@ -356,21 +1540,21 @@ func (_m *MockEc2client) DescribeInstances(ctx context.Context, params *ec2.Desc
 	return r0, r1
 }
 
-// MockEc2client_DescribeInstances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeInstances'
-type MockEc2client_DescribeInstances_Call struct {
+// MockEc2client_DisableImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableImage'
+type MockEc2client_DisableImage_Call struct {
 	*mock.Call
 }
 
-// DescribeInstances is a helper method to define mock.On call
+// DisableImage is a helper method to define mock.On call
 //   - ctx context.Context
-//   - params *ec2.DescribeInstancesInput
+//   - params *ec2.DisableImageInput
 //   - optFns ...func(*ec2.Options)
-func (_e *MockEc2client_Expecter) DescribeInstances(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeInstances_Call {
-	return &MockEc2client_DescribeInstances_Call{Call: _e.mock.On("DescribeInstances",
+func (_e *MockEc2client_Expecter) DisableImage(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DisableImage_Call {
+	return &MockEc2client_DisableImage_Call{Call: _e.mock.On("DisableImage",
 		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *MockEc2client_DescribeInstances_Call) Run(run func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeInstances_Call {
+func (_c *MockEc2client_DisableImage_Call) Run(run func(ctx context.Context, params *ec2.DisableImageInput, optFns ...func(*ec2.Options))) *MockEc2client_DisableImage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]func(*ec2.Options), len(args)-2)
 		for i, a := range args[2:] {
@@ -378,23 +1562,23 @@ func (_c *MockEc2client_DescribeInstances_Call) Run(run func(ctx context.Context
 				variadicArgs[i] = a.(func(*ec2.Options))
 			}
 		}
-		run(args[0].(context.Context), args[1].(*ec2.DescribeInstancesInput), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*ec2.DisableImageInput), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockEc2client_DescribeInstances_Call) Return(_a0 *ec2.DescribeInstancesOutput, _a1 error) *MockEc2client_DescribeInstances_Call {
+func (_c *MockEc2client_DisableImage_Call) Return(_a0 *ec2.DisableImageOutput, _a1 error) *MockEc2client_DisableImage_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockEc2client_DescribeInstances_Call) RunAndReturn(run func(context.Context, *ec2.DescribeInstancesInput, ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)) *MockEc2client_DescribeInstances_Call {
+func (_c *MockEc2client_DisableImage_Call) RunAndReturn(run func(context.Context, *ec2.DisableImageInput, ...func(*ec2.Options)) (*ec2.DisableImageOutput, error)) *MockEc2client_DisableImage_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DescribeLaunchTemplateVersions provides a mock function with given fields: ctx, params, optFns
-func (_m *MockEc2client) DescribeLaunchTemplateVersions(ctx context.Context, params *ec2.DescribeLaunchTemplateVersionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+// EnableImageDeprecation provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) EnableImageDeprecation(ctx context.Context, params *ec2.EnableImageDeprecationInput, optFns ...func(*ec2.Options)) (*ec2.EnableImageDeprecationOutput, error) {
 	_va := make([]interface{}, len(optFns))
 	for _i := range optFns {
 		_va[_i] = optFns[_i]
@@ -405,23 +1589,23 @@ func (_m *MockEc2client) DescribeLaunchTemplateVersions(ctx context.Context, par
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DescribeLaunchTemplateVersions")
+		panic("no return value specified for EnableImageDeprecation")
 	}
 
-	var r0 *ec2.DescribeLaunchTemplateVersionsOutput
+	var r0 *ec2.EnableImageDeprecationOutput
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeLaunchTemplateVersionsInput, ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplateVersionsOutput, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.EnableImageDeprecationInput, ...func(*ec2.Options)) (*ec2.EnableImageDeprecationOutput, error)); ok {
 		return rf(ctx, params, optFns...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeLaunchTemplateVersionsInput, ...func(*ec2.Options)) *ec2.DescribeLaunchTemplateVersionsOutput); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.EnableImageDeprecationInput, ...func(*ec2.Options)) *ec2.EnableImageDeprecationOutput); ok {
 		r0 = rf(ctx, params, optFns...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ec2.DescribeLaunchTemplateVersionsOutput)
+			r0 = ret.Get(0).(*ec2.EnableImageDeprecationOutput)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeLaunchTemplateVersionsInput, ...func(*ec2.Options)) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.EnableImageDeprecationInput, ...func(*ec2.Options)) error); ok {
 		r1 = rf(ctx, params, optFns...)
 	} else {
 		r1 = ret.Error(1)
@@ -430,21 +1614,21 @@ func (_m *MockEc2client) DescribeLaunchTemplateVersions(ctx context.Context, par
 	return r0, r1
 }
 
-// MockEc2client_DescribeLaunchTemplateVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeLaunchTemplateVersions'
-type MockEc2client_DescribeLaunchTemplateVersions_Call struct {
+// MockEc2client_EnableImageDeprecation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableImageDeprecation'
+type MockEc2client_EnableImageDeprecation_Call struct {
 	*mock.Call
 }
 
-// DescribeLaunchTemplateVersions is a helper method to define mock.On call
+// EnableImageDeprecation is a helper method to define mock.On call
 //   - ctx context.Context
-//   - params *ec2.DescribeLaunchTemplateVersionsInput
+//   - params *ec2.EnableImageDeprecationInput
 //   - optFns ...func(*ec2.Options)
-func (_e *MockEc2client_Expecter) DescribeLaunchTemplateVersions(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeLaunchTemplateVersions_Call {
-	return &MockEc2client_DescribeLaunchTemplateVersions_Call{Call: _e.mock.On("DescribeLaunchTemplateVersions",
+func (_e *MockEc2client_Expecter) EnableImageDeprecation(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_EnableImageDeprecation_Call {
+	return &MockEc2client_EnableImageDeprecation_Call{Call: _e.mock.On("EnableImageDeprecation",
 		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *MockEc2client_DescribeLaunchTemplateVersions_Call) Run(run func(ctx context.Context, params *ec2.DescribeLaunchTemplateVersionsInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeLaunchTemplateVersions_Call {
+func (_c *MockEc2client_EnableImageDeprecation_Call) Run(run func(ctx context.Context, params *ec2.EnableImageDeprecationInput, optFns ...func(*ec2.Options))) *MockEc2client_EnableImageDeprecation_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]func(*ec2.Options), len(args)-2)
 		for i, a := range args[2:] {
@@ -452,26 +1636,26 @@ func (_c *MockEc2client_DescribeLaunchTemplateVersions_Call) Run(run func(ctx co
 				variadicArgs[i] = a.(func(*ec2.Options))
 			}
 		}
-		run(args[0].(context.Context), args[1].(*ec2.DescribeLaunchTemplateVersionsInput), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*ec2.EnableImageDeprecationInput), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockEc2client_DescribeLaunchTemplateVersions_Call) Return(_a0 *ec2.DescribeLaunchTemplateVersionsOutput, _a1 error) *MockEc2client_DescribeLaunchTemplateVersions_Call {
+func (_c *MockEc2client_EnableImageDeprecation_Call) Return(_a0 *ec2.EnableImageDeprecationOutput, _a1 error) *MockEc2client_EnableImageDeprecation_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockEc2client_DescribeLaunchTemplateVersions_Call) RunAndReturn(run func(context.Context, *ec2.DescribeLaunchTemplateVersionsInput, ...func(*ec2.Options)) (*ec2.DescribeLaunchTemplateVersionsOutput, error)) *MockEc2client_DescribeLaunchTemplateVersions_Call {
+func (_c *MockEc2client_EnableImageDeprecation_Call) RunAndReturn(run func(context.Context, *ec2.EnableImageDeprecationInput, ...func(*ec2.Options)) (*ec2.EnableImageDeprecationOutput, error)) *MockEc2client_EnableImageDeprecation_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DescribeNetworkInterfaces provides a mock function with given fields: ctx, params, opftFns
-func (_m *MockEc2client) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, opftFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
-	_va := make([]interface{}, len(opftFns))
-	for _i := range opftFns {
-		_va[_i] = opftFns[_i]
+// ReleaseAddress provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) ReleaseAddress(ctx context.Context, params *ec2.ReleaseAddressInput, optFns ...func(*ec2.Options)) (*ec2.ReleaseAddressOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
 	}
 	var _ca []interface{}
 	_ca = append(_ca, ctx, params)
@@ -479,24 +1663,24 @@ func (_m *MockEc2client) DescribeNetworkInterfaces(ctx context.Context, params *
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DescribeNetworkInterfaces")
+		panic("no return value specified for ReleaseAddress")
 	}
 
-	var r0 *ec2.DescribeNetworkInterfacesOutput
+	var r0 *ec2.ReleaseAddressOutput
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeNetworkInterfacesInput, ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error)); ok {
-		return rf(ctx, params, opftFns...)
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.ReleaseAddressInput, ...func(*ec2.Options)) (*ec2.ReleaseAddressOutput, error)); ok {
+		return rf(ctx, params, optFns...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeNetworkInterfacesInput, ...func(*ec2.Options)) *ec2.DescribeNetworkInterfacesOutput); ok {
-		r0 = rf(ctx, params, opftFns...)
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.ReleaseAddressInput, ...func(*ec2.Options)) *ec2.ReleaseAddressOutput); ok {
+		r0 = rf(ctx, params, optFns...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ec2.DescribeNetworkInterfacesOutput)
+			r0 = ret.Get(0).(*ec2.ReleaseAddressOutput)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeNetworkInterfacesInput, ...func(*ec2.Options)) error); ok {
-		r1 = rf(ctx, params, opftFns...)
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.ReleaseAddressInput, ...func(*ec2.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -504,21 +1688,21 @@ func (_m *MockEc2client) DescribeNetworkInterfaces(ctx context.Context, params *
 	return r0, r1
 }
 
-// MockEc2client_DescribeNetworkInterfaces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeNetworkInterfaces'
-type MockEc2client_DescribeNetworkInterfaces_Call struct {
+// MockEc2client_ReleaseAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseAddress'
+type MockEc2client_ReleaseAddress_Call struct {
 	*mock.Call
 }
 
-// DescribeNetworkInterfaces is a helper method to define mock.On call
+// ReleaseAddress is a helper method to define mock.On call
 //   - ctx context.Context
-//   - params *ec2.DescribeNetworkInterfacesInput
-//   - opftFns ...func(*ec2.Options)
-func (_e *MockEc2client_Expecter) DescribeNetworkInterfaces(ctx interface{}, params interface{}, opftFns ...interface{}) *MockEc2client_DescribeNetworkInterfaces_Call {
-	return &MockEc2client_DescribeNetworkInterfaces_Call{Call: _e.mock.On("DescribeNetworkInterfaces",
-		append([]interface{}{ctx, params}, opftFns...)...)}
+//   - params *ec2.ReleaseAddressInput
+//   - optFns ...func(*ec2.Options)
+func (_e *MockEc2client_Expecter) ReleaseAddress(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_ReleaseAddress_Call {
+	return &MockEc2client_ReleaseAddress_Call{Call: _e.mock.On("ReleaseAddress",
+		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *MockEc2client_DescribeNetworkInterfaces_Call) Run(run func(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, opftFns ...func(*ec2.Options))) *MockEc2client_DescribeNetworkInterfaces_Call {
+func (_c *MockEc2client_ReleaseAddress_Call) Run(run func(ctx context.Context, params *ec2.ReleaseAddressInput, optFns ...func(*ec2.Options))) *MockEc2client_ReleaseAddress_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]func(*ec2.Options), len(args)-2)
 		for i, a := range args[2:] {
@@ -526,23 +1710,23 @@ func (_c *MockEc2client_DescribeNetworkInterfaces_Call) Run(run func(ctx context
 				variadicArgs[i] = a.(func(*ec2.Options))
 			}
 		}
-		run(args[0].(context.Context), args[1].(*ec2.DescribeNetworkInterfacesInput), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*ec2.ReleaseAddressInput), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockEc2client_DescribeNetworkInterfaces_Call) Return(_a0 *ec2.DescribeNetworkInterfacesOutput, _a1 error) *MockEc2client_DescribeNetworkInterfaces_Call {
+func (_c *MockEc2client_ReleaseAddress_Call) Return(_a0 *ec2.ReleaseAddressOutput, _a1 error) *MockEc2client_ReleaseAddress_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockEc2client_DescribeNetworkInterfaces_Call) RunAndReturn(run func(context.Context, *ec2.DescribeNetworkInterfacesInput, ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error)) *MockEc2client_DescribeNetworkInterfaces_Call {
+func (_c *MockEc2client_ReleaseAddress_Call) RunAndReturn(run func(context.Context, *ec2.ReleaseAddressInput, ...func(*ec2.Options)) (*ec2.ReleaseAddressOutput, error)) *MockEc2client_ReleaseAddress_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DescribeSecurityGroups provides a mock function with given fields: ctx, params, optFns
-func (_m *MockEc2client) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+// RevokeSecurityGroupEgress provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) RevokeSecurityGroupEgress(ctx context.Context, params *ec2.RevokeSecurityGroupEgressInput, optFns ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupEgressOutput, error) {
 	_va := make([]interface{}, len(optFns))
 	for _i := range optFns {
 		_va[_i] = optFns[_i]
@@ -553,23 +1737,23 @@ func (_m *MockEc2client) DescribeSecurityGroups(ctx context.Context, params *ec2
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DescribeSecurityGroups")
+		panic("no return value specified for RevokeSecurityGroupEgress")
 	}
 
-	var r0 *ec2.DescribeSecurityGroupsOutput
+	var r0 *ec2.RevokeSecurityGroupEgressOutput
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeSecurityGroupsInput, ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.RevokeSecurityGroupEgressInput, ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupEgressOutput, error)); ok {
 		return rf(ctx, params, optFns...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeSecurityGroupsInput, ...func(*ec2.Options)) *ec2.DescribeSecurityGroupsOutput); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.RevokeSecurityGroupEgressInput, ...func(*ec2.Options)) *ec2.RevokeSecurityGroupEgressOutput); ok {
 		r0 = rf(ctx, params, optFns...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ec2.DescribeSecurityGroupsOutput)
+			r0 = ret.Get(0).(*ec2.RevokeSecurityGroupEgressOutput)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeSecurityGroupsInput, ...func(*ec2.Options)) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.RevokeSecurityGroupEgressInput, ...func(*ec2.Options)) error); ok {
 		r1 = rf(ctx, params, optFns...)
 	} else {
 		r1 = ret.Error(1)
@@ -578,21 +1762,21 @@ func (_m *MockEc2client) DescribeSecurityGroups(ctx context.Context, params *ec2
 	return r0, r1
 }
 
-// MockEc2client_DescribeSecurityGroups_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeSecurityGroups'
-type MockEc2client_DescribeSecurityGroups_Call struct {
+// MockEc2client_RevokeSecurityGroupEgress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSecurityGroupEgress'
+type MockEc2client_RevokeSecurityGroupEgress_Call struct {
 	*mock.Call
 }
 
-// DescribeSecurityGroups is a helper method to define mock.On call
+// RevokeSecurityGroupEgress is a helper method to define mock.On call
 //   - ctx context.Context
-//   - params *ec2.DescribeSecurityGroupsInput
+//   - params *ec2.RevokeSecurityGroupEgressInput
 //   - optFns ...func(*ec2.Options)
-func (_e *MockEc2client_Expecter) DescribeSecurityGroups(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeSecurityGroups_Call {
-	return &MockEc2client_DescribeSecurityGroups_Call{Call: _e.mock.On("DescribeSecurityGroups",
+func (_e *MockEc2client_Expecter) RevokeSecurityGroupEgress(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_RevokeSecurityGroupEgress_Call {
+	return &MockEc2client_RevokeSecurityGroupEgress_Call{Call: _e.mock.On("RevokeSecurityGroupEgress",
 		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *MockEc2client_DescribeSecurityGroups_Call) Run(run func(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeSecurityGroups_Call {
+func (_c *MockEc2client_RevokeSecurityGroupEgress_Call) Run(run func(ctx context.Context, params *ec2.RevokeSecurityGroupEgressInput, optFns ...func(*ec2.Options))) *MockEc2client_RevokeSecurityGroupEgress_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]func(*ec2.Options), len(args)-2)
 		for i, a := range args[2:] {
@@ -600,23 +1784,23 @@ func (_c *MockEc2client_DescribeSecurityGroups_Call) Run(run func(ctx context.Co
 				variadicArgs[i] = a.(func(*ec2.Options))
 			}
 		}
-		run(args[0].(context.Context), args[1].(*ec2.DescribeSecurityGroupsInput), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*ec2.RevokeSecurityGroupEgressInput), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockEc2client_DescribeSecurityGroups_Call) Return(_a0 *ec2.DescribeSecurityGroupsOutput, _a1 error) *MockEc2client_DescribeSecurityGroups_Call {
+func (_c *MockEc2client_RevokeSecurityGroupEgress_Call) Return(_a0 *ec2.RevokeSecurityGroupEgressOutput, _a1 error) *MockEc2client_RevokeSecurityGroupEgress_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockEc2client_DescribeSecurityGroups_Call) RunAndReturn(run func(context.Context, *ec2.DescribeSecurityGroupsInput, ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)) *MockEc2client_DescribeSecurityGroups_Call {
+func (_c *MockEc2client_RevokeSecurityGroupEgress_Call) RunAndReturn(run func(context.Context, *ec2.RevokeSecurityGroupEgressInput, ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupEgressOutput, error)) *MockEc2client_RevokeSecurityGroupEgress_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DescribeVolumes provides a mock function with given fields: ctx, params, optFns
-func (_m *MockEc2client) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+// RevokeSecurityGroupIngress provides a mock function with given fields: ctx, params, optFns
+func (_m *MockEc2client) RevokeSecurityGroupIngress(ctx context.Context, params *ec2.RevokeSecurityGroupIngressInput, optFns ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error) {
 	_va := make([]interface{}, len(optFns))
 	for _i := range optFns {
 		_va[_i] = optFns[_i]
@@ -627,23 +1811,23 @@ func (_m *MockEc2client) DescribeVolumes(ctx context.Context, params *ec2.Descri
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DescribeVolumes")
+		panic("no return value specified for RevokeSecurityGroupIngress")
 	}
 
-	var r0 *ec2.DescribeVolumesOutput
+	var r0 *ec2.RevokeSecurityGroupIngressOutput
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.RevokeSecurityGroupIngressInput, ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error)); ok {
 		return rf(ctx, params, optFns...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) *ec2.DescribeVolumesOutput); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.RevokeSecurityGroupIngressInput, ...func(*ec2.Options)) *ec2.RevokeSecurityGroupIngressOutput); ok {
 		r0 = rf(ctx, params, optFns...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ec2.DescribeVolumesOutput)
+			r0 = ret.Get(0).(*ec2.RevokeSecurityGroupIngressOutput)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.RevokeSecurityGroupIngressInput, ...func(*ec2.Options)) error); ok {
 		r1 = rf(ctx, params, optFns...)
 	} else {
 		r1 = ret.Error(1)
@@ -652,21 +1836,21 @@ func (_m *MockEc2client) DescribeVolumes(ctx context.Context, params *ec2.Descri
 	return r0, r1
 }
 
-// MockEc2client_DescribeVolumes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeVolumes'
-type MockEc2client_DescribeVolumes_Call struct {
+// MockEc2client_RevokeSecurityGroupIngress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSecurityGroupIngress'
+type MockEc2client_RevokeSecurityGroupIngress_Call struct {
 	*mock.Call
 }
 
-// DescribeVolumes is a helper method to define mock.On call
+// RevokeSecurityGroupIngress is a helper method to define mock.On call
 //   - ctx context.Context
-//   - params *ec2.DescribeVolumesInput
+//   - params *ec2.RevokeSecurityGroupIngressInput
 //   - optFns ...func(*ec2.Options)
-func (_e *MockEc2client_Expecter) DescribeVolumes(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_DescribeVolumes_Call {
-	return &MockEc2client_DescribeVolumes_Call{Call: _e.mock.On("DescribeVolumes",
+func (_e *MockEc2client_Expecter) RevokeSecurityGroupIngress(ctx interface{}, params interface{}, optFns ...interface{}) *MockEc2client_RevokeSecurityGroupIngress_Call {
+	return &MockEc2client_RevokeSecurityGroupIngress_Call{Call: _e.mock.On("RevokeSecurityGroupIngress",
 		append([]interface{}{ctx, params}, optFns...)...)}
 }
 
-func (_c *MockEc2client_DescribeVolumes_Call) Run(run func(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options))) *MockEc2client_DescribeVolumes_Call {
+func (_c *MockEc2client_RevokeSecurityGroupIngress_Call) Run(run func(ctx context.Context, params *ec2.RevokeSecurityGroupIngressInput, optFns ...func(*ec2.Options))) *MockEc2client_RevokeSecurityGroupIngress_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		variadicArgs := make([]func(*ec2.Options), len(args)-2)
 		for i, a := range args[2:] {
@@ -674,17 +1858,17 @@ func (_c *MockEc2client_DescribeVolumes_Call) Run(run func(ctx context.Context,
 				variadicArgs[i] = a.(func(*ec2.Options))
 			}
 		}
-		run(args[0].(context.Context), args[1].(*ec2.DescribeVolumesInput), variadicArgs...)
+		run(args[0].(context.Context), args[1].(*ec2.RevokeSecurityGroupIngressInput), variadicArgs...)
 	})
 	return _c
 }
 
-func (_c *MockEc2client_DescribeVolumes_Call) Return(_a0 *ec2.DescribeVolumesOutput, _a1 error) *MockEc2client_DescribeVolumes_Call {
+func (_c *MockEc2client_RevokeSecurityGroupIngress_Call) Return(_a0 *ec2.RevokeSecurityGroupIngressOutput, _a1 error) *MockEc2client_RevokeSecurityGroupIngress_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockEc2client_DescribeVolumes_Call) RunAndReturn(run func(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)) *MockEc2client_DescribeVolumes_Call {
+func (_c *MockEc2client_RevokeSecurityGroupIngress_Call) RunAndReturn(run func(context.Context, *ec2.RevokeSecurityGroupIngressInput, ...func(*ec2.Options)) (*ec2.RevokeSecurityGroupIngressOutput, error)) *MockEc2client_RevokeSecurityGroupIngress_Call {
 	_c.Call.Return(run)
 	return _c
 }
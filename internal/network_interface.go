@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/steffakasid/eslog"
+)
+
+const NETWORKINTERFACE_RESOURCE_TYPE = "AWS::EC2::NetworkInterface"
+
+// GetNetworkInterfaces returns all network interfaces.
+func (a AWS) GetNetworkInterfaces() ([]ec2Types.NetworkInterface, error) {
+	ifaces := []ec2Types.NetworkInterface{}
+	in := &ec2.DescribeNetworkInterfacesInput{}
+	for {
+		out, err := a.ec2.DescribeNetworkInterfaces(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		ifaces = append(ifaces, out.NetworkInterfaces...)
+
+		if out.NextToken == nil {
+			return ifaces, nil
+		}
+		in.NextToken = out.NextToken
+	}
+}
+
+// GetNetworkInterfaceCreationEvents returns the CreateNetworkInterface event of each network interface created
+// between startTime and endTime by the interface ID.
+func (a AWS) GetNetworkInterfaceCreationEvents(startTime, endTime time.Time) (map[string]LifecycleEvent, error) {
+	return a.lookupLatestEvents(NETWORKINTERFACE_CREATED, resourcesOfType(NETWORKINTERFACE_RESOURCE_TYPE), startTime, endTime)
+}
+
+func (a AWS) DeleteNetworkInterface(ifaceID string, dryrun bool) error {
+	eslog.Logger.Debugf("DeleteNetworkInterface(%s), dryrun: %t", ifaceID, dryrun)
+
+	opts := &ec2.DeleteNetworkInterfaceInput{
+		NetworkInterfaceId: &ifaceID,
+		DryRun:             &dryrun,
+	}
+	_, err := a.ec2.DeleteNetworkInterface(context.TODO(), opts)
+	return err
+}
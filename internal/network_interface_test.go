@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cloudtrailTypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNetworkInterfaces(t *testing.T) {
+	t.Run("Paginated", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+		mock.EXPECT().DescribeNetworkInterfaces(context.TODO(), &ec2.DescribeNetworkInterfacesInput{}).Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []types.NetworkInterface{{NetworkInterfaceId: aws.String("eni-1")}},
+			NextToken:         aws.String("next"),
+		}, nil).Once()
+		mock.EXPECT().DescribeNetworkInterfaces(context.TODO(), &ec2.DescribeNetworkInterfacesInput{NextToken: aws.String("next")}).Return(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []types.NetworkInterface{{NetworkInterfaceId: aws.String("eni-2")}},
+		}, nil).Once()
+
+		ifaces, err := SUT.GetNetworkInterfaces()
+		require.NoError(t, err)
+		require.Len(t, ifaces, 2)
+		assert.Equal(t, "eni-2", *ifaces[1].NetworkInterfaceId)
+	})
+
+	t.Run("Error from AWS", func(t *testing.T) {
+		SUT, mock, _ := setupSUT(t)
+		mock.EXPECT().DescribeNetworkInterfaces(context.TODO(), &ec2.DescribeNetworkInterfacesInput{}).Return(nil, errors.New("Something went wrong")).Once()
+
+		_, err := SUT.GetNetworkInterfaces()
+		require.EqualError(t, err, "Something went wrong")
+	})
+}
+
+func TestGetNetworkInterfaceCreationEvents(t *testing.T) {
+	starttime, err := time.Parse(time.DateTime, "2006-01-02 15:04:05")
+	require.NoError(t, err)
+	endtime := starttime.Add(24 * time.Hour)
+
+	resources := func(ifaceID string) []cloudtrailTypes.Resource {
+		return []cloudtrailTypes.Resource{
+			{ResourceName: aws.String("subnet-1"), ResourceType: aws.String("AWS::EC2::Subnet")},
+			{ResourceName: aws.String(ifaceID), ResourceType: aws.String(NETWORKINTERFACE_RESOURCE_TYPE)},
+		}
+	}
+
+	SUT, _, cloudTrailMock := setupSUT(t)
+	cloudTrailMock.EXPECT().LookupEvents(context.TODO(), &cloudtrail.LookupEventsInput{
+		StartTime: &starttime,
+		EndTime:   &endtime,
+		LookupAttributes: []cloudtrailTypes.LookupAttribute{
+			{
+				AttributeKey:   cloudtrailTypes.LookupAttributeKeyEventName,
+				AttributeValue: aws.String("CreateNetworkInterface"),
+			},
+		},
+	}).Return(&cloudtrail.LookupEventsOutput{
+		Events: []cloudtrailTypes.Event{
+			{EventName: aws.String("CreateNetworkInterface"), EventTime: aws.Time(starttime.Add(time.Hour)), Username: aws.String("lambda"), Resources: resources("eni-1")},
+			{EventName: aws.String("CreateNetworkInterface"), EventTime: aws.Time(starttime.Add(2 * time.Hour)), Username: aws.String("dryrun"), Resources: resources("eni-2"),
+				CloudTrailEvent: aws.String(`{"errorCode":"Client.DryRunOperation"}`)},
+		},
+	}, nil).Once()
+
+	events, err := SUT.GetNetworkInterfaceCreationEvents(starttime, endtime)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "lambda", events["eni-1"].Username)
+	assert.Equal(t, starttime.Add(time.Hour), *events["eni-1"].EventTime)
+}
+
+func TestDeleteNetworkInterface(t *testing.T) {
+	SUT, mock, _ := setupSUT(t)
+	mock.EXPECT().DeleteNetworkInterface(context.TODO(), &ec2.DeleteNetworkInterfaceInput{
+		NetworkInterfaceId: aws.String("eni-1"),
+		DryRun:             aws.Bool(true),
+	}).Return(nil, errors.New("Something went wrong")).Once()
+
+	err := SUT.DeleteNetworkInterface("eni-1", true)
+	require.EqualError(t, err, "Something went wrong")
+}
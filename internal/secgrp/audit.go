@@ -0,0 +1,332 @@
+package secgrp
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/steffakasid/awsclean/internal"
+	eslog "github.com/steffakasid/eslog"
+)
+
+type Severity string
+
+const (
+	SEVERITY_HIGH   Severity = "high"
+	SEVERITY_MEDIUM Severity = "medium"
+	SEVERITY_LOW    Severity = "low"
+)
+
+var severityOrder = []Severity{SEVERITY_HIGH, SEVERITY_MEDIUM, SEVERITY_LOW}
+
+type Direction string
+
+const (
+	DIRECTION_INGRESS Direction = "ingress"
+	DIRECTION_EGRESS  Direction = "egress"
+)
+
+// ports which may be open to the internet
+var webPorts = []int32{80, 443}
+
+// Finding is a risky or dead rule of a security group.
+type Finding struct {
+	GroupId   string
+	GroupName string
+	Direction Direction
+	Rule      string
+	Severity  Severity
+	Issue     string
+	// the rule references a deleted group or prefix list and can be revoked
+	Stale bool
+	// the part of the rule the finding is about, used to revoke it
+	Permission ec2Types.IpPermission `json:"-"`
+}
+
+// Audit checks the rules of all security groups for:
+//   - ingress from the internet on other ports then 80 and 443
+//   - references to deleted security groups or prefix lists, the stale groups of each VPC are looked up to
+//     also find references to deleted groups in peered VPCs
+//   - CIDRs which are already covered by another rule of the group
+//
+// The findings are ordered by severity.
+func (sec SecGrp) Audit() ([]Finding, error) {
+	secGrps, err := sec.awsClient.GetSecurityGroups()
+	if err != nil {
+		return nil, fmt.Errorf("could not getSecurityGroups: %w", err)
+	}
+	prefixListIds, err := sec.awsClient.GetManagedPrefixListIds()
+	if err != nil {
+		return nil, fmt.Errorf("could not get prefix lists: %w", err)
+	}
+	staleRefs, err := sec.getStaleReferences(secGrps)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []Finding{}
+	for _, groupID := range slices.Sorted(maps.Keys(secGrps)) {
+		grp := secGrps[groupID]
+		for _, direction := range []Direction{DIRECTION_INGRESS, DIRECTION_EGRESS} {
+			perms := grp.IpPermissions
+			if direction == DIRECTION_EGRESS {
+				perms = grp.IpPermissionsEgress
+			}
+
+			grpFindings := []Finding{}
+			if direction == DIRECTION_INGRESS {
+				grpFindings = append(grpFindings, auditOpenIngress(perms)...)
+			}
+			grpFindings = append(grpFindings, auditStaleReferences(*grp.SecurityGroup, perms, secGrps, prefixListIds, staleRefs[groupID][direction])...)
+			grpFindings = append(grpFindings, auditOverlappingCidrs(perms)...)
+
+			for _, finding := range grpFindings {
+				finding.GroupId = groupID
+				finding.GroupName = aws.ToString(grp.GroupName)
+				finding.Direction = direction
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Compare(slices.Index(severityOrder, a.Severity), slices.Index(severityOrder, b.Severity))
+	})
+	return findings, nil
+}
+
+// RevokeStaleRules revokes the rules of the stale findings. Other findings are ignored.
+func (sec SecGrp) RevokeStaleRules(findings []Finding) {
+	for _, finding := range findings {
+		if !finding.Stale {
+			continue
+		}
+		err := sec.awsClient.RevokeSecurityGroupRule(finding.GroupId, finding.Direction == DIRECTION_EGRESS, finding.Permission, sec.dryrun)
+		if err != nil && !internal.IsDryRunOperation(err) {
+			eslog.Logger.Errorf("error revoking %s rule %s of %s: %s", finding.Direction, finding.Rule, finding.GroupId, err)
+			continue
+		}
+		eslog.Logger.Infof("Revoked %s rule %s of %s - %s (dry run: %t)", finding.Direction, finding.Rule, finding.GroupName, finding.GroupId, sec.dryrun)
+	}
+}
+
+func auditOpenIngress(perms []ec2Types.IpPermission) []Finding {
+	findings := []Finding{}
+	for _, perm := range perms {
+		sources := []string{}
+		for _, ipRange := range perm.IpRanges {
+			if aws.ToString(ipRange.CidrIp) == "0.0.0.0/0" {
+				sources = append(sources, "0.0.0.0/0")
+			}
+		}
+		for _, ipRange := range perm.Ipv6Ranges {
+			if aws.ToString(ipRange.CidrIpv6) == "::/0" {
+				sources = append(sources, "::/0")
+			}
+		}
+
+		for _, source := range sources {
+			finding := Finding{Rule: fmt.Sprintf("%s from %s", describePorts(perm), source), Permission: perm}
+			switch {
+			case isICMP(perm):
+				finding.Severity = SEVERITY_LOW
+				finding.Issue = "ICMP open to the internet"
+			case isWebOnly(perm):
+				continue
+			default:
+				finding.Severity = SEVERITY_HIGH
+				finding.Issue = "open to the internet on non-web ports"
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// getStaleReferences returns the ids of the deleted groups referenced by each group and direction, as reported by
+// AWS for the VPCs of the groups.
+func (sec SecGrp) getStaleReferences(secGrps internal.SecurityGroups) (map[string]map[Direction][]string, error) {
+	vpcIds := []string{}
+	for _, grp := range secGrps {
+		if grp.VpcId != nil && !slices.Contains(vpcIds, *grp.VpcId) {
+			vpcIds = append(vpcIds, *grp.VpcId)
+		}
+	}
+	slices.Sort(vpcIds)
+
+	staleRefs := map[string]map[Direction][]string{}
+	for _, vpcId := range vpcIds {
+		staleGroups, err := sec.awsClient.GetStaleSecurityGroups(vpcId)
+		if err != nil {
+			return nil, fmt.Errorf("could not get stale security groups of %s: %w", vpcId, err)
+		}
+		for _, staleGroup := range staleGroups {
+			groupID := aws.ToString(staleGroup.GroupId)
+			staleRefs[groupID] = map[Direction][]string{
+				DIRECTION_INGRESS: staleGroupIds(staleGroup.StaleIpPermissions),
+				DIRECTION_EGRESS:  staleGroupIds(staleGroup.StaleIpPermissionsEgress),
+			}
+		}
+	}
+	return staleRefs, nil
+}
+
+func staleGroupIds(perms []ec2Types.StaleIpPermission) []string {
+	groupIds := []string{}
+	for _, perm := range perms {
+		for _, pair := range perm.UserIdGroupPairs {
+			if pair.GroupId != nil {
+				groupIds = append(groupIds, *pair.GroupId)
+			}
+		}
+	}
+	return groupIds
+}
+
+// auditStaleReferences reports the references to deleted groups and prefix lists. References to groups in peered VPCs
+// are only known to be stale if AWS reports them in staleRefs, references to groups of other accounts can't be checked.
+func auditStaleReferences(grp ec2Types.SecurityGroup, perms []ec2Types.IpPermission, secGrps internal.SecurityGroups, prefixListIds, staleRefs []string) []Finding {
+	findings := []Finding{}
+	for _, perm := range perms {
+		for _, pair := range perm.UserIdGroupPairs {
+			if pair.GroupId == nil {
+				continue
+			}
+			if !slices.Contains(staleRefs, *pair.GroupId) {
+				if pair.VpcPeeringConnectionId != nil ||
+					(pair.UserId != nil && aws.ToString(pair.UserId) != aws.ToString(grp.OwnerId)) {
+					continue
+				}
+				if _, exists := secGrps[*pair.GroupId]; exists {
+					continue
+				}
+			}
+			stalePerm := ec2Types.IpPermission{IpProtocol: perm.IpProtocol, FromPort: perm.FromPort, ToPort: perm.ToPort, UserIdGroupPairs: []ec2Types.UserIdGroupPair{pair}}
+			findings = append(findings, Finding{
+				Rule:       fmt.Sprintf("%s with %s", describePorts(perm), *pair.GroupId),
+				Severity:   SEVERITY_MEDIUM,
+				Issue:      "references deleted security group",
+				Stale:      true,
+				Permission: stalePerm,
+			})
+		}
+
+		for _, prefixList := range perm.PrefixListIds {
+			if prefixList.PrefixListId == nil || slices.Contains(prefixListIds, *prefixList.PrefixListId) {
+				continue
+			}
+			stalePerm := ec2Types.IpPermission{IpProtocol: perm.IpProtocol, FromPort: perm.FromPort, ToPort: perm.ToPort, PrefixListIds: []ec2Types.PrefixListId{prefixList}}
+			findings = append(findings, Finding{
+				Rule:       fmt.Sprintf("%s with %s", describePorts(perm), *prefixList.PrefixListId),
+				Severity:   SEVERITY_MEDIUM,
+				Issue:      "references deleted prefix list",
+				Stale:      true,
+				Permission: stalePerm,
+			})
+		}
+	}
+	return findings
+}
+
+type cidrRule struct {
+	perm   ec2Types.IpPermission
+	prefix netip.Prefix
+}
+
+func (r cidrRule) String() string {
+	return fmt.Sprintf("%s %s", describePorts(r.perm), r.prefix)
+}
+
+// covers returns true if all traffic allowed by other is also allowed by r.
+func (r cidrRule) covers(other cidrRule) bool {
+	if r.prefix.Addr().Is4() != other.prefix.Addr().Is4() ||
+		r.prefix.Bits() > other.prefix.Bits() || !r.prefix.Contains(other.prefix.Addr()) {
+		return false
+	}
+	if aws.ToString(r.perm.IpProtocol) == "-1" {
+		return true
+	}
+	if aws.ToString(r.perm.IpProtocol) != aws.ToString(other.perm.IpProtocol) {
+		return false
+	}
+	if isICMP(r.perm) {
+		// -1 means all ICMP types or codes
+		return (aws.ToInt32(r.perm.FromPort) == -1 || aws.ToInt32(r.perm.FromPort) == aws.ToInt32(other.perm.FromPort)) &&
+			(aws.ToInt32(r.perm.ToPort) == -1 || aws.ToInt32(r.perm.ToPort) == aws.ToInt32(other.perm.ToPort))
+	}
+	return aws.ToInt32(r.perm.FromPort) <= aws.ToInt32(other.perm.FromPort) && aws.ToInt32(r.perm.ToPort) >= aws.ToInt32(other.perm.ToPort)
+}
+
+func auditOverlappingCidrs(perms []ec2Types.IpPermission) []Finding {
+	rules := []cidrRule{}
+	for _, perm := range perms {
+		cidrs := []string{}
+		for _, ipRange := range perm.IpRanges {
+			cidrs = append(cidrs, aws.ToString(ipRange.CidrIp))
+		}
+		for _, ipRange := range perm.Ipv6Ranges {
+			cidrs = append(cidrs, aws.ToString(ipRange.CidrIpv6))
+		}
+		for _, cidr := range cidrs {
+			prefix, err := netip.ParsePrefix(cidr)
+			if err != nil {
+				eslog.Logger.Debugf("Skipping invalid CIDR %s: %s", cidr, err)
+				continue
+			}
+			rules = append(rules, cidrRule{perm: perm, prefix: prefix.Masked()})
+		}
+	}
+
+	findings := []Finding{}
+	for i, rule := range rules {
+		for j, other := range rules {
+			if i == j || !other.covers(rule) {
+				continue
+			}
+			issue := fmt.Sprintf("covered by %s", other)
+			if rule.covers(other) {
+				// identical rules: only report the second one
+				if j > i {
+					continue
+				}
+				issue = fmt.Sprintf("duplicate of %s", other)
+			}
+			findings = append(findings, Finding{
+				Rule:       rule.String(),
+				Severity:   SEVERITY_LOW,
+				Issue:      issue,
+				Permission: rule.perm,
+			})
+			break
+		}
+	}
+	return findings
+}
+
+// describePorts returns a short description of the protocol and ports of perm, e.g. tcp 22 or all traffic.
+func describePorts(perm ec2Types.IpPermission) string {
+	protocol := aws.ToString(perm.IpProtocol)
+	switch {
+	case protocol == "-1":
+		return "all traffic"
+	case isICMP(perm) || perm.FromPort == nil:
+		return protocol
+	case aws.ToInt32(perm.FromPort) == aws.ToInt32(perm.ToPort):
+		return fmt.Sprintf("%s %d", protocol, aws.ToInt32(perm.FromPort))
+	}
+	return fmt.Sprintf("%s %d-%d", protocol, aws.ToInt32(perm.FromPort), aws.ToInt32(perm.ToPort))
+}
+
+func isICMP(perm ec2Types.IpPermission) bool {
+	return slices.Contains([]string{"icmp", "1", "icmpv6", "58"}, aws.ToString(perm.IpProtocol))
+}
+
+func isWebOnly(perm ec2Types.IpPermission) bool {
+	return aws.ToString(perm.IpProtocol) == "tcp" &&
+		aws.ToInt32(perm.FromPort) == aws.ToInt32(perm.ToPort) &&
+		slices.Contains(webPorts, aws.ToInt32(perm.FromPort))
+}
@@ -0,0 +1,231 @@
+package secgrp
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tcpPermission(fromPort, toPort int32, cidrs ...string) ec2Types.IpPermission {
+	perm := ec2Types.IpPermission{
+		IpProtocol: aws.String("tcp"),
+		FromPort:   aws.Int32(fromPort),
+		ToPort:     aws.Int32(toPort),
+	}
+	for _, cidr := range cidrs {
+		perm.IpRanges = append(perm.IpRanges, ec2Types.IpRange{CidrIp: aws.String(cidr)})
+	}
+	return perm
+}
+
+func mockDescribeManagedPrefixLists(ec2Mock *mocks.MockEc2client, prefixListIds ...string) {
+	out := &ec2.DescribeManagedPrefixListsOutput{}
+	for _, prefixListId := range prefixListIds {
+		out.PrefixLists = append(out.PrefixLists, ec2Types.ManagedPrefixList{PrefixListId: aws.String(prefixListId)})
+	}
+	ec2Mock.EXPECT().DescribeManagedPrefixLists(context.TODO(), &ec2.DescribeManagedPrefixListsInput{}).Return(out, nil).Once()
+}
+
+func TestAudit(t *testing.T) {
+	staleGroupPair := ec2Types.UserIdGroupPair{GroupId: aws.String("sg-deleted"), UserId: aws.String("123456789012")}
+	staleGroupPermission := ec2Types.IpPermission{
+		IpProtocol:       aws.String("tcp"),
+		FromPort:         aws.Int32(5432),
+		ToPort:           aws.Int32(5432),
+		UserIdGroupPairs: []ec2Types.UserIdGroupPair{staleGroupPair, {GroupId: aws.String("sg-web"), UserId: aws.String("123456789012")}},
+	}
+	groups := []ec2Types.SecurityGroup{
+		{
+			GroupId:   aws.String("sg-web"),
+			GroupName: aws.String("web"),
+			OwnerId:   aws.String("123456789012"),
+			IpPermissions: []ec2Types.IpPermission{
+				tcpPermission(443, 443, "0.0.0.0/0"),
+				tcpPermission(22, 22, "0.0.0.0/0"),
+				tcpPermission(8000, 9000, "10.0.0.0/8"),
+				tcpPermission(8080, 8080, "10.1.0.0/16"),
+				{IpProtocol: aws.String("icmp"), FromPort: aws.Int32(-1), ToPort: aws.Int32(-1), IpRanges: []ec2Types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}},
+			},
+			IpPermissionsEgress: []ec2Types.IpPermission{
+				{IpProtocol: aws.String("-1"), IpRanges: []ec2Types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}},
+				{
+					IpProtocol:    aws.String("tcp"),
+					FromPort:      aws.Int32(443),
+					ToPort:        aws.Int32(443),
+					PrefixListIds: []ec2Types.PrefixListId{{PrefixListId: aws.String("pl-existing")}, {PrefixListId: aws.String("pl-deleted")}},
+				},
+			},
+		},
+		{
+			GroupId:       aws.String("sg-db"),
+			GroupName:     aws.String("db"),
+			OwnerId:       aws.String("123456789012"),
+			IpPermissions: []ec2Types.IpPermission{staleGroupPermission},
+		},
+	}
+
+	t.Run("Findings", func(t *testing.T) {
+		SUT, ec2Mock, _ := setupSUT(t, nil, false, false)
+		mockDescribeSecGrpsWithRules(ec2Mock, groups...)
+		mockDescribeManagedPrefixLists(ec2Mock, "pl-existing")
+
+		findings, err := SUT.Audit()
+		require.NoError(t, err)
+
+		expected := []Finding{
+			{GroupId: "sg-web", GroupName: "web", Direction: DIRECTION_INGRESS, Rule: "tcp 22 from 0.0.0.0/0", Severity: SEVERITY_HIGH, Issue: "open to the internet on non-web ports"},
+			{GroupId: "sg-db", GroupName: "db", Direction: DIRECTION_INGRESS, Rule: "tcp 5432 with sg-deleted", Severity: SEVERITY_MEDIUM, Issue: "references deleted security group", Stale: true},
+			{GroupId: "sg-web", GroupName: "web", Direction: DIRECTION_EGRESS, Rule: "tcp 443 with pl-deleted", Severity: SEVERITY_MEDIUM, Issue: "references deleted prefix list", Stale: true},
+			{GroupId: "sg-web", GroupName: "web", Direction: DIRECTION_INGRESS, Rule: "icmp from 0.0.0.0/0", Severity: SEVERITY_LOW, Issue: "ICMP open to the internet"},
+			{GroupId: "sg-web", GroupName: "web", Direction: DIRECTION_INGRESS, Rule: "tcp 8080 10.1.0.0/16", Severity: SEVERITY_LOW, Issue: "covered by tcp 8000-9000 10.0.0.0/8"},
+		}
+		require.Len(t, findings, len(expected))
+		for i, finding := range findings {
+			finding.Permission = ec2Types.IpPermission{}
+			assert.Equal(t, expected[i], finding)
+		}
+		assert.Equal(t, []ec2Types.UserIdGroupPair{staleGroupPair}, findings[1].Permission.UserIdGroupPairs)
+	})
+
+	t.Run("Revoke Stale Rules", func(t *testing.T) {
+		dryrun := true
+		SUT, ec2Mock, _ := setupSUT(t, nil, dryrun, false)
+		mockDescribeSecGrpsWithRules(ec2Mock, groups...)
+		mockDescribeManagedPrefixLists(ec2Mock, "pl-existing")
+
+		ec2Mock.EXPECT().RevokeSecurityGroupIngress(context.TODO(), &ec2.RevokeSecurityGroupIngressInput{
+			DryRun:  aws.Bool(dryrun),
+			GroupId: aws.String("sg-db"),
+			IpPermissions: []ec2Types.IpPermission{{
+				IpProtocol:       aws.String("tcp"),
+				FromPort:         aws.Int32(5432),
+				ToPort:           aws.Int32(5432),
+				UserIdGroupPairs: []ec2Types.UserIdGroupPair{staleGroupPair},
+			}},
+		}).Return(nil, &smithy.GenericAPIError{Code: "DryRunOperation"}).Once()
+		ec2Mock.EXPECT().RevokeSecurityGroupEgress(context.TODO(), &ec2.RevokeSecurityGroupEgressInput{
+			DryRun:  aws.Bool(dryrun),
+			GroupId: aws.String("sg-web"),
+			IpPermissions: []ec2Types.IpPermission{{
+				IpProtocol:    aws.String("tcp"),
+				FromPort:      aws.Int32(443),
+				ToPort:        aws.Int32(443),
+				PrefixListIds: []ec2Types.PrefixListId{{PrefixListId: aws.String("pl-deleted")}},
+			}},
+		}).Return(nil, &smithy.GenericAPIError{Code: "DryRunOperation"}).Once()
+
+		findings, err := SUT.Audit()
+		require.NoError(t, err)
+		SUT.RevokeStaleRules(findings)
+	})
+
+	t.Run("Stale Peered Reference", func(t *testing.T) {
+		peeredPair := ec2Types.UserIdGroupPair{GroupId: aws.String("sg-peer-deleted"), UserId: aws.String("210987654321"), VpcPeeringConnectionId: aws.String("pcx-1")}
+		peered := []ec2Types.SecurityGroup{{
+			GroupId:   aws.String("sg-app"),
+			GroupName: aws.String("app"),
+			OwnerId:   aws.String("123456789012"),
+			VpcId:     aws.String("vpc-1"),
+			IpPermissions: []ec2Types.IpPermission{{
+				IpProtocol: aws.String("tcp"),
+				FromPort:   aws.Int32(8080),
+				ToPort:     aws.Int32(8080),
+				UserIdGroupPairs: []ec2Types.UserIdGroupPair{
+					peeredPair,
+					{GroupId: aws.String("sg-peer"), UserId: aws.String("210987654321"), VpcPeeringConnectionId: aws.String("pcx-1")},
+				},
+			}},
+		}}
+
+		dryrun := true
+		SUT, ec2Mock, _ := setupSUT(t, nil, dryrun, false)
+		mockDescribeSecGrpsWithRules(ec2Mock, peered...)
+		mockDescribeManagedPrefixLists(ec2Mock)
+		ec2Mock.EXPECT().DescribeStaleSecurityGroups(context.TODO(), &ec2.DescribeStaleSecurityGroupsInput{VpcId: aws.String("vpc-1")}).Return(&ec2.DescribeStaleSecurityGroupsOutput{
+			StaleSecurityGroupSet: []ec2Types.StaleSecurityGroup{{
+				GroupId: aws.String("sg-app"),
+				VpcId:   aws.String("vpc-1"),
+				StaleIpPermissions: []ec2Types.StaleIpPermission{{
+					IpProtocol:       aws.String("tcp"),
+					FromPort:         aws.Int32(8080),
+					ToPort:           aws.Int32(8080),
+					UserIdGroupPairs: []ec2Types.UserIdGroupPair{peeredPair},
+				}},
+			}},
+		}, nil).Once()
+		ec2Mock.EXPECT().RevokeSecurityGroupIngress(context.TODO(), &ec2.RevokeSecurityGroupIngressInput{
+			DryRun:  aws.Bool(dryrun),
+			GroupId: aws.String("sg-app"),
+			IpPermissions: []ec2Types.IpPermission{{
+				IpProtocol:       aws.String("tcp"),
+				FromPort:         aws.Int32(8080),
+				ToPort:           aws.Int32(8080),
+				UserIdGroupPairs: []ec2Types.UserIdGroupPair{peeredPair},
+			}},
+		}).Return(nil, &smithy.GenericAPIError{Code: "DryRunOperation"}).Once()
+
+		findings, err := SUT.Audit()
+		require.NoError(t, err)
+		require.Len(t, findings, 1)
+		assert.Equal(t, "tcp 8080 with sg-peer-deleted", findings[0].Rule)
+		assert.Equal(t, "references deleted security group", findings[0].Issue)
+		assert.True(t, findings[0].Stale)
+		SUT.RevokeStaleRules(findings)
+	})
+
+	t.Run("Error DescribeStaleSecurityGroups", func(t *testing.T) {
+		SUT, ec2Mock, _ := setupSUT(t, nil, false, false)
+		mockDescribeSecGrpsWithRules(ec2Mock, ec2Types.SecurityGroup{GroupId: aws.String("sg-app"), VpcId: aws.String("vpc-1")})
+		mockDescribeManagedPrefixLists(ec2Mock)
+		ec2Mock.EXPECT().DescribeStaleSecurityGroups(context.TODO(), &ec2.DescribeStaleSecurityGroupsInput{VpcId: aws.String("vpc-1")}).Return(nil, errors.New("some error")).Once()
+
+		_, err := SUT.Audit()
+		require.EqualError(t, err, "could not get stale security groups of vpc-1: some error")
+	})
+}
+
+func TestCidrRuleCovers(t *testing.T) {
+	rule := func(perm ec2Types.IpPermission) cidrRule {
+		return cidrRule{perm: perm, prefix: netipMustParse(t, aws.ToString(perm.IpRanges[0].CidrIp))}
+	}
+
+	assert.True(t, rule(tcpPermission(0, 65535, "10.0.0.0/8")).covers(rule(tcpPermission(22, 22, "10.0.0.0/24"))))
+	assert.True(t, rule(tcpPermission(22, 22, "10.0.0.0/24")).covers(rule(tcpPermission(22, 22, "10.0.0.0/24"))))
+	assert.False(t, rule(tcpPermission(22, 22, "10.0.0.0/24")).covers(rule(tcpPermission(0, 65535, "10.0.0.0/8"))))
+	assert.False(t, rule(tcpPermission(80, 80, "10.0.0.0/8")).covers(rule(tcpPermission(22, 22, "10.0.0.0/24"))))
+	assert.False(t, rule(tcpPermission(22, 22, "10.0.0.0/8")).covers(rule(tcpPermission(22, 22, "192.168.0.0/24"))))
+
+	allTraffic := ec2Types.IpPermission{IpProtocol: aws.String("-1"), IpRanges: []ec2Types.IpRange{{CidrIp: aws.String("10.0.0.0/8")}}}
+	assert.True(t, rule(allTraffic).covers(rule(tcpPermission(22, 22, "10.0.0.0/24"))))
+
+	t.Run("Duplicates", func(t *testing.T) {
+		findings := auditOverlappingCidrs([]ec2Types.IpPermission{
+			tcpPermission(22, 22, "10.0.0.0/24"),
+			tcpPermission(22, 22, "10.0.0.0/24"),
+		})
+		require.Len(t, findings, 1)
+		assert.Equal(t, "duplicate of tcp 22 10.0.0.0/24", findings[0].Issue)
+	})
+}
+
+func TestDescribePorts(t *testing.T) {
+	assert.Equal(t, "tcp 22", describePorts(tcpPermission(22, 22)))
+	assert.Equal(t, "tcp 8000-9000", describePorts(tcpPermission(8000, 9000)))
+	assert.Equal(t, "all traffic", describePorts(ec2Types.IpPermission{IpProtocol: aws.String("-1")}))
+	assert.Equal(t, "icmp", describePorts(ec2Types.IpPermission{IpProtocol: aws.String("icmp"), FromPort: aws.Int32(8), ToPort: aws.Int32(-1)}))
+}
+
+func netipMustParse(t *testing.T, cidr string) netip.Prefix {
+	prefix, err := netip.ParsePrefix(cidr)
+	require.NoError(t, err)
+	return prefix
+}
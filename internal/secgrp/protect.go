@@ -0,0 +1,88 @@
+package secgrp
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/steffakasid/awsclean/internal"
+)
+
+// DEFAULT_GROUP_NAME is the name of the security group AWS creates for each VPC. It can't be deleted.
+const DEFAULT_GROUP_NAME = "default"
+
+// name prefixes of security groups created by other services
+var managedNamePrefixes = map[string]string{
+	"eks-cluster-sg-":   "EKS",
+	"k8s-":              "Kubernetes",
+	"awseb-":            "Elastic Beanstalk",
+	"ElasticMapReduce-": "EMR",
+	"default_elb_":      "Classic Load Balancer",
+}
+
+// name suffixes of security groups created by other services, e.g. d-1234567890_controllers
+var managedNameSuffixes = map[string]string{
+	"_controllers": "Directory Service",
+}
+
+// tag key prefixes of security groups created by AWS or Kubernetes
+var managedTagPrefixes = []string{"aws:", "kubernetes.io/"}
+
+// WithDeleteProtected allows to delete groups which are managed by other services. The default security
+// groups of the VPCs are never deleted as AWS doesn't allow it.
+func WithDeleteProtected(deleteProtected bool) Option {
+	return func(sec *SecGrp) {
+		sec.deleteProtected = deleteProtected
+	}
+}
+
+// protect sets the ProtectedReason of all groups which are the default group of a VPC or managed by another service.
+func protect(secGrps internal.SecurityGroups) {
+	for _, groupID := range slices.Sorted(maps.Keys(secGrps)) {
+		secGrp := secGrps[groupID]
+		if secGrp.SecurityGroup == nil {
+			continue
+		}
+		secGrp.ProtectedReason = protectionReason(*secGrp)
+	}
+}
+
+// protectionReason returns why the group must not be deleted or an empty string if it can be deleted.
+func protectionReason(secGrp internal.SecurityGroup) string {
+	name := aws.ToString(secGrp.GroupName)
+	if name == DEFAULT_GROUP_NAME {
+		return "default security group of VPC"
+	}
+	if len(secGrp.ManagedBy) > 0 {
+		return fmt.Sprintf("attached to network interfaces managed by %s", strings.Join(secGrp.ManagedBy, ", "))
+	}
+	for _, prefix := range slices.Sorted(maps.Keys(managedNamePrefixes)) {
+		if strings.HasPrefix(name, prefix) {
+			return fmt.Sprintf("name prefix %s of %s", prefix, managedNamePrefixes[prefix])
+		}
+	}
+	for _, suffix := range slices.Sorted(maps.Keys(managedNameSuffixes)) {
+		if strings.HasSuffix(name, suffix) {
+			return fmt.Sprintf("name suffix %s of %s", suffix, managedNameSuffixes[suffix])
+		}
+	}
+	for _, tag := range secGrp.Tags {
+		key := aws.ToString(tag.Key)
+		for _, prefix := range managedTagPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				return fmt.Sprintf("tag %s", key)
+			}
+		}
+	}
+	return ""
+}
+
+// isDeletable returns false if the group is protected. With deleteProtected only the default groups are kept.
+func (sec SecGrp) isDeletable(secGrp internal.SecurityGroup) bool {
+	if secGrp.ProtectedReason == "" {
+		return true
+	}
+	return sec.deleteProtected && aws.ToString(secGrp.GroupName) != DEFAULT_GROUP_NAME
+}
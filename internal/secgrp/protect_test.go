@@ -0,0 +1,130 @@
+package secgrp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectionReason(t *testing.T) {
+	tests := map[string]struct {
+		secGrp   internal.SecurityGroup
+		expected string
+	}{
+		"Default": {
+			secGrp:   internal.SecurityGroup{SecurityGroup: &ec2Types.SecurityGroup{GroupName: aws.String("default")}},
+			expected: "default security group of VPC",
+		},
+		"Requester Managed": {
+			secGrp:   internal.SecurityGroup{SecurityGroup: &ec2Types.SecurityGroup{GroupName: aws.String("lb")}, ManagedBy: []string{"amazon-elb"}},
+			expected: "attached to network interfaces managed by amazon-elb",
+		},
+		"Name Prefix": {
+			secGrp:   internal.SecurityGroup{SecurityGroup: &ec2Types.SecurityGroup{GroupName: aws.String("eks-cluster-sg-prod-123")}},
+			expected: "name prefix eks-cluster-sg- of EKS",
+		},
+		"Classic Load Balancer": {
+			secGrp:   internal.SecurityGroup{SecurityGroup: &ec2Types.SecurityGroup{GroupName: aws.String("default_elb_1234abcd-5678-90ef-1234-567890abcdef")}},
+			expected: "name prefix default_elb_ of Classic Load Balancer",
+		},
+		"Name Suffix": {
+			secGrp:   internal.SecurityGroup{SecurityGroup: &ec2Types.SecurityGroup{GroupName: aws.String("d-1234567890_controllers")}},
+			expected: "name suffix _controllers of Directory Service",
+		},
+		"Tag": {
+			secGrp: internal.SecurityGroup{SecurityGroup: &ec2Types.SecurityGroup{
+				GroupName: aws.String("nodes"),
+				Tags:      []ec2Types.Tag{{Key: aws.String("team"), Value: aws.String("a")}, {Key: aws.String("kubernetes.io/cluster/prod"), Value: aws.String("owned")}},
+			}},
+			expected: "tag kubernetes.io/cluster/prod",
+		},
+		"Not Protected": {
+			secGrp: internal.SecurityGroup{SecurityGroup: &ec2Types.SecurityGroup{
+				GroupName: aws.String("web"),
+				Tags:      []ec2Types.Tag{{Key: aws.String("team"), Value: aws.String("a")}},
+			}},
+			expected: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, protectionReason(test.secGrp))
+		})
+	}
+}
+
+func TestDeleteProtected(t *testing.T) {
+	expectedEndtime, err := time.Parse(time.DateTime, "2006-01-02 15:04:05")
+	require.NoError(t, err)
+	expectedStarttime := expectedEndtime.Add(ninetyDayOffset * -1)
+
+	groups := []ec2Types.SecurityGroup{
+		{GroupId: aws.String("sg-default"), GroupName: aws.String("default")},
+		{GroupId: aws.String("sg-eks"), GroupName: aws.String("eks-cluster-sg-prod-123")},
+		{GroupId: aws.String("sg-web"), GroupName: aws.String("web")},
+	}
+
+	tests := map[string]struct {
+		deleteProtected bool
+		expected        []string
+	}{
+		"Keep Protected":    {deleteProtected: false, expected: []string{"sg-web"}},
+		"Include Protected": {deleteProtected: true, expected: []string{"sg-eks", "sg-web"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ec2Mock := mocks.NewMockEc2client(t)
+			cloudTrailMock := mocks.NewMockCloudTrail(t)
+			awsClient := internal.NewFromInterface(ec2Mock, cloudTrailMock)
+			SUT := NewInstance(awsClient, nil, false, true, WithDeleteProtected(test.deleteProtected))
+
+			mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), "sg-web")
+			mockDescribeSecGrpsWithRules(ec2Mock, groups...)
+			for _, grp := range groups {
+				mockDescribeNetIfaces(ec2Mock, *grp.GroupId)
+			}
+
+			deleted := []string{}
+			ec2Mock.EXPECT().DeleteSecurityGroup(context.TODO(), mock.Anything).
+				Run(func(ctx context.Context, params *ec2.DeleteSecurityGroupInput, optFns ...func(*ec2.Options)) {
+					deleted = append(deleted, *params.GroupId)
+				}).
+				Return(&ec2.DeleteSecurityGroupOutput{}, nil).Times(len(test.expected))
+
+			err = SUT.DeleteSecurityGroups(expectedStarttime, expectedEndtime)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, deleted)
+		})
+	}
+}
+
+func TestProtectedReasonIsListed(t *testing.T) {
+	expectedEndtime, err := time.Parse(time.DateTime, "2006-01-02 15:04:05")
+	require.NoError(t, err)
+	expectedStarttime := expectedEndtime.Add(ninetyDayOffset * -1)
+
+	SUT, ec2Mock, cloudTrailMock := setupSUT(t, nil, false, false)
+	mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), "sg-default")
+	mockDescribeSecGrps(ec2Mock, "sg-default", "default")
+	mockDescribeNetIfaces(ec2Mock, "sg-default")
+	SUT.olderthen = aws.Duration(time.Hour)
+
+	err = SUT.GetSecurityGroups(expectedStarttime, expectedEndtime)
+	require.NoError(t, err)
+
+	all := SUT.GetAllSecurityGroups()
+	require.Contains(t, all, "sg-default")
+	assert.Equal(t, "default security group of VPC", all["sg-default"].ProtectedReason)
+}
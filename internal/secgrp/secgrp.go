@@ -10,24 +10,53 @@ import (
 	eslog "github.com/steffakasid/eslog"
 )
 
+// DEFAULT_CONCURRENCY is the default number of parallel DescribeNetworkInterfaces calls.
+const DEFAULT_CONCURRENCY = 10
+
 type SecGrp struct {
-	awsClient     *internal.AWS
-	olderthen     *time.Duration
-	dryrun        bool
-	onlyUnused    bool
-	usedSecGrps   *internal.SecurityGroups
-	unusedSecGrps *internal.SecurityGroups
+	awsClient      *internal.AWS
+	olderthen      *time.Duration
+	dryrun         bool
+	onlyUnused     bool
+	deleteClusters bool
+	// delete groups which are managed by other services
+	deleteProtected bool
+	concurrency     int
+	usedSecGrps     *internal.SecurityGroups
+	unusedSecGrps   *internal.SecurityGroups
+}
+
+type Option func(*SecGrp)
+
+// WithDeleteClusters treats groups which are only referenced by other unused groups as unused. Such
+// clusters are deleted in dependency order: referencing groups first.
+func WithDeleteClusters(deleteClusters bool) Option {
+	return func(sec *SecGrp) {
+		sec.deleteClusters = deleteClusters
+	}
 }
 
-func NewInstance(awsClient *internal.AWS, olderthen *time.Duration, dryrun, onlyUnused bool) *SecGrp {
-	return &SecGrp{
+// WithConcurrency sets how many groups are looked up in parallel to find the attached network interfaces.
+func WithConcurrency(concurrency int) Option {
+	return func(sec *SecGrp) {
+		sec.concurrency = concurrency
+	}
+}
+
+func NewInstance(awsClient *internal.AWS, olderthen *time.Duration, dryrun, onlyUnused bool, opts ...Option) *SecGrp {
+	sec := &SecGrp{
 		awsClient:     awsClient,
 		olderthen:     olderthen,
 		dryrun:        dryrun,
 		onlyUnused:    onlyUnused,
+		concurrency:   DEFAULT_CONCURRENCY,
 		usedSecGrps:   &internal.SecurityGroups{},
 		unusedSecGrps: &internal.SecurityGroups{},
 	}
+	for _, opt := range opts {
+		opt(sec)
+	}
+	return sec
 }
 
 func (sec *SecGrp) GetSecurityGroups(startTime, endTime time.Time) error {
@@ -36,7 +65,10 @@ func (sec *SecGrp) GetSecurityGroups(startTime, endTime time.Time) error {
 	var err error
 
 	eslog.Logger.Debug("GetCloudTrailForSecGroups")
-	secGrpsFromCCTrail := sec.awsClient.GetCloudTrailForSecGroups(startTime, endTime)
+	secGrpsFromCCTrail, err := sec.awsClient.GetCloudTrailForSecGroups(startTime, endTime)
+	if err != nil {
+		return fmt.Errorf("could not get CloudTrail events of SecurityGroups: %w", err)
+	}
 
 	// if startTime is before 90d in past we want to get additional SecurityGroups which are not in CloudTrail
 	eslog.Logger.Debug("GetSecurityGroups")
@@ -45,6 +77,8 @@ func (sec *SecGrp) GetSecurityGroups(startTime, endTime time.Time) error {
 		return fmt.Errorf("could not getSecurityGroups: %w", err)
 	}
 	secGrps.AppendAll(result)
+	// references must be set before skipped groups are removed as they still block a deletion
+	secGrps.SetReferences()
 
 	skippedSecGrps := secGrps.UpdateIfExists(secGrpsFromCCTrail)
 	eslog.Logger.Debugf("After additionalDetails len(secGrps) %d", len(secGrps))
@@ -59,12 +93,15 @@ func (sec *SecGrp) GetSecurityGroups(startTime, endTime time.Time) error {
 	// TODO: olderthen is not used here, we could filter and only return secgrps which are olderthen
 	if sec.onlyUnused || sec.olderthen != nil {
 		eslog.Logger.Debug("GetNotUsedSecGrpsFromENI")
-		sec.usedSecGrps, sec.unusedSecGrps, err = sec.awsClient.GetNotUsedSecGrpsFromENI(secGrps)
+		sec.usedSecGrps, sec.unusedSecGrps, err = sec.awsClient.GetNotUsedSecGrpsFromENI(secGrps, sec.concurrency)
 		eslog.Logger.Debugf("GetNotUsedSecGrpsFromENI() len(secGrps) %d", len(secGrps))
 		if err != nil {
 			return fmt.Errorf("could not get GetNotUsedSecGrpsFromENI() %w", err)
 		}
+		sec.markReferencedAsUsed()
 	}
+	protect(*sec.usedSecGrps)
+	protect(*sec.unusedSecGrps)
 
 	eslog.Logger.Debug("secgrp.go GetSecurityGroups returning no error")
 	return nil
@@ -77,24 +114,29 @@ func (sec SecGrp) DeleteSecurityGroups(startTime, endTime time.Time, ignoredIDs
 	}
 
 	if sec.onlyUnused {
-		for _, secGrp := range *sec.unusedSecGrps {
-			if slices.Contains(ignoredIDs, *secGrp.GroupId) {
-				eslog.Debugf("Skipping because of ignore flag: %s - %s", *secGrp.GroupName, *secGrp.GroupId)
-				continue
-			}
-			if secGrp.CreationTime == nil ||
-				sec.olderthen == nil ||
-				(sec.olderthen != nil && secGrp.CreationTime.Before(time.Now().Add(*sec.olderthen*-1))) {
-				if sec.olderthen == nil {
-					eslog.Logger.Info("olderthen not set ignoring CreationTime of SecurityGroup")
+		// delete in dependency order: a group can only be deleted if no remaining group references it
+		remaining := slices.Sorted(maps.Keys(*sec.unusedSecGrps))
+		kept := []string{}
+		for len(remaining) > 0 {
+			deletable, blocked := []string{}, []string{}
+			for _, groupID := range remaining {
+				if referencedByAny((*sec.unusedSecGrps)[groupID], remaining) {
+					blocked = append(blocked, groupID)
+				} else {
+					deletable = append(deletable, groupID)
 				}
-				err := sec.awsClient.DeleteSecurityGroup(*secGrp, sec.dryrun)
-				if err != nil {
-					eslog.LogIfErrorf(err, eslog.Errorf, "error deleting security group: %s")
+			}
+			if len(deletable) == 0 {
+				eslog.Logger.Warnf("Skipping %v as the groups reference each other", blocked)
+				break
+			}
+
+			for _, groupID := range deletable {
+				if !sec.deleteSecurityGroup((*sec.unusedSecGrps)[groupID], kept, ignoredIDs) {
+					kept = append(kept, groupID)
 				}
-			} else {
-				eslog.Logger.Infof("Skipping because of CreationDate %s - %s: %s", *secGrp.GroupName, *secGrp.GroupId, secGrp.CreationTime.Format(time.RFC3339))
 			}
+			remaining = blocked
 		}
 	} else {
 		eslog.Logger.Warn("DeleteSecurityGroups() only-unused flag is not set. Not implemented as SecurityGroups can't be deleted if in use.")
@@ -103,6 +145,71 @@ func (sec SecGrp) DeleteSecurityGroups(startTime, endTime time.Time, ignoredIDs
 	return nil
 }
 
+// deleteSecurityGroup deletes secGrp if it is old enough. It returns false if the group is kept.
+func (sec SecGrp) deleteSecurityGroup(secGrp *internal.SecurityGroup, kept []string, ignoredIDs []string) bool {
+	if slices.Contains(ignoredIDs, *secGrp.GroupId) {
+		eslog.Debugf("Skipping because of ignore flag: %s - %s", *secGrp.GroupName, *secGrp.GroupId)
+		return false
+	}
+	if !sec.isDeletable(*secGrp) {
+		eslog.Logger.Infof("Skipping protected group %s - %s: %s", *secGrp.GroupName, *secGrp.GroupId, secGrp.ProtectedReason)
+		return false
+	}
+	if referencedByAny(secGrp, kept) {
+		eslog.Logger.Infof("Skipping because still referenced by kept groups %s - %s: %v", *secGrp.GroupName, *secGrp.GroupId, secGrp.ReferencedBy)
+		return false
+	}
+	if secGrp.CreationTime == nil ||
+		sec.olderthen == nil ||
+		(sec.olderthen != nil && secGrp.CreationTime.Before(time.Now().Add(*sec.olderthen*-1))) {
+		if sec.olderthen == nil {
+			eslog.Logger.Info("olderthen not set ignoring CreationTime of SecurityGroup")
+		}
+		err := sec.awsClient.DeleteSecurityGroup(*secGrp, sec.dryrun)
+		if err != nil && !internal.IsDryRunOperation(err) {
+			eslog.LogIfErrorf(err, eslog.Errorf, "error deleting security group: %s")
+			return false
+		}
+		return true
+	}
+	eslog.Logger.Infof("Skipping because of CreationDate %s - %s: %s", *secGrp.GroupName, *secGrp.GroupId, secGrp.CreationTime.Format(time.RFC3339))
+	return false
+}
+
+// markReferencedAsUsed moves unused groups which are referenced by other groups to the used groups. With
+// deleteClusters only references of used groups count, which is repeated until no further group is moved.
+func (sec *SecGrp) markReferencedAsUsed() {
+	for moved := true; moved; {
+		moved = false
+		for _, groupID := range slices.Sorted(maps.Keys(*sec.unusedSecGrps)) {
+			secGrp := (*sec.unusedSecGrps)[groupID]
+			for _, referencingID := range secGrp.ReferencedBy {
+				if _, unused := (*sec.unusedSecGrps)[referencingID]; unused && sec.deleteClusters {
+					continue
+				}
+				eslog.Logger.Debugf("Group %s is referenced by %s", groupID, referencingID)
+				secGrp.IsUsed = true
+				(*sec.usedSecGrps)[groupID] = secGrp
+				delete(*sec.unusedSecGrps, groupID)
+				moved = true
+				break
+			}
+		}
+		if !sec.deleteClusters {
+			break
+		}
+	}
+}
+
+func referencedByAny(secGrp *internal.SecurityGroup, groupIDs []string) bool {
+	for _, referencingID := range secGrp.ReferencedBy {
+		if slices.Contains(groupIDs, referencingID) {
+			return true
+		}
+	}
+	return false
+}
+
 func (sec SecGrp) GetAllSecurityGroups() internal.SecurityGroups {
 	all := internal.SecurityGroups{}
 
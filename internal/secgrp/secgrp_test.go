@@ -13,6 +13,7 @@ import (
 	"github.com/steffakasid/awsclean/internal"
 	"github.com/steffakasid/awsclean/internal/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/xhit/go-str2duration/v2"
 )
@@ -45,17 +46,17 @@ func TestGetSecurityGroups(t *testing.T) {
 
 		SUT, ec2Mock, cloudTrailMock := setupSUT(t, nil, dryrun, unused)
 
-		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), expectedSecGrpName)
+		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), expectedSecGrpID)
 		mockDescribeSecGrps(ec2Mock, expectedSecGrpID, expectedSecGrpName)
-		mockDescribeNetIfaces(ec2Mock, expectedSecGrpName)
+		mockDescribeNetIfaces(ec2Mock, expectedSecGrpID)
 
 		err = SUT.GetSecurityGroups(expectedStarttime, expectedEndtime)
 		require.NoError(t, err)
 		ec2Mock.AssertExpectations(t)
 		assert.Len(t, *SUT.unusedSecGrps, 1)
 		assert.Len(t, *SUT.usedSecGrps, 0)
-		assert.Contains(t, *SUT.unusedSecGrps, expectedSecGrpName)
-		assert.Equal(t, "username", (*SUT.unusedSecGrps)[expectedSecGrpName].Creator)
+		assert.Contains(t, *SUT.unusedSecGrps, expectedSecGrpID)
+		assert.Equal(t, "username", (*SUT.unusedSecGrps)[expectedSecGrpID].Creator)
 	})
 	t.Run("Success Get Created 8d Ago", func(t *testing.T) {
 		expectedSecGrpID := "6987698-1243"
@@ -70,17 +71,17 @@ func TestGetSecurityGroups(t *testing.T) {
 
 		SUT, ec2Mock, cloudTrailMock := setupSUT(t, nil, dryrun, unused)
 
-		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), expectedSecGrpName)
+		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), expectedSecGrpID)
 		mockDescribeSecGrps(ec2Mock, expectedSecGrpID, expectedSecGrpName)
-		mockDescribeNetIfaces(ec2Mock, expectedSecGrpName)
+		mockDescribeNetIfaces(ec2Mock, expectedSecGrpID)
 
 		err = SUT.GetSecurityGroups(expectedStarttime, expectedEndtime)
 		require.NoError(t, err)
 		ec2Mock.AssertExpectations(t)
 		assert.Len(t, *SUT.unusedSecGrps, 1)
 		assert.Len(t, *SUT.usedSecGrps, 0)
-		assert.Contains(t, *SUT.unusedSecGrps, expectedSecGrpName)
-		assert.Equal(t, "username", (*SUT.unusedSecGrps)[expectedSecGrpName].Creator)
+		assert.Contains(t, *SUT.unusedSecGrps, expectedSecGrpID)
+		assert.Equal(t, "username", (*SUT.unusedSecGrps)[expectedSecGrpID].Creator)
 	})
 }
 
@@ -98,9 +99,9 @@ func TestDeleteSecurityGroups(t *testing.T) {
 
 		SUT, ec2Mock, cloudTrailMock := setupSUT(t, nil, dryrun, onlyUnused)
 
-		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), expectedSecGrpName)
+		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), expectedSecGrpID)
 		mockDescribeSecGrps(ec2Mock, expectedSecGrpID, expectedSecGrpName)
-		mockDescribeNetIfaces(ec2Mock, expectedSecGrpName)
+		mockDescribeNetIfaces(ec2Mock, expectedSecGrpID)
 
 		expectedDeleteSecGrpOpts := &ec2.DeleteSecurityGroupInput{
 			DryRun:  &dryrun,
@@ -128,9 +129,9 @@ func TestDeleteSecurityGroups(t *testing.T) {
 
 		SUT, ec2Mock, cloudTrailMock := setupSUT(t, &olderthen, dryrun, onlyUnused)
 
-		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now().Add(olderthen*-1), expectedSecGrpName)
+		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now().Add(olderthen*-1), expectedSecGrpID)
 		mockDescribeSecGrps(ec2Mock, expectedSecGrpID, expectedSecGrpName)
-		mockDescribeNetIfaces(ec2Mock, expectedSecGrpName)
+		mockDescribeNetIfaces(ec2Mock, expectedSecGrpID)
 
 		expectedDeleteSecGrpOpts := &ec2.DeleteSecurityGroupInput{
 			DryRun:  &dryrun,
@@ -149,13 +150,104 @@ func TestDeleteSecurityGroups(t *testing.T) {
 	
 }
 
+func TestReferencedSecurityGroups(t *testing.T) {
+	expectedEndtime, err := time.Parse(time.DateTime, "2006-01-02 15:04:05")
+	require.NoError(t, err)
+	expectedStarttime := expectedEndtime.Add(ninetyDayOffset * -1)
+
+	// sg-a references sg-b which references sg-c
+	groups := []ec2Types.SecurityGroup{
+		{
+			GroupId:   aws.String("sg-a"),
+			GroupName: aws.String("a"),
+			IpPermissions: []ec2Types.IpPermission{
+				{UserIdGroupPairs: []ec2Types.UserIdGroupPair{{GroupId: aws.String("sg-b")}}},
+			},
+		},
+		{
+			GroupId:   aws.String("sg-b"),
+			GroupName: aws.String("b"),
+			IpPermissionsEgress: []ec2Types.IpPermission{
+				{UserIdGroupPairs: []ec2Types.UserIdGroupPair{{GroupId: aws.String("sg-c")}}},
+			},
+		},
+		{
+			GroupId:   aws.String("sg-c"),
+			GroupName: aws.String("c"),
+		},
+	}
+
+	t.Run("Referenced Are Used", func(t *testing.T) {
+		SUT, ec2Mock, cloudTrailMock := setupSUT(t, nil, false, true)
+
+		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), "sg-a")
+		mockDescribeSecGrpsWithRules(ec2Mock, groups...)
+		for _, grp := range groups {
+			mockDescribeNetIfaces(ec2Mock, *grp.GroupId)
+		}
+
+		err = SUT.GetSecurityGroups(expectedStarttime, expectedEndtime)
+		require.NoError(t, err)
+
+		assert.Len(t, *SUT.unusedSecGrps, 1)
+		assert.Contains(t, *SUT.unusedSecGrps, "sg-a")
+		assert.Len(t, *SUT.usedSecGrps, 2)
+		assert.True(t, (*SUT.usedSecGrps)["sg-b"].IsUsed)
+		assert.Equal(t, []string{"sg-a"}, (*SUT.usedSecGrps)["sg-b"].ReferencedBy)
+		assert.Equal(t, []string{"sg-b"}, (*SUT.usedSecGrps)["sg-c"].ReferencedBy)
+	})
+
+	t.Run("Delete Clusters In Dependency Order", func(t *testing.T) {
+		ec2Mock := mocks.NewMockEc2client(t)
+		cloudTrailMock := mocks.NewMockCloudTrail(t)
+		awsClient := internal.NewFromInterface(ec2Mock, cloudTrailMock)
+		SUT := NewInstance(awsClient, nil, false, true, WithDeleteClusters(true))
+
+		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), "sg-a")
+		mockDescribeSecGrpsWithRules(ec2Mock, groups...)
+		for _, grp := range groups {
+			mockDescribeNetIfaces(ec2Mock, *grp.GroupId)
+		}
+
+		deleted := []string{}
+		ec2Mock.EXPECT().DeleteSecurityGroup(context.TODO(), mock.Anything).
+			Run(func(ctx context.Context, params *ec2.DeleteSecurityGroupInput, optFns ...func(*ec2.Options)) {
+				deleted = append(deleted, *params.GroupId)
+			}).
+			Return(&ec2.DeleteSecurityGroupOutput{}, nil).Times(3)
+
+		err = SUT.DeleteSecurityGroups(expectedStarttime, expectedEndtime)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"sg-a", "sg-b", "sg-c"}, deleted)
+	})
+
+	t.Run("Keep Referenced By Ignored", func(t *testing.T) {
+		ec2Mock := mocks.NewMockEc2client(t)
+		cloudTrailMock := mocks.NewMockCloudTrail(t)
+		awsClient := internal.NewFromInterface(ec2Mock, cloudTrailMock)
+		SUT := NewInstance(awsClient, nil, false, true, WithDeleteClusters(true))
+
+		mockLookupEvents(cloudTrailMock, expectedStarttime, expectedEndtime, time.Now(), "sg-a")
+		mockDescribeSecGrpsWithRules(ec2Mock, groups...)
+		for _, grp := range groups {
+			mockDescribeNetIfaces(ec2Mock, *grp.GroupId)
+		}
+
+		err = SUT.DeleteSecurityGroups(expectedStarttime, expectedEndtime, "sg-a")
+		require.NoError(t, err)
+
+		ec2Mock.AssertNotCalled(t, "DeleteSecurityGroup", mock.Anything, mock.Anything)
+	})
+}
+
 func mockDescribeNetIfaces(ec2Mock *mocks.MockEc2client,
-	expectedSecGrpName string) {
+	expectedSecGrpID string) {
 	expectedDescribeNetIfaceOpts := &ec2.DescribeNetworkInterfacesInput{
 		Filters: []ec2Types.Filter{
 			{
-				Name:   aws.String("group-name"),
-				Values: []string{expectedSecGrpName},
+				Name:   aws.String("group-id"),
+				Values: []string{expectedSecGrpID},
 			},
 		},
 	}
@@ -165,23 +257,25 @@ func mockDescribeNetIfaces(ec2Mock *mocks.MockEc2client,
 
 func mockDescribeSecGrps(ec2Mock *mocks.MockEc2client,
 	expectedSecGrpID, expectedSecGrpName string) {
+	mockDescribeSecGrpsWithRules(ec2Mock, ec2Types.SecurityGroup{
+		GroupId:   aws.String(expectedSecGrpID),
+		GroupName: aws.String(expectedSecGrpName),
+	})
+}
+
+func mockDescribeSecGrpsWithRules(ec2Mock *mocks.MockEc2client, secGrps ...ec2Types.SecurityGroup) {
 	expectedDescribeSecGrpsOpts := &ec2.DescribeSecurityGroupsInput{
 		MaxResults: aws.Int32(1000),
 	}
 	expectedDescribeSecGrpsOut := &ec2.DescribeSecurityGroupsOutput{
-		SecurityGroups: []ec2Types.SecurityGroup{
-			{
-				GroupId:   aws.String(expectedSecGrpID),
-				GroupName: aws.String(expectedSecGrpName),
-			},
-		},
+		SecurityGroups: secGrps,
 	}
 	ec2Mock.EXPECT().DescribeSecurityGroups(context.TODO(), expectedDescribeSecGrpsOpts).Return(expectedDescribeSecGrpsOut, nil).Once()
 }
 
 func mockLookupEvents(cloudTrailMock *mocks.MockCloudTrail,
 	expectedStarttime, expectedEndtime, expectedEventDatetime time.Time,
-	expectedSecGrpName string) {
+	expectedSecGrpID string) {
 	expectedLookupEventsIn := &cloudtrail.LookupEventsInput{
 		StartTime: &expectedStarttime,
 		EndTime:   &expectedEndtime,
@@ -195,11 +289,12 @@ func mockLookupEvents(cloudTrailMock *mocks.MockCloudTrail,
 	expectedLookupEventsOut := &cloudtrail.LookupEventsOutput{
 		Events: []cloudtrailTypes.Event{
 			{
+				EventName: aws.String(string(internal.SECURITYGROUP_CREATED)),
 				EventTime: aws.Time(expectedEventDatetime),
 				Username:  aws.String("username"),
 				Resources: []cloudtrailTypes.Resource{
 					{
-						ResourceName: aws.String(expectedSecGrpName),
+						ResourceName: aws.String(expectedSecGrpID),
 						ResourceType: aws.String(internal.CLOUDTRAIL_RESOURCE_TYPE),
 					},
 				},
@@ -207,4 +302,16 @@ func mockLookupEvents(cloudTrailMock *mocks.MockCloudTrail,
 		},
 	}
 	cloudTrailMock.EXPECT().LookupEvents(context.TODO(), expectedLookupEventsIn).Return(expectedLookupEventsOut, nil).Once()
+
+	expectedLookupDeleteEventsIn := &cloudtrail.LookupEventsInput{
+		StartTime: &expectedStarttime,
+		EndTime:   &expectedEndtime,
+		LookupAttributes: []cloudtrailTypes.LookupAttribute{
+			{
+				AttributeKey:   cloudtrailTypes.LookupAttributeKeyEventName,
+				AttributeValue: aws.String(string(internal.SECURITYGROUP_DELETED)),
+			},
+		},
+	}
+	cloudTrailMock.EXPECT().LookupEvents(context.TODO(), expectedLookupDeleteEventsIn).Return(&cloudtrail.LookupEventsOutput{}, nil).Once()
 }
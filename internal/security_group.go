@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/steffakasid/eslog"
 )
@@ -16,9 +18,26 @@ type SecurityGroup struct {
 	Creator             string
 	IsUsed              bool
 	AttachedToNetIfaces []string
+	// requesters of the requester-managed network interfaces the group is attached to, e.g. amazon-elb
+	ManagedBy []string
+	// why the group must not be deleted, empty if it's not protected
+	ProtectedReason string
+	// IDs of other groups which reference this group in an ingress or egress rule
+	ReferencedBy []string
+	Deleter      string
+	DeletionTime *time.Time
+	// CloudTrail events which created or deleted the group in chronological order
+	History []LifecycleEvent
 }
 
-// the key must be the SecurityGroup.GroupName of the SecurityGroup value
+// LifecycleEvent is a CreateSecurityGroup or DeleteSecurityGroup event recorded by CloudTrail.
+type LifecycleEvent struct {
+	EventName string
+	Username  string
+	EventTime *time.Time
+}
+
+// the key must be the SecurityGroup.GroupId of the SecurityGroup value. Group names are only unique per VPC.
 type SecurityGroups map[string]*SecurityGroup
 
 func (grps SecurityGroups) AddOrUpdate(grpToAdd SecurityGroup) error {
@@ -26,17 +45,27 @@ func (grps SecurityGroups) AddOrUpdate(grpToAdd SecurityGroup) error {
 	if grpToAdd.SecurityGroup == nil {
 		return errors.New("AddOrUpdate() grpToAdd.SecurityGroup is nil")
 	}
-	if grpToAdd.GroupName == nil {
-		return errors.New("AddOrUpdate() grpToAdd.SecurityGroup.GroupName is nil")
+	if grpToAdd.GroupId == nil {
+		// without ID we can only update a group which name is unique
+		if grpToAdd.GroupName == nil {
+			return errors.New("AddOrUpdate() grpToAdd.SecurityGroup.GroupId is nil")
+		}
+		grp, exists := grps.getValueByIDorName(*grpToAdd.GroupName)
+		if !exists {
+			return fmt.Errorf("AddOrUpdate() grpToAdd.SecurityGroup.GroupId is nil and %s is not unique", *grpToAdd.GroupName)
+		}
+		err := grp.mergeFields(grpToAdd)
+		eslog.LogIfErrorf(err, eslog.Errorf, "error in AddOrUpdate(): %s", err)
+		return nil
 	}
 
-	groupName := *grpToAdd.GroupName
+	groupID := *grpToAdd.GroupId
 
-	if grp, exists := grps[groupName]; exists {
+	if grp, exists := grps[groupID]; exists {
 		err := grp.mergeFields(grpToAdd)
-		eslog.LogIfErrorf(err, eslog.Errorf, "error in AddOrUpdate(): %s", &err)
+		eslog.LogIfErrorf(err, eslog.Errorf, "error in AddOrUpdate(): %s", err)
 	} else {
-		grps[groupName] = &grpToAdd
+		grps[groupID] = &grpToAdd
 	}
 	return nil
 }
@@ -63,7 +92,11 @@ func (grps SecurityGroups) UpdateIfExists(src SecurityGroups) (skipped SecurityG
 
 			grps[key] = tgtObj
 		} else {
-			eslog.Logger.Infof("%s doesn't seem to exist anymore. Skipping Update of result set.", key)
+			if val.IsDeleted() {
+				eslog.Logger.Debugf("%s was deleted at %s by %s. Skipping Update of result set.", key, val.DeletionTime.Format(time.RFC3339), val.Deleter)
+			} else {
+				eslog.Logger.Infof("%s doesn't seem to exist anymore. Skipping Update of result set.", key)
+			}
 			skipped[key] = val
 		}
 	}
@@ -77,20 +110,87 @@ func (grps SecurityGroups) DeleteSkipped(skipped SecurityGroups) {
 	})
 }
 
+// SetReferences sets ReferencedBy of each group to the IDs of the other groups which use it as source or
+// destination in an ingress or egress rule. Self references are ignored as they don't prevent a deletion.
+func (grps SecurityGroups) SetReferences() {
+	for _, referencingID := range slices.Sorted(maps.Keys(grps)) {
+		grp := grps[referencingID]
+		if grp.SecurityGroup == nil {
+			continue
+		}
+
+		for _, permission := range slices.Concat(grp.IpPermissions, grp.IpPermissionsEgress) {
+			for _, pair := range permission.UserIdGroupPairs {
+				if pair.GroupId == nil || *pair.GroupId == referencingID {
+					continue
+				}
+				if referenced, exists := grps[*pair.GroupId]; exists {
+					referenced.ReferencedBy = UniqueAppend(referenced.ReferencedBy, referencingID)
+				}
+			}
+		}
+	}
+}
+
+// addLifecycleEvent adds ev to the History and updates the creator and deleter of the group accordingly.
+func (grp *SecurityGroup) addLifecycleEvent(ev LifecycleEvent) {
+	if slices.ContainsFunc(grp.History, func(existing LifecycleEvent) bool {
+		return existing.EventName == ev.EventName && existing.Username == ev.Username &&
+			aws.ToTime(existing.EventTime).Equal(aws.ToTime(ev.EventTime))
+	}) {
+		return
+	}
+	grp.History = append(grp.History, ev)
+	slices.SortStableFunc(grp.History, func(a, b LifecycleEvent) int {
+		return aws.ToTime(a.EventTime).Compare(aws.ToTime(b.EventTime))
+	})
+
+	// the latest events win, e.g. if a group with the same name was created again
+	for _, histEv := range grp.History {
+		switch cloudTrailEventType(histEv.EventName) {
+		case SECURITYGROUP_CREATED:
+			grp.Creator = histEv.Username
+			grp.CreationTime = histEv.EventTime
+		case SECURITYGROUP_DELETED:
+			grp.Deleter = histEv.Username
+			grp.DeletionTime = histEv.EventTime
+		}
+	}
+}
+
+// IsDeleted returns true if the latest event in the History deleted the group.
+func (grp SecurityGroup) IsDeleted() bool {
+	return len(grp.History) > 0 &&
+		cloudTrailEventType(grp.History[len(grp.History)-1].EventName) == SECURITYGROUP_DELETED
+}
+
+// getValueByIDorName returns the group with the given ID. A name is only resolved if exactly one group has
+// that name.
 func (grps SecurityGroups) getValueByIDorName(idOrName string) (value *SecurityGroup, exists bool) {
-	// if it's the name we can just get it ad key from the map by convention.
+	// if it's the ID we can just get it as key from the map by convention.
 	if grp, exists := grps[idOrName]; exists {
 		return grp, true
 	}
 
+	var byName *SecurityGroup
+	ambiguous := false
 	for _, grp := range grps {
-		if grp.SecurityGroup != nil &&
-			*grp.GroupId == idOrName {
+		if grp.SecurityGroup == nil {
+			continue
+		}
+		if aws.ToString(grp.GroupId) == idOrName {
 			return grp, true
 		}
+		if aws.ToString(grp.GroupName) == idOrName {
+			ambiguous = byName != nil
+			byName = grp
+		}
 	}
 
-	return nil, false
+	if byName == nil || ambiguous {
+		return nil, false
+	}
+	return byName, true
 }
 
 // Basically add details from src to tgt.
@@ -106,11 +206,17 @@ func (tgt *SecurityGroup) mergeFields(src SecurityGroup) error {
 			tgt.SecurityGroup = src.SecurityGroup
 		}
 
-		if src.SecurityGroup != nil &&
-			src.GroupName != nil &&
-			tgt.GroupName != nil &&
-			*src.GroupName != *tgt.GroupName {
-			return fmt.Errorf("error mergin SecurityGroups: %s != %s", *src.GroupName, *tgt.GroupName)
+		if src.SecurityGroup != nil && tgt.SecurityGroup != nil {
+			if src.GroupId != nil &&
+				tgt.GroupId != nil &&
+				*src.GroupId != *tgt.GroupId {
+				return fmt.Errorf("error mergin SecurityGroups: %s != %s", *src.GroupId, *tgt.GroupId)
+			}
+			if src.GroupName != nil &&
+				tgt.GroupName != nil &&
+				*src.GroupName != *tgt.GroupName {
+				return fmt.Errorf("error mergin SecurityGroups: %s != %s", *src.GroupName, *tgt.GroupName)
+			}
 		}
 	}
 
@@ -122,6 +228,10 @@ func (tgt *SecurityGroup) mergeFields(src SecurityGroup) error {
 		tgt.SecurityGroup = src.SecurityGroup
 	}
 
+	for _, ev := range src.History {
+		tgt.addLifecycleEvent(ev)
+	}
+
 	if src.Creator != "" && tgt.Creator == "" {
 		tgt.Creator = src.Creator
 	}
@@ -143,5 +253,17 @@ func (tgt *SecurityGroup) mergeFields(src SecurityGroup) error {
 		tgt.AttachedToNetIfaces = src.AttachedToNetIfaces
 	}
 
+	for _, requester := range src.ManagedBy {
+		tgt.ManagedBy = UniqueAppend(tgt.ManagedBy, requester)
+	}
+
+	if src.ProtectedReason != "" && tgt.ProtectedReason == "" {
+		tgt.ProtectedReason = src.ProtectedReason
+	}
+
+	for _, referencingID := range src.ReferencedBy {
+		tgt.ReferencedBy = UniqueAppend(tgt.ReferencedBy, referencingID)
+	}
+
 	return nil
 }
@@ -30,7 +30,7 @@ func TestAddOrUpdate(t *testing.T) {
 		require.NoError(t, err)
 
 		assert.Len(t, grps, 1)
-		assert.Contains(t, grps, expectedName)
+		assert.Contains(t, grps, expectedID)
 
 	})
 
@@ -42,7 +42,7 @@ func TestAddOrUpdate(t *testing.T) {
 		expectedIFace := "someNetIface"
 
 		grps := SecurityGroups{
-			expectedName: &SecurityGroup{
+			expectedID: &SecurityGroup{
 				SecurityGroup: &types.SecurityGroup{
 					GroupId:   &expectedID,
 					GroupName: &expectedName,
@@ -64,10 +64,10 @@ func TestAddOrUpdate(t *testing.T) {
 		err := grps.AddOrUpdate(grpToAdd)
 		require.NoError(t, err)
 		assert.Len(t, grps, 1)
-		assert.Equal(t, expectedCreator, grps[expectedName].Creator)
-		assert.Equal(t, expecteCreationTime, *grps[expectedName].CreationTime)
-		assert.True(t, grps[expectedName].IsUsed)
-		assert.Contains(t, grps[expectedName].AttachedToNetIfaces, expectedIFace)
+		assert.Equal(t, expectedCreator, grps[expectedID].Creator)
+		assert.Equal(t, expecteCreationTime, *grps[expectedID].CreationTime)
+		assert.True(t, grps[expectedID].IsUsed)
+		assert.Contains(t, grps[expectedID].AttachedToNetIfaces, expectedIFace)
 	})
 
 	t.Run("Update Existing (details on src)", func(t *testing.T) {
@@ -78,7 +78,7 @@ func TestAddOrUpdate(t *testing.T) {
 		expectedIFace := "someNetIface"
 
 		grps := SecurityGroups{
-			expectedName: &SecurityGroup{
+			expectedID: &SecurityGroup{
 				SecurityGroup: &types.SecurityGroup{
 					GroupId:   &expectedID,
 					GroupName: &expectedName,
@@ -100,10 +100,10 @@ func TestAddOrUpdate(t *testing.T) {
 		err := grps.AddOrUpdate(grpToAdd)
 		require.NoError(t, err)
 		assert.Len(t, grps, 1)
-		assert.Equal(t, expectedCreator, grps[expectedName].Creator)
-		assert.Equal(t, expecteCreationTime, *grps[expectedName].CreationTime)
-		assert.True(t, grps[expectedName].IsUsed)
-		assert.Contains(t, grps[expectedName].AttachedToNetIfaces, expectedIFace)
+		assert.Equal(t, expectedCreator, grps[expectedID].Creator)
+		assert.Equal(t, expecteCreationTime, *grps[expectedID].CreationTime)
+		assert.True(t, grps[expectedID].IsUsed)
+		assert.Contains(t, grps[expectedID].AttachedToNetIfaces, expectedIFace)
 	})
 
 	t.Run("Update Existing (details on src)", func(t *testing.T) {
@@ -115,7 +115,7 @@ func TestAddOrUpdate(t *testing.T) {
 		expectedIFace := "someNetIface"
 
 		grps := SecurityGroups{
-			expectedName: &SecurityGroup{
+			expectedID: &SecurityGroup{
 				SecurityGroup: &types.SecurityGroup{
 					GroupId:     &expectedID,
 					GroupName:   &expectedName,
@@ -137,15 +137,30 @@ func TestAddOrUpdate(t *testing.T) {
 		err := grps.AddOrUpdate(grpToAdd)
 		require.NoError(t, err)
 		assert.Len(t, grps, 1)
-		assert.EqualValues(t, *grps[expectedName].SecurityGroup, types.SecurityGroup{
+		assert.EqualValues(t, *grps[expectedID].SecurityGroup, types.SecurityGroup{
 			GroupId:     &expectedID,
 			GroupName:   &expectedName,
 			Description: &expectedDescription,
 		})
-		assert.Equal(t, expectedCreator, grps[expectedName].Creator)
-		assert.Equal(t, expecteCreationTime, *grps[expectedName].CreationTime)
-		assert.True(t, grps[expectedName].IsUsed)
-		assert.Contains(t, grps[expectedName].AttachedToNetIfaces, expectedIFace)
+		assert.Equal(t, expectedCreator, grps[expectedID].Creator)
+		assert.Equal(t, expecteCreationTime, *grps[expectedID].CreationTime)
+		assert.True(t, grps[expectedID].IsUsed)
+		assert.Contains(t, grps[expectedID].AttachedToNetIfaces, expectedIFace)
+	})
+
+	t.Run("Same Name In Different VPCs", func(t *testing.T) {
+		grps := SecurityGroups{}
+
+		err := grps.AddOrUpdate(SecurityGroup{SecurityGroup: &types.SecurityGroup{GroupId: aws.String("sg-1"), GroupName: aws.String("default"), VpcId: aws.String("vpc-1")}})
+		require.NoError(t, err)
+		err = grps.AddOrUpdate(SecurityGroup{SecurityGroup: &types.SecurityGroup{GroupId: aws.String("sg-2"), GroupName: aws.String("default"), VpcId: aws.String("vpc-2")}})
+		require.NoError(t, err)
+		assert.Len(t, grps, 2)
+
+		err = grps.AddOrUpdate(SecurityGroup{SecurityGroup: &types.SecurityGroup{GroupName: aws.String("default")}, Creator: "Creator"})
+		require.EqualError(t, err, "AddOrUpdate() grpToAdd.SecurityGroup.GroupId is nil and default is not unique")
+		assert.Empty(t, grps["sg-1"].Creator)
+		assert.Empty(t, grps["sg-2"].Creator)
 	})
 }
 
@@ -221,6 +236,70 @@ func TestAppendAll(t *testing.T) {
 	}
 }
 
+func TestSetReferences(t *testing.T) {
+	newGrp := func(id string, ingress, egress []string) *SecurityGroup {
+		toPermission := func(ids []string) []types.IpPermission {
+			permissions := []types.IpPermission{}
+			for _, id := range ids {
+				permissions = append(permissions, types.IpPermission{
+					UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String(id)}},
+				})
+			}
+			return permissions
+		}
+		return &SecurityGroup{
+			SecurityGroup: &types.SecurityGroup{
+				GroupId:             aws.String(id),
+				GroupName:           aws.String(id),
+				IpPermissions:       toPermission(ingress),
+				IpPermissionsEgress: toPermission(egress),
+			},
+		}
+	}
+
+	grps := SecurityGroups{
+		"sg-a": newGrp("sg-a", []string{"sg-b", "sg-a"}, nil),
+		"sg-b": newGrp("sg-b", nil, []string{"sg-c", "sg-unknown"}),
+		"sg-c": newGrp("sg-c", []string{"sg-b"}, nil),
+		"sg-d": newGrp("sg-d", []string{"sg-c"}, []string{"sg-c"}),
+	}
+
+	grps.SetReferences()
+
+	assert.Empty(t, grps["sg-a"].ReferencedBy)
+	assert.Equal(t, []string{"sg-a", "sg-c"}, grps["sg-b"].ReferencedBy)
+	assert.Equal(t, []string{"sg-b", "sg-d"}, grps["sg-c"].ReferencedBy)
+	assert.Empty(t, grps["sg-d"].ReferencedBy)
+}
+
+func TestAddLifecycleEvent(t *testing.T) {
+	createdAt := time.Now().Add(-2 * time.Hour)
+	deletedAt := time.Now().Add(-time.Hour)
+
+	grp := &SecurityGroup{}
+	grp.addLifecycleEvent(LifecycleEvent{EventName: string(SECURITYGROUP_DELETED), Username: "deleter", EventTime: aws.Time(deletedAt)})
+	grp.addLifecycleEvent(LifecycleEvent{EventName: string(SECURITYGROUP_CREATED), Username: "creator", EventTime: aws.Time(createdAt)})
+	grp.addLifecycleEvent(LifecycleEvent{EventName: string(SECURITYGROUP_CREATED), Username: "creator", EventTime: aws.Time(createdAt)})
+
+	require.Len(t, grp.History, 2)
+	assert.Equal(t, string(SECURITYGROUP_CREATED), grp.History[0].EventName)
+	assert.Equal(t, "creator", grp.Creator)
+	assert.Equal(t, createdAt, *grp.CreationTime)
+	assert.Equal(t, "deleter", grp.Deleter)
+	assert.Equal(t, deletedAt, *grp.DeletionTime)
+	assert.True(t, grp.IsDeleted())
+
+	t.Run("Merge History", func(t *testing.T) {
+		tgt := &SecurityGroup{
+			SecurityGroup: &types.SecurityGroup{GroupId: aws.String("sg-1")},
+		}
+		err := tgt.mergeFields(*grp)
+		require.NoError(t, err)
+		assert.Equal(t, grp.History, tgt.History)
+		assert.Equal(t, "deleter", tgt.Deleter)
+	})
+}
+
 func TestMergeFields(t *testing.T) {
 
 	expectedID := "1234"
@@ -388,7 +467,7 @@ func TestMergeFields(t *testing.T) {
 		assert.True(t, tgt.CreationTime.Compare(time.Time{}) == 0)
 	})
 
-	t.Run("Not the same obj", func(t *testing.T) {
+	t.Run("Not the same name", func(t *testing.T) {
 		expectedID := "1234"
 		expectedID2 := "1234"
 		expectedName := "Name"
@@ -410,6 +489,23 @@ func TestMergeFields(t *testing.T) {
 		require.EqualError(t, err, fmt.Sprintf("error mergin SecurityGroups: %v != %v", expectedName, expectedName2))
 	})
 
+	t.Run("Not the same ID", func(t *testing.T) {
+		src := SecurityGroup{
+			SecurityGroup: &types.SecurityGroup{
+				GroupId:   aws.String("sg-1"),
+				GroupName: aws.String("default"),
+			},
+		}
+		tgt := &SecurityGroup{
+			SecurityGroup: &types.SecurityGroup{
+				GroupId:   aws.String("sg-2"),
+				GroupName: aws.String("default"),
+			},
+		}
+		err := tgt.mergeFields(src)
+		require.EqualError(t, err, "error mergin SecurityGroups: sg-1 != sg-2")
+	})
+
 	t.Run("No SecurityGroup Details", func(t *testing.T) {
 		src := SecurityGroup{}
 		tgt := &SecurityGroup{}
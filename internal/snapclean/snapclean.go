@@ -0,0 +1,160 @@
+package snapclean
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/steffakasid/awsclean/internal"
+	eslog "github.com/steffakasid/eslog"
+)
+
+type SnapClean struct {
+	awsClient       *internal.AWS
+	olderthen       time.Duration
+	dryrun          bool
+	onlyUnused      bool
+	ignorePatterns  []string
+	usedSnapshots   []types.Snapshot
+	unusedSnapshots []types.Snapshot
+}
+
+func NewInstance(awsClient *internal.AWS, olderthen time.Duration, dryrun bool, onlyUnused bool, ignorePatterns []string) *SnapClean {
+	return &SnapClean{
+		awsClient:       awsClient,
+		olderthen:       olderthen,
+		dryrun:          dryrun,
+		onlyUnused:      onlyUnused,
+		ignorePatterns:  ignorePatterns,
+		usedSnapshots:   []types.Snapshot{},
+		unusedSnapshots: []types.Snapshot{},
+	}
+}
+
+// GetSnapshots fetches all own snapshots and sorts them into used and unused ones. A snapshot is
+// unused if its source volume doesn't exist anymore and no AMI references it.
+func (s *SnapClean) GetSnapshots() error {
+	volumes, err := s.awsClient.GetEBSVolumes()
+	if err != nil {
+		return fmt.Errorf("could not get EBS volumes: %w", err)
+	}
+	existingVolumes := []string{}
+	for _, volume := range volumes {
+		existingVolumes = append(existingVolumes, *volume.VolumeId)
+	}
+
+	// disabled AMIs still exist and keep their snapshots
+	images, err := s.awsClient.DescribeImages("", true)
+	if err != nil {
+		return fmt.Errorf("could not get AMIs: %w", err)
+	}
+	snapshotsOfImages := []string{}
+	for _, image := range images {
+		for _, mapping := range image.BlockDeviceMappings {
+			if mapping.Ebs != nil && mapping.Ebs.SnapshotId != nil {
+				snapshotsOfImages = internal.UniqueAppend(snapshotsOfImages, *mapping.Ebs.SnapshotId)
+			}
+		}
+	}
+
+	snapshots, err := s.awsClient.GetOwnSnapshots()
+	if err != nil {
+		return fmt.Errorf("could not get snapshots: %w", err)
+	}
+
+	for _, snapshot := range snapshots {
+		if internal.Contains(snapshotsOfImages, *snapshot.SnapshotId) {
+			eslog.Logger.Infof("Used by AMI: %s", *snapshot.SnapshotId)
+			s.usedSnapshots = append(s.usedSnapshots, snapshot)
+		} else if snapshot.VolumeId != nil && internal.Contains(existingVolumes, *snapshot.VolumeId) {
+			eslog.Logger.Infof("Volume %s still exists: %s", *snapshot.VolumeId, *snapshot.SnapshotId)
+			s.usedSnapshots = append(s.usedSnapshots, snapshot)
+		} else {
+			s.unusedSnapshots = append(s.unusedSnapshots, snapshot)
+		}
+	}
+	return nil
+}
+
+func (s SnapClean) GetAllSnapshots() []types.Snapshot {
+	all := []types.Snapshot{}
+
+	all = append(all, s.unusedSnapshots...)
+	if !s.onlyUnused {
+		all = append(all, s.usedSnapshots...)
+	}
+
+	return all
+}
+
+func (s SnapClean) DeleteOrphanedSnapshots() error {
+	err := s.GetSnapshots()
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	skipped := 0
+
+	olderThenDate := time.Now().Add(s.olderthen * -1)
+	eslog.Logger.Debugf("OlderThenDate %v", olderThenDate)
+
+	for _, snapshot := range s.unusedSnapshots {
+		ignored, err := s.isIgnored(snapshot)
+		if err != nil {
+			return err
+		}
+
+		if ignored {
+			eslog.Logger.Infof("Skipping %s as it matches an ignore pattern", *snapshot.SnapshotId)
+			skipped++
+		} else if snapshot.StartTime == nil {
+			eslog.Logger.Infof("Keeping %s as it's start time is unknown", *snapshot.SnapshotId)
+			skipped++
+		} else if snapshot.StartTime.Before(olderThenDate) {
+			eslog.Logger.Infof("Delete %s as it's start time %s is older then %s", *snapshot.SnapshotId, snapshot.StartTime.Format(time.RFC3339), olderThenDate.Format(time.RFC3339))
+			err := s.awsClient.DeleteSnapshot(*snapshot.SnapshotId, s.dryrun)
+			if err != nil && !internal.IsDryRunOperation(err) {
+				eslog.LogIfErrorf(err, eslog.Errorf, "Error on DeleteSnapshot(): %s")
+				skipped++
+				continue
+			}
+			deleted++
+		} else {
+			eslog.Logger.Infof("Keeping %s as it's start time %s is newer then %s", *snapshot.SnapshotId, snapshot.StartTime.Format(time.RFC3339), olderThenDate.Format(time.RFC3339))
+			skipped++
+		}
+	}
+
+	eslog.Logger.Infof("Deleted %d, Skipped %d EBS snapshots", deleted, skipped)
+	return nil
+}
+
+// isIgnored matches the ignore patterns against the snapshot ID, the description and the Name tag.
+func (s SnapClean) isIgnored(snapshot types.Snapshot) (bool, error) {
+	candidates := []string{*snapshot.SnapshotId}
+	if snapshot.Description != nil {
+		candidates = append(candidates, *snapshot.Description)
+	}
+	if name := SnapshotName(snapshot); name != "" {
+		candidates = append(candidates, name)
+	}
+
+	for _, candidate := range candidates {
+		ok, err := internal.MatchAny(candidate, s.ignorePatterns)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// SnapshotName returns the value of the Name tag or an empty string if the snapshot has none.
+func SnapshotName(snapshot types.Snapshot) string {
+	for _, tag := range snapshot.Tags {
+		if tag.Key != nil && *tag.Key == "Name" && tag.Value != nil {
+			return *tag.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,174 @@
+package snapclean
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/steffakasid/awsclean/internal"
+	"github.com/steffakasid/awsclean/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+func setupSUT(t *testing.T, dryrun, onlyUnused bool, ignorePatterns []string) (*SnapClean, *mocks.MockEc2client) {
+	olderthen, err := str2duration.ParseDuration("7d")
+	require.NoError(t, err)
+
+	ec2ClientMock := mocks.NewMockEc2client(t)
+	awsClient := internal.NewFromInterface(ec2ClientMock, mocks.NewMockCloudTrail(t))
+	return NewInstance(awsClient, olderthen, dryrun, onlyUnused, ignorePatterns), ec2ClientMock
+}
+
+func TestGetSnapshots(t *testing.T) {
+
+	t.Run("Success", func(t *testing.T) {
+		SUT, ec2Mock := setupSUT(t, false, false, nil)
+
+		mockDescribeVolumes(ec2Mock, "vol-exists")
+		mockDescribeImages(ec2Mock, "snap-of-ami")
+		mockDescribeSnapshots(ec2Mock, time.Now(),
+			types.Snapshot{SnapshotId: aws.String("snap-of-ami"), VolumeId: aws.String("vol-gone")},
+			types.Snapshot{SnapshotId: aws.String("snap-of-volume"), VolumeId: aws.String("vol-exists")},
+			types.Snapshot{SnapshotId: aws.String("snap-orphaned"), VolumeId: aws.String("vol-gone")},
+		)
+
+		err := SUT.GetSnapshots()
+		require.NoError(t, err)
+		assert.Len(t, SUT.usedSnapshots, 2)
+		require.Len(t, SUT.unusedSnapshots, 1)
+		assert.Equal(t, "snap-orphaned", *SUT.unusedSnapshots[0].SnapshotId)
+		assert.Len(t, SUT.GetAllSnapshots(), 3)
+	})
+
+	t.Run("Only Unused", func(t *testing.T) {
+		SUT, ec2Mock := setupSUT(t, false, true, nil)
+
+		mockDescribeVolumes(ec2Mock, "vol-exists")
+		mockDescribeImages(ec2Mock)
+		mockDescribeSnapshots(ec2Mock, time.Now(),
+			types.Snapshot{SnapshotId: aws.String("snap-of-volume"), VolumeId: aws.String("vol-exists")},
+			types.Snapshot{SnapshotId: aws.String("snap-orphaned"), VolumeId: aws.String("vol-gone")},
+		)
+
+		err := SUT.GetSnapshots()
+		require.NoError(t, err)
+		assert.Len(t, SUT.GetAllSnapshots(), 1)
+	})
+
+	t.Run("Error DescribeVolumes", func(t *testing.T) {
+		SUT, ec2Mock := setupSUT(t, false, false, nil)
+
+		ec2Mock.EXPECT().DescribeVolumes(context.TODO(), &ec2.DescribeVolumesInput{}).Return(nil, errors.New("some error")).Once()
+
+		err := SUT.GetSnapshots()
+		require.EqualError(t, err, "could not get EBS volumes: some error")
+		assert.Len(t, SUT.GetAllSnapshots(), 0)
+	})
+}
+
+func TestDeleteOrphanedSnapshots(t *testing.T) {
+	eightDaysAgo := time.Now().Add(8 * 24 * time.Hour * -1)
+
+	t.Run("Success", func(t *testing.T) {
+		SUT, ec2Mock := setupSUT(t, false, true, []string{"^keep-.*"})
+
+		mockDescribeVolumes(ec2Mock, "vol-exists")
+		mockDescribeImages(ec2Mock)
+		mockDescribeSnapshots(ec2Mock, eightDaysAgo,
+			types.Snapshot{SnapshotId: aws.String("snap-of-volume"), VolumeId: aws.String("vol-exists")},
+			types.Snapshot{SnapshotId: aws.String("snap-orphaned"), VolumeId: aws.String("vol-gone")},
+			types.Snapshot{SnapshotId: aws.String("snap-ignored"), VolumeId: aws.String("vol-gone"), Tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String("keep-me")}}},
+			types.Snapshot{SnapshotId: aws.String("snap-too-young"), VolumeId: aws.String("vol-gone"), StartTime: aws.Time(time.Now())},
+		)
+
+		expectedDeleteIn := &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-orphaned"), DryRun: aws.Bool(false)}
+		ec2Mock.EXPECT().DeleteSnapshot(context.TODO(), expectedDeleteIn).Return(&ec2.DeleteSnapshotOutput{}, nil).Once()
+
+		err := SUT.DeleteOrphanedSnapshots()
+		require.NoError(t, err)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		SUT, ec2Mock := setupSUT(t, true, true, nil)
+
+		mockDescribeVolumes(ec2Mock)
+		mockDescribeImages(ec2Mock)
+		mockDescribeSnapshots(ec2Mock, eightDaysAgo,
+			types.Snapshot{SnapshotId: aws.String("snap-orphaned"), VolumeId: aws.String("vol-gone")},
+		)
+
+		expectedDeleteIn := &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-orphaned"), DryRun: aws.Bool(true)}
+		ec2Mock.EXPECT().DeleteSnapshot(context.TODO(), expectedDeleteIn).Return(nil, &smithy.GenericAPIError{Code: "DryRunOperation"}).Once()
+
+		err := SUT.DeleteOrphanedSnapshots()
+		require.NoError(t, err)
+	})
+
+	t.Run("Delete Failed", func(t *testing.T) {
+		SUT, ec2Mock := setupSUT(t, false, true, nil)
+
+		mockDescribeVolumes(ec2Mock)
+		mockDescribeImages(ec2Mock)
+		mockDescribeSnapshots(ec2Mock, eightDaysAgo,
+			types.Snapshot{SnapshotId: aws.String("snap-in-use"), VolumeId: aws.String("vol-gone")},
+			types.Snapshot{SnapshotId: aws.String("snap-orphaned"), VolumeId: aws.String("vol-gone")},
+		)
+
+		ec2Mock.EXPECT().DeleteSnapshot(context.TODO(), &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-in-use"), DryRun: aws.Bool(false)}).Return(nil, errors.New("InvalidSnapshot.InUse")).Once()
+		ec2Mock.EXPECT().DeleteSnapshot(context.TODO(), &ec2.DeleteSnapshotInput{SnapshotId: aws.String("snap-orphaned"), DryRun: aws.Bool(false)}).Return(&ec2.DeleteSnapshotOutput{}, nil).Once()
+
+		err := SUT.DeleteOrphanedSnapshots()
+		require.NoError(t, err)
+	})
+
+	t.Run("Without Start Time", func(t *testing.T) {
+		SUT, ec2Mock := setupSUT(t, false, true, nil)
+
+		mockDescribeVolumes(ec2Mock)
+		mockDescribeImages(ec2Mock)
+		ec2Mock.EXPECT().DescribeSnapshots(context.TODO(), &ec2.DescribeSnapshotsInput{OwnerIds: []string{"self"}}).Return(&ec2.DescribeSnapshotsOutput{
+			Snapshots: []types.Snapshot{{SnapshotId: aws.String("snap-orphaned"), VolumeId: aws.String("vol-gone")}},
+		}, nil).Once()
+
+		err := SUT.DeleteOrphanedSnapshots()
+		require.NoError(t, err)
+		ec2Mock.AssertNotCalled(t, "DeleteSnapshot", context.TODO(), mock.Anything)
+	})
+}
+
+func mockDescribeVolumes(ec2Mock *mocks.MockEc2client, volumeIDs ...string) {
+	out := &ec2.DescribeVolumesOutput{}
+	for _, volumeID := range volumeIDs {
+		out.Volumes = append(out.Volumes, types.Volume{VolumeId: aws.String(volumeID)})
+	}
+	ec2Mock.EXPECT().DescribeVolumes(context.TODO(), &ec2.DescribeVolumesInput{}).Return(out, nil).Once()
+}
+
+func mockDescribeImages(ec2Mock *mocks.MockEc2client, snapshotIDs ...string) {
+	image := types.Image{ImageId: aws.String("ami-1234")}
+	for _, snapshotID := range snapshotIDs {
+		image.BlockDeviceMappings = append(image.BlockDeviceMappings, types.BlockDeviceMapping{
+			Ebs: &types.EbsBlockDevice{SnapshotId: aws.String(snapshotID)},
+		})
+	}
+	out := &ec2.DescribeImagesOutput{Images: []types.Image{image}}
+	ec2Mock.EXPECT().DescribeImages(context.TODO(), &ec2.DescribeImagesInput{Owners: []string{"self"}, IncludeDisabled: aws.Bool(true)}).Return(out, nil).Once()
+}
+
+func mockDescribeSnapshots(ec2Mock *mocks.MockEc2client, startTime time.Time, snapshots ...types.Snapshot) {
+	for i := range snapshots {
+		if snapshots[i].StartTime == nil {
+			snapshots[i].StartTime = aws.Time(startTime)
+		}
+	}
+	out := &ec2.DescribeSnapshotsOutput{Snapshots: snapshots}
+	ec2Mock.EXPECT().DescribeSnapshots(context.TODO(), &ec2.DescribeSnapshotsInput{OwnerIds: []string{"self"}}).Return(out, nil).Once()
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/steffakasid/eslog"
+)
+
+// CLOUDTRAIL_TPS is the limit of LookupEvents calls per second per account and region.
+const CLOUDTRAIL_TPS = 2
+
+// DESCRIBE_LOG_STREAMS_TPS is the limit of DescribeLogStreams calls per second per account and region.
+const DESCRIBE_LOG_STREAMS_TPS = 25
+
+// rateLimiter spaces calls evenly to not exceed the given calls per second.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(tps int) *rateLimiter {
+	return &rateLimiter{interval: time.Second / time.Duration(tps)}
+}
+
+// Wait blocks until the next call is allowed.
+func (l *rateLimiter) Wait() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	wait := l.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	l.next = now.Add(wait + l.interval)
+	l.mu.Unlock()
+
+	time.Sleep(wait)
+}
+
+// backoff defines the exponential backoff with full jitter used to retry throttled calls.
+type backoff struct {
+	base    time.Duration
+	max     time.Duration
+	retries int
+}
+
+var defaultBackoff = backoff{base: time.Second, max: 30 * time.Second, retries: 5}
+
+// delay returns a random delay for the given attempt, starting with 0, which is at most base * 2^attempt.
+func (b backoff) delay(attempt int) time.Duration {
+	ceiling := b.max
+	if attempt < 32 && b.base<<attempt < b.max {
+		ceiling = b.base << attempt
+	}
+	return rand.N(ceiling) + 1
+}
+
+// isThrottlingError returns true if err is caused by exceeding a rate limit, e.g. a ThrottlingException.
+func isThrottlingError(err error) bool {
+	return retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err).Bool()
+}
+
+// callThrottled calls call within the rate limit of limiter, a nil limiter doesn't limit the calls. Throttled calls are retried with exponential
+// backoff, name is the API call used in the log.
+func callThrottled[T any](name string, limiter *rateLimiter, b backoff, call func() (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		limiter.Wait()
+		out, err := call()
+		if err == nil || !isThrottlingError(err) || attempt >= b.retries {
+			return out, err
+		}
+
+		delay := b.delay(attempt)
+		eslog.Logger.Warnf("%s() throttled, retrying in %s: %s", name, delay, err)
+		time.Sleep(delay)
+	}
+}
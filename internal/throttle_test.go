@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	SUT := newRateLimiter(20)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		SUT.Wait()
+	}
+	// the first call doesn't wait
+	assert.GreaterOrEqual(t, time.Since(start), 4*50*time.Millisecond)
+
+	t.Run("Nil", func(t *testing.T) {
+		var SUT *rateLimiter
+		SUT.Wait()
+	})
+}
+
+func TestWithRateLimits(t *testing.T) {
+	SUT := NewFromInterface(nil, nil)
+	assert.Nil(t, SUT.cloudTrailLimiter)
+	assert.Nil(t, SUT.logStreamsLimiter)
+
+	WithRateLimits()(SUT)
+	assert.Equal(t, time.Second/CLOUDTRAIL_TPS, SUT.cloudTrailLimiter.interval)
+	assert.Equal(t, time.Second/DESCRIBE_LOG_STREAMS_TPS, SUT.logStreamsLimiter.interval)
+}
+
+func TestBackoffDelay(t *testing.T) {
+	SUT := backoff{base: 10 * time.Millisecond, max: 50 * time.Millisecond, retries: 5}
+
+	for attempt, ceiling := range []time.Duration{10, 20, 40, 50, 50, 50} {
+		for i := 0; i < 20; i++ {
+			delay := SUT.delay(attempt)
+			assert.Greater(t, delay, time.Duration(0))
+			assert.LessOrEqual(t, delay, ceiling*time.Millisecond)
+		}
+	}
+	assert.LessOrEqual(t, SUT.delay(100), SUT.max)
+}
+
+func TestIsThrottlingError(t *testing.T) {
+	assert.True(t, isThrottlingError(&smithy.GenericAPIError{Code: "ThrottlingException"}))
+	assert.True(t, isThrottlingError(&smithy.GenericAPIError{Code: "RequestLimitExceeded"}))
+	assert.False(t, isThrottlingError(&smithy.GenericAPIError{Code: "AccessDeniedException"}))
+	assert.False(t, isThrottlingError(errors.New("something went wrong")))
+}